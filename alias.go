@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// JiraAliasEnvVar holds user-defined Jira base URL aliases, as comma-separated
+// name=url pairs, e.g. "work=https://jira.example.com,oss=https://issues.example.org"
+const JiraAliasEnvVar = "JIG_JIRA_ALIASES"
+
+// builtInJiraAliases are the --jira-base-url shortcuts available without configuration
+var builtInJiraAliases = map[string]string{
+	"redhat": "https://issues.redhat.com",
+	"apache": "https://issues.apache.org/jira",
+}
+
+// jiraBaseURLAlias is the alias --jira-base-url was given as, if any, for display
+var jiraBaseURLAlias string
+
+// resolveJiraBaseURL expands an alias given as --jira-base-url into its URL before
+// any client is created; it runs before every command
+func resolveJiraBaseURL(cmd *cobra.Command, args []string) {
+	aliases, err := jiraAliases(baseURLAliases, os.Getenv(JiraAliasEnvVar))
+	if err != nil {
+		color.Red("❌ Invalid Jira alias: %v", err)
+		os.Exit(1)
+	}
+
+	url, ok := expandJiraAlias(jiraBaseURL, aliases)
+	switch {
+	case ok:
+		jiraBaseURLAlias, jiraBaseURL = jiraBaseURL, url
+	case !strings.Contains(jiraBaseURL, "://"):
+		color.Red("❌ Invalid --jira-base-url %q: not a URL or a known alias (%s)", jiraBaseURL, strings.Join(jiraAliasNames(aliases), ", "))
+		os.Exit(1)
+	}
+}
+
+// jiraAliases combines the built-in aliases with those from the JIG_JIRA_ALIASES
+// environment variable and --base-url-alias flags, later definitions winning
+func jiraAliases(flags []string, env string) (map[string]string, error) {
+	aliases := make(map[string]string, len(builtInJiraAliases))
+	for name, url := range builtInJiraAliases {
+		aliases[name] = url
+	}
+
+	var defs []string
+	for _, def := range strings.Split(env, ",") {
+		if strings.TrimSpace(def) != "" {
+			defs = append(defs, def)
+		}
+	}
+	for _, def := range append(defs, flags...) {
+		name, url, ok := strings.Cut(def, "=")
+		name, url = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(url)
+		if !ok || name == "" || url == "" {
+			return nil, fmt.Errorf("%q: expected name=url", def)
+		}
+		if strings.Contains(name, "://") {
+			return nil, fmt.Errorf("%q: alias names can't be URLs", def)
+		}
+		aliases[name] = strings.TrimRight(url, "/")
+	}
+	return aliases, nil
+}
+
+// expandJiraAlias returns the URL for value if it is an alias name. URLs and
+// unknown names are left for the caller, reported by ok being false.
+func expandJiraAlias(value string, aliases map[string]string) (url string, ok bool) {
+	if strings.Contains(value, "://") {
+		return "", false
+	}
+	url, ok = aliases[strings.ToLower(strings.TrimSpace(value))]
+	return url, ok
+}
+
+// jiraAliasNames returns the alias names in sorted order, for error messages
+func jiraAliasNames(aliases map[string]string) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
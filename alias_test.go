@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestResolveJiraBaseURL(t *testing.T) {
+	oldURL, oldAlias, oldFlags := jiraBaseURL, jiraBaseURLAlias, baseURLAliases
+	t.Cleanup(func() { jiraBaseURL, jiraBaseURLAlias, baseURLAliases = oldURL, oldAlias, oldFlags })
+
+	tests := []struct {
+		name      string
+		value     string
+		flags     []string
+		env       string
+		want      string
+		wantAlias string
+	}{
+		{name: "built-in alias", value: "redhat", want: "https://issues.redhat.com", wantAlias: "redhat"},
+		{name: "alias case and spaces", value: " Apache ", want: "https://issues.apache.org/jira", wantAlias: " Apache "},
+		{name: "full URL unchanged", value: "https://jira.example.com/jira", want: "https://jira.example.com/jira"},
+		{name: "URL matching an alias name unchanged", value: "https://redhat", flags: []string{"redhat=https://other.example.com"}, want: "https://redhat"},
+		{name: "environment alias", value: "work", env: "work=https://jira.example.com/, oss=https://issues.example.org", want: "https://jira.example.com", wantAlias: "work"},
+		{name: "flag overrides environment and built-in", value: "redhat", flags: []string{"redhat=https://staging.example.com"}, env: "redhat=https://env.example.com", want: "https://staging.example.com", wantAlias: "redhat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(JiraAliasEnvVar, tt.env)
+			jiraBaseURL, jiraBaseURLAlias, baseURLAliases = tt.value, "", tt.flags
+
+			resolveJiraBaseURL(nil, nil)
+
+			if jiraBaseURL != tt.want {
+				t.Errorf("jiraBaseURL = %q, want %q", jiraBaseURL, tt.want)
+			}
+			if jiraBaseURLAlias != tt.wantAlias {
+				t.Errorf("jiraBaseURLAlias = %q, want %q", jiraBaseURLAlias, tt.wantAlias)
+			}
+		})
+	}
+}
+
+func TestJiraAliasesInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []string
+		env   string
+	}{
+		{name: "missing URL", flags: []string{"work"}},
+		{name: "empty name", flags: []string{"=https://jira.example.com"}},
+		{name: "URL as name", flags: []string{"https://a.example.com=https://b.example.com"}},
+		{name: "invalid environment entry", env: "work=https://jira.example.com,oss"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jiraAliases(tt.flags, tt.env); err == nil {
+				t.Error("jiraAliases() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+// loadBrandingText resolves a --file-header or --file-footer value, which is either
+// the path of a file to read or the literal text itself
+func loadBrandingText(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if info, err := os.Stat(value); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", value, err)
+		}
+		return string(data), nil
+	}
+	return value, nil
+}
+
+// renderBranding renders a file header or footer template for a ticket, trimmed
+// and followed by a blank line so it separates cleanly from the surrounding content
+func renderBranding(name, text string, ticket *jira.Ticket) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	rendered, err := prompt.RenderString(name, text, ticket, renderOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("invalid --%s: %w", name, err)
+	}
+
+	rendered = strings.TrimSpace(rendered)
+	if rendered == "" {
+		return "", nil
+	}
+	return rendered + "\n\n", nil
+}
+
+// brandPlanContent wraps saved plan content with the configured file header and footer
+func brandPlanContent(ticket *jira.Ticket, content string) (string, error) {
+	header, err := renderBranding("file-header", fileHeaderText, ticket)
+	if err != nil {
+		return "", err
+	}
+	footer, err := renderBranding("file-footer", fileFooterText, ticket)
+	if err != nil {
+		return "", err
+	}
+
+	if footer != "" {
+		content = strings.TrimRight(content, "\n") + "\n\n" + strings.TrimRight(footer, "\n") + "\n"
+	}
+	return header + content, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestSavedPlanBranding(t *testing.T) {
+	footerFile := filepath.Join(t.TempDir(), "footer.md")
+	if err := os.WriteFile(footerFile, []byte("_Generated for {{.Ticket.Key}}, review before use._\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		format     string
+		header     string
+		footer     string
+		wantHeader string
+		wantFooter string
+	}{
+		{
+			name:       "markdown text and file",
+			format:     FormatMarkdown,
+			header:     "{info}Plan for {{.Summary}}{info}",
+			footer:     footerFile,
+			wantHeader: "{info}Plan for Retry uploads{info}\n\n# Implementation Plan:",
+			wantFooter: "1. Retry failed uploads\n\n_Generated for RHEL-9, review before use._\n",
+		},
+		{
+			name:       "html",
+			format:     FormatHTML,
+			header:     "Team Storage",
+			footer:     "Disclaimer for {{.Ticket.Key}}",
+			wantHeader: "<p>Team Storage</p>",
+			wantFooter: "<p>Disclaimer for RHEL-9</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldHeader, oldFooter, oldNoHeader, oldFormat := fileHeaderText, fileFooterText, noHeader, outputFormat
+			t.Cleanup(func() {
+				fileHeaderText, fileFooterText, noHeader, outputFormat = oldHeader, oldFooter, oldNoHeader, oldFormat
+			})
+			noHeader, outputFormat = false, tt.format
+			var err error
+			if fileHeaderText, err = loadBrandingText(tt.header); err != nil {
+				t.Fatal(err)
+			}
+			if fileFooterText, err = loadBrandingText(tt.footer); err != nil {
+				t.Fatal(err)
+			}
+
+			ticket := &jira.Ticket{Key: "RHEL-9", Summary: "Retry uploads"}
+			path, err := saveImplementationPlan(ticket.Key, ticket, nil, "## Steps\n\n1. Retry failed uploads\n", t.TempDir(), defaultHeaderFields)
+			if err != nil {
+				t.Fatalf("saveImplementationPlan() error = %v", err)
+			}
+
+			content := fileContent(t, path)
+			header := strings.Index(content, tt.wantHeader)
+			footer := strings.Index(content, tt.wantFooter)
+			if header < 0 || footer < 0 || header > footer {
+				t.Errorf("saved plan = %q, want the header %q before the footer %q", content, tt.wantHeader, tt.wantFooter)
+			}
+			if tt.format == FormatMarkdown && (!strings.HasPrefix(content, tt.wantHeader) || !strings.HasSuffix(content, tt.wantFooter)) {
+				t.Errorf("saved plan = %q, want it to start with the header and end with the footer", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// DefaultCacheTTL is how long cached responses are reused with --cache-responses
+const DefaultCacheTTL = 24 * time.Hour
+
+// DefaultTemperature is the sampling temperature plans are generated with, the
+// API's own default
+const DefaultTemperature = 1.0
+
+// responseCache stores Claude responses on disk keyed by a hash of the full request
+// (model, prompt, max tokens, temperature and any other parameters), so repeating an
+// identical request within the TTL reuses the earlier response. A nil cache never
+// hits and discards writes. The clock is injectable so expiry is deterministic in tests.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// newResponseCache creates a cache in dir whose entries expire after ttl
+func newResponseCache(dir string, ttl time.Duration, now func() time.Time) *responseCache {
+	return &responseCache{dir: dir, ttl: ttl, now: now}
+}
+
+// cacheable reports whether the response to a request may be stored. Only
+// requests sampled at temperature 0 are, unless force is set: at higher
+// temperatures each run is expected to produce a different plan.
+func cacheable(params anthropic.MessageNewParams, force bool) bool {
+	return force || (params.Temperature.Valid() && params.Temperature.Value == 0)
+}
+
+// defaultCacheDir returns the per-user cache directory for responses
+func defaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jig", "responses"), nil
+}
+
+// key hashes the request parameters into a cache key
+func (c *responseCache) key(params anthropic.MessageNewParams) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// get returns the cached response for key, if present and not expired
+func (c *responseCache) get(key string) (*anthropic.Message, bool) {
+	if c == nil || key == "" {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, key+".json")
+	info, err := os.Stat(path)
+	if err != nil || c.now().Sub(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var message anthropic.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, false
+	}
+	return &message, true
+}
+
+// store caches the response to sent, the request that actually produced it, if
+// it is cacheable. After a retry, such as a short plan retried at a higher
+// temperature, sent differs from the original request and so does its key.
+func (c *responseCache) store(sent anthropic.MessageNewParams, message *anthropic.Message, force bool) error {
+	if !cacheable(sent, force) {
+		return nil
+	}
+	key, err := c.key(sent)
+	if err != nil {
+		return err
+	}
+	return c.put(key, message)
+}
+
+// put stores a response under key, replacing any earlier entry
+func (c *responseCache) put(key string, message *anthropic.Message) error {
+	if c == nil || key == "" {
+		return nil
+	}
+
+	data := []byte(message.RawJSON())
+	if len(data) == 0 {
+		// Accumulated streaming responses have no raw JSON
+		var err error
+		if data, err = json.Marshal(message); err != nil {
+			return fmt.Errorf("failed to encode response: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory %s: %w", c.dir, err)
+	}
+	path := filepath.Join(c.dir, key+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file %s: %w", path, err)
+	}
+	return nil
+}
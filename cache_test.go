@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestResponseCache(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	cache := newResponseCache(t.TempDir(), time.Hour, clock)
+
+	params := anthropic.MessageNewParams{
+		Model:       anthropic.Model(DefaultModel),
+		MaxTokens:   planMaxTokens,
+		Temperature: anthropic.Float(0),
+		Messages:    []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Plan RHEL-1"))},
+	}
+	other := params
+	other.Messages = []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Plan RHEL-2"))}
+
+	key, err := cache.key(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := cache.key(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key == otherKey {
+		t.Fatal("different prompts have the same cache key")
+	}
+
+	if _, ok := cache.get(key); ok {
+		t.Fatal("get() hit on an empty cache")
+	}
+	message := &anthropic.Message{ID: "msg_1", Model: anthropic.Model(DefaultModel)}
+	if err := cache.put(key, message); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		elapsed time.Duration
+		wantHit bool
+	}{
+		{"hit", key, 0, true},
+		{"miss for another request", otherKey, 0, false},
+		{"hit within TTL", key, 59 * time.Minute, true},
+		{"miss after TTL", key, 61 * time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = time.Now().Add(tt.elapsed)
+			if _, ok := cache.get(tt.key); ok != tt.wantHit {
+				t.Errorf("get() hit = %v, want %v", ok, tt.wantHit)
+			}
+		})
+	}
+}
+
+func TestNilResponseCache(t *testing.T) {
+	var cache *responseCache
+	key, err := cache.key(anthropic.MessageNewParams{})
+	if err != nil || key != "" {
+		t.Fatalf("key() = %q, %v; want no key", key, err)
+	}
+	if err := cache.put(key, &anthropic.Message{}); err != nil {
+		t.Errorf("put() error = %v", err)
+	}
+	if _, ok := cache.get(key); ok {
+		t.Error("get() hit on a nil cache")
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	tests := []struct {
+		name   string
+		params anthropic.MessageNewParams
+		force  bool
+		want   bool
+	}{
+		{"temperature 0", anthropic.MessageNewParams{Temperature: anthropic.Float(0)}, false, true},
+		{"default temperature", anthropic.MessageNewParams{Temperature: anthropic.Float(DefaultTemperature)}, false, false},
+		{"unset temperature", anthropic.MessageNewParams{}, false, false},
+		{"forced", anthropic.MessageNewParams{Temperature: anthropic.Float(0.7)}, true, true},
+	}
+	for _, tt := range tests {
+		if got := cacheable(tt.params, tt.force); got != tt.want {
+			t.Errorf("%s: cacheable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResponseCacheStoreAfterShortPlanRetry(t *testing.T) {
+	params := anthropic.MessageNewParams{
+		Model:       anthropic.Model(DefaultModel),
+		MaxTokens:   planMaxTokens,
+		Temperature: anthropic.Float(0),
+		Messages:    []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Plan RHEL-1"))},
+	}
+	message := &anthropic.Message{ID: "msg_1", Model: anthropic.Model(DefaultModel)}
+	retry := shortPlanRetryParams(params, message, "3 lines, below the minimum of 10")
+
+	tests := []struct {
+		name         string
+		sent         anthropic.MessageNewParams
+		force        bool
+		wantOriginal bool
+		wantRetry    bool
+	}{
+		{name: "original request", sent: params, wantOriginal: true},
+		{name: "retried short plan", sent: retry},
+		{name: "retried short plan forced", sent: retry, force: true, wantRetry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newResponseCache(t.TempDir(), time.Hour, time.Now)
+			if err := cache.store(tt.sent, message, tt.force); err != nil {
+				t.Fatalf("store() error = %v", err)
+			}
+
+			for _, check := range []struct {
+				label  string
+				params anthropic.MessageNewParams
+				want   bool
+			}{
+				{"original", params, tt.wantOriginal},
+				{"retry", retry, tt.wantRetry},
+			} {
+				key, err := cache.key(check.params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, statErr := os.Stat(filepath.Join(cache.dir, key+".json"))
+				if stored := statErr == nil; stored != check.want {
+					t.Errorf("stored under the %s key = %v, want %v", check.label, stored, check.want)
+				}
+			}
+		})
+	}
+}
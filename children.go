@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// expandChildTickets replaces each parent with its subtasks and epic children for
+// --expand-children, dropping children already listed. A parent without children
+// is planned itself; one whose children couldn't be fetched is reported and
+// counted as a failure, as is each invalid child.
+func expandChildTickets(jiraClient *jira.Client, progress *retrySpinner, timer *phaseTimer, parents []*jira.Ticket) ([]*jira.Ticket, int) {
+	seen := make(map[string]bool)
+	var tickets []*jira.Ticket
+	failures := 0
+	add := func(ticket *jira.Ticket) {
+		if !seen[ticket.Key] {
+			seen[ticket.Key] = true
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	for _, parent := range parents {
+		progress.start(spinner.New(spinner.CharSets[35], 100*time.Millisecond), fmt.Sprintf("Fetching children of %s", parent.Key))
+		done := timer.track(parent.Key, "fetch")
+		children, err := jiraClient.GetChildTickets(parent.Key)
+		done()
+		progress.stop()
+		skipped, err := reportInvalidIssues(err)
+		failures += skipped
+		if err != nil {
+			observer.OnError(parent.Key, err)
+			color.Red("❌ Failed to fetch child tickets: %v", err)
+			failures++
+			continue
+		}
+		if len(children) == 0 && skipped == 0 {
+			color.Yellow("⚠️  Warning: %s has no subtasks or epic children; planning it instead", parent.Key)
+			add(parent)
+			continue
+		}
+
+		for _, child := range children {
+			observer.OnTicketFetched(child)
+			add(child)
+		}
+		color.Green("✅ Found %d child tickets of %s", len(children), parent.Key)
+	}
+
+	return tickets, failures
+}
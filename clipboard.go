@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// clipboardWriter copies text to the system clipboard; replace it to capture plans elsewhere
+var clipboardWriter func(text string) error = writeSystemClipboard
+
+// clipboardCommands lists the clipboard programs tried in order, per operating system
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip.exe"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"clip.exe"}, // WSL
+	},
+}
+
+// writeSystemClipboard pipes text into the first available clipboard program
+func writeSystemClipboard(text string) error {
+	commands := clipboardCommands[runtime.GOOS]
+	if runtime.GOOS == "linux" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		// wl-copy fails without a Wayland session; fall through to X11 tools
+		commands = commands[1:]
+	}
+
+	for _, args := range commands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	return errors.New("no clipboard program found (install wl-copy, xclip or xsel)")
+}
+
+// copyPlanToClipboard copies a plan to the clipboard, warning instead of failing
+// when no clipboard is available (e.g. on headless systems)
+func copyPlanToClipboard(plan string) {
+	if err := clipboardWriter(plan); err != nil {
+		color.Yellow("⚠️  Warning: Failed to copy the plan to the clipboard: %v", err)
+		return
+	}
+	color.Green("📋 Plan copied to the clipboard")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCopyPlanToClipboard(t *testing.T) {
+	oldWriter := clipboardWriter
+	t.Cleanup(func() { clipboardWriter = oldWriter })
+
+	tests := []struct {
+		name     string
+		writeErr error
+	}{
+		{name: "copied"},
+		{name: "headless system warns", writeErr: errors.New("no clipboard program found")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var copied []string
+			clipboardWriter = func(text string) error {
+				copied = append(copied, text)
+				return tt.writeErr
+			}
+
+			copyPlanToClipboard("# Plan\n\nDo the thing.")
+
+			if len(copied) != 1 {
+				t.Fatalf("clipboard writes = %d, want 1", len(copied))
+			}
+			if copied[0] != "# Plan\n\nDo the thing." {
+				t.Errorf("copied %q, want the plan", copied[0])
+			}
+		})
+	}
+}
+
+func TestWriteSystemClipboardNoProgram(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	if err := writeSystemClipboard("plan"); err == nil {
+		t.Error("writeSystemClipboard() error = nil, want an error without a clipboard program")
+	}
+}
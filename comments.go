@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+// DefaultSummarizeThreshold is the number of comments at which --summarize-comments
+// condenses a thread with the summary model instead of including it verbatim
+const DefaultSummarizeThreshold = 10
+
+// DefaultMaxCommentChars caps a comment thread included verbatim in the prompt
+const DefaultMaxCommentChars = 20000
+
+// commentSummaryPromptTemplate asks the summary model to condense a comment thread.
+// The ticket key, summary and formatted thread are substituted in order.
+const commentSummaryPromptTemplate = `Summarize the comment thread of Jira ticket %s (%q) for an engineer about to implement it.
+
+<comments>
+%s
+</comments>
+
+Condense the discussion into at most 8 markdown bullet points covering decisions made, agreed
+requirements or scope changes, open questions, and constraints or workarounds mentioned. Leave out
+greetings, status pings and duplicated information. Reply with the bullet points only.`
+
+// commentSummarizer condenses a ticket's formatted comment thread
+type commentSummarizer interface {
+	Summarize(ctx context.Context, ticket *jira.Ticket, thread string) (string, error)
+}
+
+// claudeSummarizer summarizes comment threads with a single Claude request
+type claudeSummarizer struct {
+	client anthropic.Client
+	model  string
+}
+
+// Summarize sends the comment thread to the summary model and returns its bullet points
+func (s *claudeSummarizer) Summarize(ctx context.Context, ticket *jira.Ticket, thread string) (string, error) {
+	message, err := s.client.Messages.New(ctx, anthropic.MessageNewParams{
+		MaxTokens: 1024,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(fmt.Sprintf(commentSummaryPromptTemplate, ticket.Key, ticket.Summary, thread))),
+		},
+		Model: anthropic.Model(s.model),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(messageText(message)), nil
+}
+
+// formatCommentThread formats comments oldest first as "Author (date): body" entries
+func formatCommentThread(comments []jira.Comment) string {
+	var thread strings.Builder
+	for _, comment := range comments {
+		if comment.Body == "" {
+			continue
+		}
+		author := comment.Author.DisplayName
+		if author == "" {
+			author = "Unknown"
+		}
+		if comment.Created.IsZero() {
+			thread.WriteString(fmt.Sprintf("%s: %s\n\n", author, comment.Body))
+		} else {
+			thread.WriteString(fmt.Sprintf("%s (%s): %s\n\n", author, comment.Created.Format("2006-01-02"), comment.Body))
+		}
+	}
+	return strings.TrimSpace(thread.String())
+}
+
+// recentCommentThread formats the most recent comments that fit in maxChars
+// characters (zero means unlimited), noting how many earlier comments were left
+// out. When even the latest comment is too long, it is cut to maxChars.
+func recentCommentThread(comments []jira.Comment, maxChars int) string {
+	thread := formatCommentThread(comments)
+	if maxChars <= 0 || utf8.RuneCountInString(thread) <= maxChars {
+		return thread
+	}
+
+	for start := 1; start < len(comments); start++ {
+		if thread := formatCommentThread(comments[start:]); utf8.RuneCountInString(thread) <= maxChars {
+			return fmt.Sprintf("[%d earlier comments omitted]\n\n%s", start, thread)
+		}
+	}
+
+	latest := []rune(formatCommentThread(comments[len(comments)-1:]))
+	return fmt.Sprintf("[%d earlier comments omitted]\n\n%s…", len(comments)-1, strings.TrimSpace(string(latest[:maxChars])))
+}
+
+// condenseComments returns the text to include in the prompt for a comment thread:
+// the most recent comments that fit in maxChars when there are fewer than
+// threshold, or otherwise the summarizer's condensed version. summarized reports
+// which one was returned.
+func condenseComments(ctx context.Context, summarizer commentSummarizer, ticket *jira.Ticket, comments []jira.Comment, threshold, maxChars int) (text string, summarized bool, err error) {
+	thread := formatCommentThread(comments)
+	if thread == "" || len(comments) < threshold || summarizer == nil {
+		return recentCommentThread(comments, maxChars), false, nil
+	}
+
+	summary, err := summarizer.Summarize(ctx, ticket, thread)
+	if err != nil {
+		return "", false, err
+	}
+	return summary, true, nil
+}
+
+// commentSummaryOptions fetches a ticket's comments and condenses them for the prompt
+// with --summarize-comments, recording the "comments" phase with timer. Failures are
+// reported as warnings and the plan is generated without the comments. In dry-run
+// mode long threads are included verbatim rather than calling the summary model.
+func commentSummaryOptions(ctx context.Context, jiraClient *jira.Client, client anthropic.Client, ticket *jira.Ticket, progress *retrySpinner, timer *phaseTimer) []prompt.RenderOption {
+	if !summarizeComments {
+		return nil
+	}
+
+	done := timer.track(ticket.Key, "comments")
+	defer done()
+
+	if showSpinners {
+		progress.start(spinner.New(spinner.CharSets[14], 100*time.Millisecond), fmt.Sprintf("Fetching comments for %s", ticket.Key))
+	}
+	comments, err := jiraClient.GetComments(ticket.Key)
+	progress.stop()
+	if err != nil {
+		color.Yellow("⚠️  Warning: Failed to fetch comments, generating the plan without them: %v", err)
+		return nil
+	}
+
+	var summarizer commentSummarizer
+	if !dryRun {
+		summarizer = &claudeSummarizer{client: client, model: summaryModel}
+	}
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Suffix = fmt.Sprintf(" 💬 Summarizing %d comments...", len(comments))
+	if showSpinners && summarizer != nil && len(comments) >= summarizeThreshold {
+		s.Start()
+	}
+	text, summarized, err := condenseComments(ctx, summarizer, ticket, comments, summarizeThreshold, maxCommentChars)
+	s.Stop()
+	if err != nil {
+		color.Yellow("⚠️  Warning: Failed to summarize comments, generating the plan without them: %v", err)
+		return nil
+	}
+
+	switch {
+	case summarized:
+		color.Green("💬 Summarized %d comments", len(comments))
+	case text != "":
+		color.Cyan("💬 Including %d comments", len(comments))
+	}
+	return []prompt.RenderOption{prompt.WithCommentSummary(text)}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// fakeSummarizer returns a fixed summary, recording the thread it was given
+type fakeSummarizer struct {
+	thread string
+}
+
+func (s *fakeSummarizer) Summarize(ctx context.Context, ticket *jira.Ticket, thread string) (string, error) {
+	s.thread = thread
+	return "- Agreed to retry uploads", nil
+}
+
+// testComments returns n comments by Alice, one per day, numbered from 1
+func testComments(n int, body string) []jira.Comment {
+	comments := make([]jira.Comment, n)
+	for i := range comments {
+		comments[i] = jira.Comment{
+			Author:  jira.User{DisplayName: "Alice"},
+			Body:    body,
+			Created: time.Date(2025, time.March, i+1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	return comments
+}
+
+func TestFormatCommentThread(t *testing.T) {
+	comments := []jira.Comment{
+		{Author: jira.User{DisplayName: "Alice"}, Body: "First", Created: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{Body: "Second"},
+		{Author: jira.User{DisplayName: "Bob"}, Body: ""},
+	}
+	want := "Alice (2025-03-01): First\n\nUnknown: Second"
+	if got := formatCommentThread(comments); got != want {
+		t.Errorf("formatCommentThread() = %q, want %q", got, want)
+	}
+}
+
+func TestCondenseComments(t *testing.T) {
+	tests := []struct {
+		name           string
+		comments       []jira.Comment
+		threshold      int
+		maxChars       int
+		wantSummarized bool
+		wantText       string
+	}{
+		{
+			name:      "below threshold verbatim",
+			comments:  testComments(2, "ok"),
+			threshold: 10,
+			wantText:  "Alice (2025-03-01): ok\n\nAlice (2025-03-02): ok",
+		},
+		{
+			name:           "at threshold summarized",
+			comments:       testComments(3, "ok"),
+			threshold:      3,
+			wantSummarized: true,
+			wantText:       "- Agreed to retry uploads",
+		},
+		{
+			name:      "capped keeps the latest comments",
+			comments:  testComments(3, "ok"),
+			threshold: 10,
+			maxChars:  50,
+			wantText:  "[1 earlier comments omitted]\n\nAlice (2025-03-02): ok\n\nAlice (2025-03-03): ok",
+		},
+		{
+			name:      "capped cuts a single long comment",
+			comments:  testComments(2, strings.Repeat("x", 100)),
+			threshold: 10,
+			maxChars:  30,
+			wantText:  "[1 earlier comments omitted]\n\nAlice (2025-03-02): xxxxxxxxxx…",
+		},
+		{
+			name:      "no comments",
+			threshold: 10,
+			maxChars:  30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summarizer := &fakeSummarizer{}
+			ticket := &jira.Ticket{Key: "RHEL-1", Summary: "Retry uploads"}
+			text, summarized, err := condenseComments(context.Background(), summarizer, ticket, tt.comments, tt.threshold, tt.maxChars)
+			if err != nil {
+				t.Fatalf("condenseComments() error = %v", err)
+			}
+			if summarized != tt.wantSummarized {
+				t.Errorf("summarized = %v, want %v", summarized, tt.wantSummarized)
+			}
+			if text != tt.wantText {
+				t.Errorf("text = %q, want %q", text, tt.wantText)
+			}
+			if summarized && summarizer.thread != formatCommentThread(tt.comments) {
+				t.Errorf("summarizer got %q, want the whole thread", summarizer.thread)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the effective configuration and where each value comes from",
+	Long: `Config prints the fully resolved configuration jig would use, after applying
+command-line flags, environment variables and defaults, with the source of each
+value. Secrets such as the Jira token and extra header values are shown as ***.`,
+	Example: `  jig config
+  jig config --region=us-central1 --template=prompts/custom.md`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		printConfig(resolveConfig(cmd.Flags().Changed, os.Getenv))
+	},
+}
+
+func init() {
+	configCmd.Flags().StringVar(&templatePath, "template", "", "Path or http(s) URL of a custom prompt template file, to check which template would be used")
+}
+
+// redacted replaces secret values in the config output
+const redacted = "***"
+
+// Configuration sources reported by jig config
+const (
+	configSourceFlag    = "flag"
+	configSourceEnv     = "env"
+	configSourceDefault = "default"
+	configSourceBuiltIn = "built-in"
+)
+
+// configSetting is a resolved configuration value and where it came from
+type configSetting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// resolveConfig returns the effective configuration. changed reports whether a
+// flag was set on the command line and getenv looks up environment variables,
+// so tests can supply their own.
+func resolveConfig(changed func(name string) bool, getenv func(string) string) []configSetting {
+	flagSetting := func(flag, value string) configSetting {
+		source := configSourceDefault
+		if changed(flag) {
+			source = configSourceFlag + " (--" + flag + ")"
+		}
+		return configSetting{Name: flag, Value: value, Source: source}
+	}
+
+	// The token flag wins over JIRA_TOKEN, as in newJiraClient
+	tokenSetting := configSetting{Name: "token", Value: "(not set)", Source: configSourceDefault}
+	switch {
+	case changed("token") && token != "":
+		tokenSetting = configSetting{Name: "token", Value: redacted, Source: configSourceFlag + " (--token)"}
+	case getenv("JIRA_TOKEN") != "":
+		tokenSetting = configSetting{Name: "token", Value: redacted, Source: configSourceEnv + " (JIRA_TOKEN)"}
+	}
+
+	var headers []string
+	for _, header := range jiraHeaders {
+		key, _, _ := strings.Cut(header, ":")
+		headers = append(headers, strings.TrimSpace(key)+": "+redacted)
+	}
+
+	path, source := prompt.ResolveTemplatePath(templatePath)
+	if source == prompt.SourceFlag {
+		source = configSourceFlag + " (--template)"
+	}
+
+	baseURLSetting := flagSetting("jira-base-url", jiraBaseURL)
+	if jiraBaseURLAlias != "" {
+		baseURLSetting.Source += ", alias " + jiraBaseURLAlias
+	}
+
+	return []configSetting{
+		baseURLSetting,
+		tokenSetting,
+		flagSetting("jira-header", strings.Join(headers, ", ")),
+		flagSetting("region", region),
+		flagSetting("project-id", projectID),
+		{Name: "model", Value: DefaultModel, Source: configSourceBuiltIn},
+		{Name: "template", Value: path, Source: source},
+		flagSetting("sprint-field", sprintField),
+		flagSetting("epic-field", epicLinkField),
+		flagSetting("custom-fields", strings.Join(customFields, ", ")),
+		flagSetting("max-attempts", strconv.Itoa(maxAttempts)),
+		flagSetting("max-retry-elapsed", maxRetryWait.String()),
+		flagSetting("fetch-user-timezone", strconv.FormatBool(fetchTimezone)),
+		flagSetting("fetch-project", strconv.FormatBool(fetchProject)),
+		flagSetting("skip-validation", strconv.FormatBool(skipValidation)),
+	}
+}
+
+// printConfig prints resolved settings as an aligned table
+func printConfig(settings []configSetting) {
+	width := 0
+	for _, setting := range settings {
+		width = max(width, len(setting.Name))
+	}
+
+	fmt.Println()
+	printSeparator()
+	color.HiYellow("⚙️  EFFECTIVE CONFIGURATION")
+	printSeparator()
+	for _, setting := range settings {
+		value := setting.Value
+		if value == "" {
+			value = "(none)"
+		}
+		fmt.Printf("%-*s  %s  ", width, setting.Name, value)
+		color.HiBlack("[%s]", setting.Source)
+	}
+	printSeparator()
+}
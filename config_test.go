@@ -0,0 +1,55 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveConfig(t *testing.T) {
+	const secret = "s3cret-pat"
+	tests := []struct {
+		name       string
+		token      string
+		changed    []string
+		env        map[string]string
+		wantSource string
+		wantValue  string
+	}{
+		{name: "token flag", token: secret, changed: []string{"token"}, wantSource: "flag (--token)", wantValue: redacted},
+		{name: "token from env", env: map[string]string{"JIRA_TOKEN": secret}, wantSource: "env (JIRA_TOKEN)", wantValue: redacted},
+		{name: "flag wins over env", token: secret, changed: []string{"token"}, env: map[string]string{"JIRA_TOKEN": "other"}, wantSource: "flag (--token)", wantValue: redacted},
+		{name: "no token", wantSource: configSourceDefault, wantValue: "(not set)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldToken, oldHeaders, oldBaseURL := token, jiraHeaders, jiraBaseURL
+			t.Cleanup(func() { token, jiraHeaders, jiraBaseURL = oldToken, oldHeaders, oldBaseURL })
+			token = tt.token
+			jiraHeaders = []string{"X-Api-Key: " + secret}
+			jiraBaseURL = "https://jira.example.com"
+
+			changed := func(name string) bool { return slices.Contains(tt.changed, name) }
+			getenv := func(key string) string { return tt.env[key] }
+
+			settings := make(map[string]configSetting)
+			for _, setting := range resolveConfig(changed, getenv) {
+				if strings.Contains(setting.Value, secret) {
+					t.Errorf("%s = %q, want the secret redacted", setting.Name, setting.Value)
+				}
+				settings[setting.Name] = setting
+			}
+
+			if got := settings["token"]; got.Value != tt.wantValue || got.Source != tt.wantSource {
+				t.Errorf("token = %q [%s], want %q [%s]", got.Value, got.Source, tt.wantValue, tt.wantSource)
+			}
+			if got, want := settings["jira-header"].Value, "X-Api-Key: ***"; got != want {
+				t.Errorf("jira-header = %q, want %q", got, want)
+			}
+			if got := settings["jira-base-url"]; got.Value != jiraBaseURL || got.Source != configSourceDefault {
+				t.Errorf("jira-base-url = %q [%s], want %q [%s]", got.Value, got.Source, jiraBaseURL, configSourceDefault)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// thinkingFileSuffix replaces the plan file extension for the --include-thinking sidecar
+const thinkingFileSuffix = ".thinking.md"
+
+// messageText joins the text blocks of a response, one per line, skipping empty
+// blocks and blocks of other types such as tool use or extended thinking
+func messageText(message *anthropic.Message) string {
+	var text strings.Builder
+	for _, block := range message.Content {
+		if block.Type != "text" || strings.TrimSpace(block.Text) == "" {
+			continue
+		}
+		text.WriteString(block.Text)
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// messageThinking joins the extended-thinking blocks of a response, separated by
+// blank lines. Redacted thinking has no readable content and is skipped.
+func messageThinking(message *anthropic.Message) string {
+	var blocks []string
+	for _, block := range message.Content {
+		if block.Type == "thinking" && strings.TrimSpace(block.Thinking) != "" {
+			blocks = append(blocks, strings.TrimSpace(block.Thinking))
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// thinkingFilePath returns the sidecar path for a saved plan's thinking, e.g.
+// RHEL-123_20250101_120000.thinking.md next to RHEL-123_20250101_120000.md
+func thinkingFilePath(planPath string) string {
+	return strings.TrimSuffix(planPath, filepath.Ext(planPath)) + thinkingFileSuffix
+}
+
+// saveThinking writes a response's extended thinking, if any, to a sidecar file
+// next to the saved plan and returns its path, or an empty path when the response
+// has no thinking
+func saveThinking(planPath, ticketKey string, message *anthropic.Message) (string, error) {
+	thinking := messageThinking(message)
+	if thinking == "" {
+		return "", nil
+	}
+
+	path := thinkingFilePath(planPath)
+	content := fmt.Sprintf("# Extended Thinking: %s\n\n%s\n", ticketKey, thinking)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+	return path, nil
+}
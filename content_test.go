@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// mixedMessage returns a response with text, thinking, tool use and empty blocks
+func mixedMessage() *anthropic.Message {
+	return &anthropic.Message{Content: []anthropic.ContentBlockUnion{
+		{Type: "thinking", Thinking: "  Consider the retry path first.\n"},
+		{Type: "text", Text: "# Plan"},
+		{Type: "redacted_thinking", Data: "c2VjcmV0"},
+		{Type: "text", Text: "  \n"},
+		{Type: "tool_use", Name: "search", ID: "toolu_1"},
+		{Type: "thinking", Thinking: " "},
+		{Type: "thinking", Thinking: "Then the tests."},
+		{Type: "text", Text: "1. Retry uploads"},
+	}}
+}
+
+func TestMessageText(t *testing.T) {
+	if got, want := messageText(mixedMessage()), "# Plan\n1. Retry uploads\n"; got != want {
+		t.Errorf("messageText() = %q, want %q", got, want)
+	}
+	if got := messageText(&anthropic.Message{}); got != "" {
+		t.Errorf("messageText() of an empty response = %q, want empty", got)
+	}
+}
+
+func TestMessageThinking(t *testing.T) {
+	if got, want := messageThinking(mixedMessage()), "Consider the retry path first.\n\nThen the tests."; got != want {
+		t.Errorf("messageThinking() = %q, want %q", got, want)
+	}
+	if got := messageThinking(textMessage("# Plan")); got != "" {
+		t.Errorf("messageThinking() without thinking = %q, want empty", got)
+	}
+}
+
+func TestSaveThinking(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "RHEL-1_20250101_120000.md")
+
+	path, err := saveThinking(planPath, "RHEL-1", mixedMessage())
+	if err != nil {
+		t.Fatalf("saveThinking() error = %v", err)
+	}
+	if want := filepath.Join(filepath.Dir(planPath), "RHEL-1_20250101_120000.thinking.md"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# Extended Thinking: RHEL-1\n\nConsider the retry path first.\n\nThen the tests.\n"; string(content) != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
+
+func TestSaveThinkingWithoutThinking(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "RHEL-1.md")
+
+	path, err := saveThinking(planPath, "RHEL-1", textMessage("# Plan"))
+	if err != nil || path != "" {
+		t.Fatalf("saveThinking() = %q, %v; want no file", path, err)
+	}
+	if _, err := os.Stat(thinkingFilePath(planPath)); !os.IsNotExist(err) {
+		t.Errorf("thinking file exists, want none: %v", err)
+	}
+}
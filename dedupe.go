@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// planDeduper tracks the plans generated during a run by a hash of their body, so
+// identical plans for similar tickets can be saved as stubs pointing at the first.
+// A nil deduper treats every plan as unique.
+type planDeduper struct {
+	seen map[string]savedPlan
+}
+
+// newPlanDeduper creates an empty plan deduper
+func newPlanDeduper() *planDeduper {
+	return &planDeduper{seen: make(map[string]savedPlan)}
+}
+
+// duplicateOf returns the earlier plan with the same body, or nil if the plan is new
+func (d *planDeduper) duplicateOf(plan string) *savedPlan {
+	if d == nil {
+		return nil
+	}
+	if first, ok := d.seen[hashPlanBody(plan)]; ok {
+		return &first
+	}
+	return nil
+}
+
+// record remembers a saved plan's body so later identical plans are detected
+func (d *planDeduper) record(plan string, saved savedPlan) {
+	if d == nil {
+		return
+	}
+	hash := hashPlanBody(plan)
+	if _, ok := d.seen[hash]; !ok {
+		d.seen[hash] = saved
+	}
+}
+
+// hashPlanBody hashes a generated plan body, excluding the metadata header, after
+// normalizing line endings and surrounding whitespace
+func hashPlanBody(plan string) string {
+	normalized := strings.TrimSpace(strings.ReplaceAll(plan, "\r\n", "\n"))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// duplicatePlanStub returns the body saved in place of a plan identical to first's,
+// linking to first's file relative to dir, the directory the stub is saved in
+func duplicatePlanStub(first *savedPlan, dir string) string {
+	file := filepath.Base(first.FilePath)
+	link, err := filepath.Rel(dir, first.FilePath)
+	if err != nil {
+		link = file
+	}
+	return fmt.Sprintf("_This plan is identical to the plan generated for %s; see [%s](%s)._\n", first.Ticket.Key, file, filepath.ToSlash(link))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestPlanDeduper(t *testing.T) {
+	dir := filepath.Join("implementation-plans", "2025-01")
+	first := savedPlan{Ticket: &jira.Ticket{Key: "RHEL-1"}, FilePath: filepath.Join(dir, "RHEL-1.md")}
+	plan := "## Steps\n\n1. Retry failed uploads\n"
+
+	dedupe := newPlanDeduper()
+	if got := dedupe.duplicateOf(plan); got != nil {
+		t.Fatalf("duplicateOf() = %+v before any plan was recorded", got)
+	}
+	dedupe.record(plan, first)
+
+	tests := []struct {
+		name string
+		plan string
+		want string
+	}{
+		{name: "identical", plan: plan, want: "RHEL-1"},
+		{name: "different line endings and whitespace", plan: "\n## Steps\r\n\r\n1. Retry failed uploads  \r\n", want: "RHEL-1"},
+		{name: "different plan", plan: "## Steps\n\n1. Log retries\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupe.duplicateOf(tt.plan)
+			if gotKey := keyOf(got); gotKey != tt.want {
+				t.Errorf("duplicateOf() = %q, want %q", gotKey, tt.want)
+			}
+		})
+	}
+
+	// A later identical plan doesn't replace the first one recorded
+	dedupe.record(plan, savedPlan{Ticket: &jira.Ticket{Key: "RHEL-2"}, FilePath: filepath.Join(dir, "RHEL-2.md")})
+	if got := keyOf(dedupe.duplicateOf(plan)); got != "RHEL-1" {
+		t.Errorf("duplicateOf() after a second record = %q, want RHEL-1", got)
+	}
+
+	stub := duplicatePlanStub(&first, dir)
+	if !strings.Contains(stub, "RHEL-1") || !strings.Contains(stub, "(RHEL-1.md)") {
+		t.Errorf("duplicatePlanStub() = %q, want a relative link to RHEL-1.md", stub)
+	}
+}
+
+func TestNilPlanDeduper(t *testing.T) {
+	var dedupe *planDeduper
+	dedupe.record("plan", savedPlan{Ticket: &jira.Ticket{Key: "RHEL-1"}})
+	if got := dedupe.duplicateOf("plan"); got != nil {
+		t.Errorf("duplicateOf() = %+v for a nil deduper, want nil", got)
+	}
+}
+
+// keyOf returns the ticket key of a saved plan, or "" for nil
+func keyOf(plan *savedPlan) string {
+	if plan == nil {
+		return ""
+	}
+	return plan.Ticket.Key
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Verify Jira authentication and Vertex AI access",
+	Long: `Doctor runs a set of connectivity checks before a long batch run: it
+tests Jira authentication (when a token is configured) and verifies the
+Vertex AI credentials by issuing a minimal 1-token request to Claude.
+
+Exits with a non-zero status if any check fails.`,
+	Example: `  jig doctor
+  jig doctor --token=your_pat_here --region=us-central1 --project-id=my-project`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !runDoctor(context.Background()) {
+			os.Exit(1)
+		}
+	},
+}
+
+// errCheckSkipped marks a check that was not applicable to the current configuration
+var errCheckSkipped = fmt.Errorf("skipped")
+
+// doctorCheck is a single named connectivity check
+type doctorCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// runDoctor runs all connectivity checks and reports whether every check passed
+func runDoctor(ctx context.Context) bool {
+	checks := []doctorCheck{
+		{name: fmt.Sprintf("Jira authentication (%s)", jiraBaseURL), run: checkJira},
+		{name: fmt.Sprintf("Vertex AI access (region: %s, project: %s)", region, projectID), run: checkVertex},
+	}
+
+	fmt.Println()
+	printSeparator()
+	color.HiYellow("🩺 CONNECTIVITY CHECKS")
+	printSeparator()
+
+	passed := true
+	for _, check := range checks {
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Checking %s...", check.name)
+		s.Start()
+		err := check.run(ctx)
+		s.Stop()
+
+		switch {
+		case err == errCheckSkipped:
+			color.Yellow("⏭️  %s: skipped (no token provided, using anonymous access)", check.name)
+		case err != nil:
+			color.Red("❌ %s: %v", check.name, err)
+			passed = false
+		default:
+			color.Green("✅ %s", check.name)
+		}
+	}
+	printSeparator()
+
+	return passed
+}
+
+// checkJira tests Jira authentication when a token is configured
+func checkJira(ctx context.Context) error {
+	jiraClient := newJiraClient()
+	if token == "" {
+		return errCheckSkipped
+	}
+
+	return jiraClient.TestAuthentication()
+}
+
+// checkVertex verifies the Vertex AI credentials with a minimal 1-token request
+func checkVertex(ctx context.Context) (err error) {
+	// vertex.WithGoogleAuth panics when no default credentials can be found
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	client := newAnthropicClient(ctx)
+	_, err = client.Messages.New(ctx, anthropic.MessageNewParams{
+		MaxTokens: 1,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock("ping")),
+		},
+		Model: DefaultModel,
+	})
+	return err
+}
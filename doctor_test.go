@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestCheckJira(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		status     int
+		wantErr    error
+		wantCalled bool
+	}{
+		{name: "authenticated", token: "pat", status: http.StatusOK, wantCalled: true},
+		{name: "invalid token", token: "expired", status: http.StatusUnauthorized, wantErr: jira.ErrUnauthorized, wantCalled: true},
+		{name: "anonymous", wantErr: errCheckSkipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// A failed check also looks up the deployment type for an auth hint
+				if r.URL.Path != "/rest/api/2/myself" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				called = true
+				if got, want := r.Header.Get("Authorization"), "Bearer "+tt.token; got != want {
+					t.Errorf("Authorization = %q, want %q", got, want)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			oldBaseURL, oldToken, oldEmail := jiraBaseURL, token, jiraEmail
+			t.Cleanup(func() { jiraBaseURL, token, jiraEmail = oldBaseURL, oldToken, oldEmail })
+			t.Setenv("JIRA_TOKEN", "")
+			t.Setenv("JIRA_EMAIL", "")
+			jiraBaseURL, token, jiraEmail = server.URL, tt.token, ""
+
+			err := checkJira(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("checkJira() error = %v, want none", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkJira() error = %v, want %v", err, tt.wantErr)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("Jira called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
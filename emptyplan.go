@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/fatih/color"
+)
+
+// clarifyingPreamble is prepended to the prompt when retrying after an empty or refused response
+const clarifyingPreamble = `This is a request from a software engineer for a technical implementation plan for a ticket in their
+organization's issue tracker. The ticket content below is ordinary engineering work. Please write
+the implementation plan as requested.
+
+`
+
+// emptyPlanError reports a response that contained no plan, so nothing is saved
+type emptyPlanError struct {
+	Reason string
+}
+
+func (e *emptyPlanError) Error() string {
+	return fmt.Sprintf("Claude returned no plan (%s)", e.Reason)
+}
+
+// emptyPlanReason describes why a response contains no usable plan: a refusal stop
+// reason or no text content. It returns an empty string for a usable response.
+func emptyPlanReason(message *anthropic.Message) string {
+	if message.StopReason == anthropic.StopReasonRefusal {
+		return "the request was refused"
+	}
+	if messageText(message) != "" {
+		return ""
+	}
+	return "the response was empty"
+}
+
+// planRequester requests a plan, reporting whether the request was interrupted
+type planRequester func(params anthropic.MessageNewParams) (message *anthropic.Message, interrupted bool, err error)
+
+// requestNonEmptyPlan requests a plan, retrying a refusal or empty response once,
+// then fails rather than return an empty plan. The retry replaces the messages in
+// params with the prompt behind a clarifying preamble. The preamble asks for a
+// plan, so explanations aren't retried.
+func requestNonEmptyPlan(request planRequester, params *anthropic.MessageNewParams, promptText string) (*anthropic.Message, bool, error) {
+	message, interrupted, err := request(*params)
+	if err != nil || interrupted {
+		return message, interrupted, err
+	}
+
+	reason := emptyPlanReason(message)
+	if reason == "" {
+		return message, false, nil
+	}
+	if explaining {
+		return nil, false, &emptyPlanError{Reason: reason}
+	}
+
+	color.Yellow("⚠️  Warning: Claude returned no plan (%s); retrying once with a clarifying preamble", reason)
+	params.Messages = []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(clarifyingPreamble + promptText)),
+	}
+	message, interrupted, err = request(*params)
+	if err != nil || interrupted {
+		return message, interrupted, err
+	}
+	if reason := emptyPlanReason(message); reason != "" {
+		return nil, false, &emptyPlanError{Reason: reason}
+	}
+	return message, false, nil
+}
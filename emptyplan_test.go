@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// refusal returns a refused response
+func refusal() *anthropic.Message {
+	message := textMessage()
+	message.StopReason = anthropic.StopReasonRefusal
+	return message
+}
+
+func TestRequestNonEmptyPlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		explaining bool
+		replies    []*anthropic.Message
+		wantCalls  int
+		wantPlan   string
+		wantReason string
+	}{
+		{name: "plan", replies: []*anthropic.Message{textMessage("# Plan")}, wantCalls: 1, wantPlan: "# Plan\n"},
+		{name: "empty then plan", replies: []*anthropic.Message{textMessage(), textMessage("# Plan")}, wantCalls: 2, wantPlan: "# Plan\n"},
+		{name: "refused then plan", replies: []*anthropic.Message{refusal(), textMessage("# Plan")}, wantCalls: 2, wantPlan: "# Plan\n"},
+		{name: "empty twice", replies: []*anthropic.Message{textMessage(), textMessage("")}, wantCalls: 2, wantReason: "the response was empty"},
+		{name: "empty then refused", replies: []*anthropic.Message{textMessage(), refusal()}, wantCalls: 2, wantReason: "the request was refused"},
+		{name: "explanation isn't retried", explaining: true, replies: []*anthropic.Message{textMessage()}, wantCalls: 1, wantReason: "the response was empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldExplaining := explaining
+			t.Cleanup(func() { explaining = oldExplaining })
+			explaining = tt.explaining
+
+			var sent []anthropic.MessageNewParams
+			request := func(params anthropic.MessageNewParams) (*anthropic.Message, bool, error) {
+				sent = append(sent, params)
+				if len(sent) > len(tt.replies) {
+					t.Fatalf("unexpected request %d", len(sent))
+				}
+				return tt.replies[len(sent)-1], false, nil
+			}
+			params := anthropic.MessageNewParams{
+				Messages: []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("prompt"))},
+			}
+
+			message, interrupted, err := requestNonEmptyPlan(request, &params, "prompt")
+			if interrupted {
+				t.Error("interrupted = true, want false")
+			}
+			if len(sent) != tt.wantCalls {
+				t.Errorf("requests = %d, want %d", len(sent), tt.wantCalls)
+			}
+			if len(sent) == 2 {
+				retry := sent[1].Messages[0].Content[0].OfText.Text
+				if !strings.HasPrefix(retry, clarifyingPreamble) || !strings.HasSuffix(retry, "prompt") {
+					t.Errorf("retry prompt = %q, want the prompt behind the clarifying preamble", retry)
+				}
+			}
+
+			if tt.wantReason != "" {
+				var emptyErr *emptyPlanError
+				if !errors.As(err, &emptyErr) || emptyErr.Reason != tt.wantReason {
+					t.Fatalf("error = %v, want emptyPlanError %q", err, tt.wantReason)
+				}
+				if message != nil {
+					t.Errorf("message = %+v, want nil so no plan is saved", message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requestNonEmptyPlan() error = %v", err)
+			}
+			if got := messageText(message); got != tt.wantPlan {
+				t.Errorf("plan = %q, want %q", got, tt.wantPlan)
+			}
+		})
+	}
+}
+
+func TestRequestNonEmptyPlanRequestError(t *testing.T) {
+	want := errors.New("overloaded")
+	calls := 0
+	request := func(params anthropic.MessageNewParams) (*anthropic.Message, bool, error) {
+		calls++
+		return nil, false, want
+	}
+
+	_, _, err := requestNonEmptyPlan(request, &anthropic.MessageNewParams{}, "prompt")
+	if !errors.Is(err, want) {
+		t.Errorf("error = %v, want %v", err, want)
+	}
+	if calls != 1 {
+		t.Errorf("requests = %d, want 1", calls)
+	}
+}
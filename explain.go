@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// DefaultExplainMaxTokens is the response budget for explain, which is much shorter than a plan
+const DefaultExplainMaxTokens = 1024
+
+var explainMaxTokens int
+
+// explaining is set by the explain subcommand: generatePlan then renders the
+// embedded explain template by default and prints the result instead of saving it
+var explaining bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <TICKET_ID>",
+	Short: "Summarize a Jira ticket in plain language without planning it",
+	Long: `Explain fetches a Jira ticket and asks Claude for a short, plain-language
+summary of what it asks for, followed by the key open questions to resolve
+before starting work. It takes the same generation flags as planning, such as
+--model, --plan-language and --dry-run, and uses a built-in explain template
+unless --template is given. No implementation plan is generated or saved.`,
+	Example: `  jig explain RHEL-12345
+  jig explain --plan-language=German RHEL-12345`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := interruptContext()
+		defer stop()
+		runExplain(ctx, args[0])
+	},
+}
+
+func init() {
+	explainCmd.Flags().IntVar(&explainMaxTokens, "max-tokens", DefaultExplainMaxTokens, "Maximum tokens in the explanation")
+	addGenerationFlags(explainCmd)
+}
+
+// generatedNoun names what generatePlan produces, for progress messages
+func generatedNoun() string {
+	if explaining {
+		return "Explanation"
+	}
+	return "Implementation plan"
+}
+
+// generatedHeading is the heading printed above the generated text
+func generatedHeading() string {
+	if explaining {
+		return "💡 TICKET EXPLANATION"
+	}
+	return "🚀 IMPLEMENTATION PLAN"
+}
+
+// explainConflicts returns the generation flags that are set but have no effect on
+// explain, because they shape or save an implementation plan
+func explainConflicts() []string {
+	flags := []struct {
+		flag string
+		set  bool
+	}{
+		{"--output", outputPath != ""},
+		{"--organize-by", organizeBy != "" && organizeBy != OrganizeFlat},
+		{"--append", appendPlan},
+		{"--format", outputFormat != "" && outputFormat != FormatMarkdown},
+		{"--structured", structuredOutput},
+		{"--header-fields", len(headerFields) > 0 && !slices.Equal(headerFields, defaultHeaderFields)},
+		{"--no-header", noHeader},
+		{"--file-header", fileHeader != ""},
+		{"--file-footer", fileFooter != ""},
+		{"--self-review", selfReview},
+		{"--review-model", reviewModel != "" && reviewModel != DefaultReviewModel},
+		{"--include-thinking", includeThinking},
+		{"--add-toc", addTOC},
+		{"--min-plan-chars", minPlanChars > 0},
+		{"--min-plan-lines", minPlanLines > 0},
+		{"--clipboard", copyToClipboard},
+	}
+
+	var conflicts []string
+	for _, f := range flags {
+		if f.set {
+			conflicts = append(conflicts, f.flag)
+		}
+	}
+	return conflicts
+}
+
+func runExplain(ctx context.Context, ticketID string) {
+	explaining = true
+	maxTokens = explainMaxTokens
+	if conflicts := explainConflicts(); len(conflicts) > 0 {
+		color.Red("❌ %s cannot be used with explain, which only prints a summary", strings.Join(conflicts, ", "))
+		os.Exit(1)
+	}
+	templateFilePath := prepareGeneration()
+	timer := newPhaseTimer(time.Now)
+
+	jiraClient := newJiraClient()
+	defer jiraClient.Close()
+
+	s := spinner.New(spinner.CharSets[35], 100*time.Millisecond)
+	s.Suffix = fmt.Sprintf(" Fetching Jira ticket: %s...", ticketID)
+	s.Start()
+	done := timer.track(ticketID, "fetch")
+	ticket, err := jiraClient.GetTicket(ticketID)
+	done()
+	s.Stop()
+	if err != nil {
+		color.Red("❌ Failed to fetch ticket %s: %v", ticketID, err)
+		os.Exit(1)
+	}
+
+	var client anthropic.Client
+	if !dryRun {
+		client = newAnthropicClient(ctx)
+	}
+	if _, err := generatePlan(ctx, client, ticket, templateFilePath, timer, nil); err != nil {
+		os.Exit(1)
+	}
+	if ctx.Err() != nil {
+		color.Red("❌ Interrupted")
+		os.Exit(exitInterrupted)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+func TestGenerationTemplatePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		explaining bool
+		structured bool
+		template   string
+		want       string
+	}{
+		{name: "explain", explaining: true, want: prompt.EmbeddedExplainTemplatePath},
+		{name: "explain with --template", explaining: true, template: "prompts/custom.md", want: "prompts/custom.md"},
+		{name: "structured", structured: true, want: prompt.EmbeddedStructuredTemplatePath},
+		{name: "plan with --template", template: "prompts/custom.md", want: "prompts/custom.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldExplaining, oldStructured, oldTemplate := explaining, structuredOutput, templatePath
+			t.Cleanup(func() { explaining, structuredOutput, templatePath = oldExplaining, oldStructured, oldTemplate })
+			explaining, structuredOutput, templatePath = tt.explaining, tt.structured, tt.template
+
+			if got, _ := generationTemplatePath(); got != tt.want {
+				t.Errorf("generationTemplatePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderExplainTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []prompt.RenderOption
+		want     []string
+		wantNone []string
+	}{
+		{
+			name:     "summary and questions",
+			want:     []string{"Add retry support to the export job", "## Summary", "## Key Questions", "Do not write an implementation plan"},
+			wantNone: []string{"Respond entirely in"},
+		},
+		{
+			name: "language",
+			opts: []prompt.RenderOption{prompt.WithLanguage("German")},
+			want: []string{"Respond entirely in German"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := prompt.LoadAndRenderTemplate(prompt.EmbeddedExplainTemplatePath, prompt.SampleTicket(), tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(rendered, want) {
+					t.Errorf("explain prompt doesn't contain %q:\n%s", want, rendered)
+				}
+			}
+			for _, unwanted := range tt.wantNone {
+				if strings.Contains(rendered, unwanted) {
+					t.Errorf("explain prompt contains %q:\n%s", unwanted, rendered)
+				}
+			}
+		})
+	}
+}
+
+func TestExplainConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func()
+		want []string
+	}{
+		{name: "defaults", set: func() {}},
+		{name: "shared generation flags", set: func() { planModel, planLanguage, dryRun, streamOutput = "claude-opus-4@20250514", "German", true, true }},
+		{name: "output", set: func() { outputPath = "plan.md" }, want: []string{"--output"}},
+		{name: "html format", set: func() { outputFormat = FormatHTML }, want: []string{"--format"}},
+		{name: "custom header fields", set: func() { headerFields = []string{"key"} }, want: []string{"--header-fields"}},
+		{name: "organize by project", set: func() { organizeBy = OrganizeProject }, want: []string{"--organize-by"}},
+		{
+			name: "several save flags",
+			set:  func() { appendPlan, noHeader, fileFooter, copyToClipboard = true, true, "footer", true },
+			want: []string{"--append", "--no-header", "--file-footer", "--clipboard"},
+		},
+		{
+			name: "plan shaping flags",
+			set: func() {
+				structuredOutput, selfReview, includeThinking, addTOC, minPlanChars, minPlanLines = true, true, true, true, 200, 5
+			},
+			want: []string{"--structured", "--self-review", "--include-thinking", "--add-toc", "--min-plan-chars", "--min-plan-lines"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetExplainFlags(t)
+			tt.set()
+			if got := explainConflicts(); !slices.Equal(got, tt.want) {
+				t.Errorf("explainConflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// resetExplainFlags sets the generation flags to their defaults for the duration of a test
+func resetExplainFlags(t *testing.T) {
+	t.Helper()
+	oldOutput, oldOrganize, oldAppend, oldFormat, oldStructured := outputPath, organizeBy, appendPlan, outputFormat, structuredOutput
+	oldHeaderFields, oldNoHeader, oldFileHeader, oldFileFooter := headerFields, noHeader, fileHeader, fileFooter
+	oldSelfReview, oldReviewModel, oldThinking, oldTOC := selfReview, reviewModel, includeThinking, addTOC
+	oldMinChars, oldMinLines, oldClipboard := minPlanChars, minPlanLines, copyToClipboard
+	oldModel, oldLanguage, oldDryRun, oldStream := planModel, planLanguage, dryRun, streamOutput
+	t.Cleanup(func() {
+		outputPath, organizeBy, appendPlan, outputFormat, structuredOutput = oldOutput, oldOrganize, oldAppend, oldFormat, oldStructured
+		headerFields, noHeader, fileHeader, fileFooter = oldHeaderFields, oldNoHeader, oldFileHeader, oldFileFooter
+		selfReview, reviewModel, includeThinking, addTOC = oldSelfReview, oldReviewModel, oldThinking, oldTOC
+		minPlanChars, minPlanLines, copyToClipboard = oldMinChars, oldMinLines, oldClipboard
+		planModel, planLanguage, dryRun, streamOutput = oldModel, oldLanguage, oldDryRun, oldStream
+	})
+
+	outputPath, organizeBy, appendPlan, outputFormat, structuredOutput = "", OrganizeFlat, false, FormatMarkdown, false
+	headerFields, noHeader, fileHeader, fileFooter = defaultHeaderFields, false, "", ""
+	selfReview, reviewModel, includeThinking, addTOC = false, DefaultReviewModel, false, false
+	minPlanChars, minPlanLines, copyToClipboard = 0, 0, false
+	planModel, planLanguage, dryRun, streamOutput = DefaultModel, "", false, false
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var fileSummary string
+
+var generateFromFileCmd = &cobra.Command{
+	Use:   "generate-from-file <FILE>",
+	Short: "Generate an implementation plan from a markdown or text file instead of Jira",
+	Long: `Generate-from-file builds a minimal ticket from a local file and runs it
+through the normal prompt rendering and plan generation pipeline, without
+fetching anything from Jira.
+
+The first line of the file becomes the ticket summary (a leading markdown
+heading marker is stripped) and the rest becomes the description. Use
+--summary to provide the summary explicitly, in which case the whole file is
+used as the description.`,
+	Example: `  jig generate-from-file notes.md
+  jig generate-from-file --summary "Add rate limiting to the API" design.txt`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := interruptContext()
+		defer stop()
+		runGenerateFromFile(ctx, args[0])
+	},
+}
+
+func init() {
+	generateFromFileCmd.Flags().StringVar(&fileSummary, "summary", "", "Summary to use instead of the first line of the file")
+	addGenerationFlags(generateFromFileCmd)
+}
+
+func runGenerateFromFile(ctx context.Context, path string) {
+	templateFilePath := prepareGeneration()
+	timer := newPhaseTimer(time.Now)
+
+	ticket, err := ticketFromFile(path, fileSummary)
+	if err != nil {
+		color.Red("❌ Failed to read ticket file: %v", err)
+		os.Exit(1)
+	}
+
+	var client anthropic.Client
+	if !dryRun {
+		color.Cyan("☁️  Using Google Cloud region: %s, project: %s", region, projectID)
+		client = newAnthropicClient(ctx)
+	}
+
+	plan, err := generatePlan(ctx, client, ticket, templateFilePath, timer, nil)
+	if err != nil {
+		os.Exit(1)
+	}
+	if plan != nil {
+		writeRunManifest([]savedPlan{*plan})
+	}
+
+	if ctx.Err() != nil {
+		color.Red("❌ Interrupted")
+		os.Exit(exitInterrupted)
+	}
+
+	if !quiet {
+		printTimingSummary(timer, false)
+	}
+}
+
+// ticketFromFile maps a markdown or text file into a minimal ticket. The file name
+// (without extension) is used as the ticket key so saved plans are named after it.
+func ticketFromFile(path string, summary string) (*jira.Ticket, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return nil, fmt.Errorf("file %s is empty", path)
+	}
+
+	description := content
+	if summary == "" {
+		firstLine, rest, _ := strings.Cut(content, "\n")
+		summary = strings.TrimSpace(strings.TrimLeft(firstLine, "#"))
+		description = strings.TrimSpace(rest)
+	}
+
+	base := filepath.Base(path)
+	return &jira.Ticket{
+		Key:         strings.TrimSuffix(base, filepath.Ext(base)),
+		Summary:     summary,
+		Description: description,
+	}, nil
+}
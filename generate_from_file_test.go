@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+func TestTicketFromFilePrompt(t *testing.T) {
+	const notes = "# Add rate limiting to the API\n\nLimit each client to 100 requests per minute.\nReturn 429 when exceeded.\n"
+	tests := []struct {
+		name            string
+		summary         string
+		wantSummary     string
+		wantDescription string
+	}{
+		{
+			name:            "first line as summary",
+			wantSummary:     "Add rate limiting to the API",
+			wantDescription: "Limit each client to 100 requests per minute.\nReturn 429 when exceeded.",
+		},
+		{
+			name:            "summary override",
+			summary:         "Throttle API clients",
+			wantSummary:     "Throttle API clients",
+			wantDescription: "# Add rate limiting to the API\n\nLimit each client to 100 requests per minute.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rate-limits.md")
+			if err := os.WriteFile(path, []byte(notes), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			ticket, err := ticketFromFile(path, tt.summary)
+			if err != nil {
+				t.Fatalf("ticketFromFile() error = %v", err)
+			}
+			if ticket.Key != "rate-limits" || ticket.Summary != tt.wantSummary {
+				t.Errorf("ticket = %s %q, want rate-limits %q", ticket.Key, ticket.Summary, tt.wantSummary)
+			}
+
+			rendered, err := prompt.LoadAndRenderTemplate(prompt.EmbeddedTemplatePath, ticket)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			for _, want := range []string{tt.wantSummary, tt.wantDescription} {
+				if !strings.Contains(rendered, want) {
+					t.Errorf("rendered prompt doesn't contain %q:\n%s", want, rendered)
+				}
+			}
+		})
+	}
+}
+
+func TestTicketFromEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.md")
+	if err := os.WriteFile(path, []byte("\n  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ticketFromFile(path, ""); err == nil {
+		t.Error("ticketFromFile() succeeded for an empty file")
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// defaultHeaderFields is the ordered set of metadata fields written to saved plan headers
+var defaultHeaderFields = []string{
+	"key",
+	"generated",
+	"status",
+	"security",
+	"type",
+	"priority",
+	"assignee",
+	"reporter",
+	"components",
+	"labels",
+	"environment",
+	"model",
+	"stopreason",
+	"tokens",
+}
+
+// generationInfo records how a plan was generated, for reproducibility
+type generationInfo struct {
+	Model        string
+	StopReason   string
+	InputTokens  int64
+	OutputTokens int64
+	Interrupted  bool
+	Cached       bool
+}
+
+// newGenerationInfo extracts generation metadata from a Claude response
+func newGenerationInfo(message *anthropic.Message) *generationInfo {
+	return &generationInfo{
+		Model:        string(message.Model),
+		StopReason:   string(message.StopReason),
+		InputTokens:  message.Usage.InputTokens,
+		OutputTokens: message.Usage.OutputTokens,
+	}
+}
+
+// markdownLinkText escapes the brackets that would end a markdown link's text early
+var markdownLinkText = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+// linkedSummary returns the ticket's summary as a markdown link to the ticket,
+// or as plain text when its URL is unknown
+func linkedSummary(t *jira.Ticket) string {
+	if t.URL == "" {
+		return t.Summary
+	}
+	return fmt.Sprintf("[%s](%s)", markdownLinkText.Replace(t.Summary), t.URL)
+}
+
+// headerFieldFormatters renders the header line for each supported field name.
+// An empty result omits the line (e.g. a ticket without labels). The generation
+// info may be nil when a plan was not generated by a single Claude response.
+var headerFieldFormatters = map[string]func(ticket *jira.Ticket, gen *generationInfo) string{
+	"key": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Ticket ID:** %s", t.Key)
+	},
+	"summary": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Summary:** %s", linkedSummary(t))
+	},
+	"generated": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Generated:** %s", time.Now().Format("2006-01-02 15:04:05"))
+	},
+	"status": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Status:** %s", t.Status.Name)
+	},
+	"type": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Type:** %s", t.IssueType.Name)
+	},
+	"priority": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Priority:** %s", t.Priority.Name)
+	},
+	"assignee": func(t *jira.Ticket, gen *generationInfo) string {
+		if t.Assignee == nil {
+			return "**Assignee:** Unassigned"
+		}
+		return fmt.Sprintf("**Assignee:** %s", t.Assignee.DisplayName)
+	},
+	"reporter": func(t *jira.Ticket, gen *generationInfo) string {
+		return fmt.Sprintf("**Reporter:** %s", t.ReporterName())
+	},
+	"components": func(t *jira.Ticket, gen *generationInfo) string {
+		if len(t.Components) == 0 {
+			return ""
+		}
+		var compNames []string
+		for _, comp := range t.Components {
+			compNames = append(compNames, comp.Name)
+		}
+		return fmt.Sprintf("**Components:** %s", strings.Join(compNames, ", "))
+	},
+	"labels": func(t *jira.Ticket, gen *generationInfo) string {
+		if len(t.Labels) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("**Labels:** %s", strings.Join(t.Labels, ", "))
+	},
+	"environment": func(t *jira.Ticket, gen *generationInfo) string {
+		if t.Environment == "" {
+			return ""
+		}
+		return fmt.Sprintf("**Environment:** %s", strings.Join(strings.Fields(t.Environment), " "))
+	},
+	"model": func(t *jira.Ticket, gen *generationInfo) string {
+		if gen == nil || gen.Model == "" {
+			return ""
+		}
+		if gen.Cached {
+			return fmt.Sprintf("**Model:** %s (cached response)", gen.Model)
+		}
+		return fmt.Sprintf("**Model:** %s", gen.Model)
+	},
+	"stopreason": func(t *jira.Ticket, gen *generationInfo) string {
+		if gen == nil || gen.StopReason == "" {
+			return ""
+		}
+		if gen.StopReason == string(anthropic.StopReasonMaxTokens) {
+			return fmt.Sprintf("**Stop Reason:** %s (plan truncated)", gen.StopReason)
+		}
+		return fmt.Sprintf("**Stop Reason:** %s", gen.StopReason)
+	},
+	"tokens": func(t *jira.Ticket, gen *generationInfo) string {
+		if gen == nil {
+			return ""
+		}
+		return fmt.Sprintf("**Tokens:** %d input, %d output", gen.InputTokens, gen.OutputTokens)
+	},
+	"epic": func(t *jira.Ticket, gen *generationInfo) string {
+		if t.Epic == "" {
+			return ""
+		}
+		return fmt.Sprintf("**Epic:** %s", t.Epic)
+	},
+	"duedate": func(t *jira.Ticket, gen *generationInfo) string {
+		if t.DueDate.IsZero() {
+			return ""
+		}
+		return fmt.Sprintf("**Due Date:** %s", t.DueDate.Format("2006-01-02"))
+	},
+	"security": func(t *jira.Ticket, gen *generationInfo) string {
+		if t.SecurityLevel == nil {
+			return ""
+		}
+		return fmt.Sprintf("**Security Level:** %s", t.SecurityLevel.Name)
+	},
+	"votes": func(t *jira.Ticket, gen *generationInfo) string {
+		if t.Votes == 0 {
+			return ""
+		}
+		return fmt.Sprintf("**Votes:** %d", t.Votes)
+	},
+}
+
+// validateHeaderFields normalizes header field names and returns an error listing any unknown names
+func validateHeaderFields(fields []string) ([]string, error) {
+	var normalized, unknown []string
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if _, ok := headerFieldFormatters[field]; !ok {
+			unknown = append(unknown, field)
+			continue
+		}
+		normalized = append(normalized, field)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown header fields: %s (valid fields: %s)",
+			strings.Join(unknown, ", "), strings.Join(validHeaderFields(), ", "))
+	}
+
+	return normalized, nil
+}
+
+// validHeaderFields returns the supported header field names in documentation order
+func validHeaderFields() []string {
+	return []string{"key", "summary", "generated", "status", "security", "type", "priority", "assignee", "reporter", "components", "labels", "environment", "epic", "duedate", "votes", "model", "stopreason", "tokens"}
+}
+
+// formatPlanHeader renders the metadata header for a saved plan with the given fields in order
+func formatPlanHeader(ticket *jira.Ticket, gen *generationInfo, fields []string) string {
+	var header strings.Builder
+	title := linkedSummary(ticket)
+	if gen != nil && gen.Interrupted {
+		header.WriteString(fmt.Sprintf("# Implementation Plan: %s [interrupted]\n\n", title))
+	} else {
+		header.WriteString(fmt.Sprintf("# Implementation Plan: %s\n\n", title))
+	}
+
+	for _, field := range fields {
+		if line := headerFieldFormatters[field](ticket, gen); line != "" {
+			header.WriteString(line)
+			header.WriteString("\n")
+		}
+	}
+
+	header.WriteString("\n---\n\n")
+	return header.String()
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestComponentDisplayLimit(t *testing.T) {
+	ticket := &jira.Ticket{Key: "MONO-1", Summary: "Bump the shared logger"}
+	for i := 1; i <= 30; i++ {
+		ticket.Components = append(ticket.Components, jira.Component{Name: fmt.Sprintf("component-%02d", i)})
+	}
+
+	tests := []struct {
+		name        string
+		limit       int
+		wantShown   int
+		wantSummary string
+	}{
+		{name: "default cap", limit: 10, wantShown: 10, wantSummary: "... and 20 more"},
+		{name: "small cap", limit: 3, wantShown: 3, wantSummary: "... and 27 more"},
+		{name: "no cap", limit: 0, wantShown: 30},
+		{name: "cap above the count", limit: 50, wantShown: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display := ticket.ComponentNamesLimit(tt.limit)
+			if got := strings.Count(display, "component-"); got != tt.wantShown {
+				t.Errorf("displayed %d components, want %d: %s", got, tt.wantShown, display)
+			}
+			if tt.wantSummary != "" && !strings.HasSuffix(display, ", "+tt.wantSummary) {
+				t.Errorf("display = %q, want it to end with %q", display, tt.wantSummary)
+			}
+			if tt.wantSummary == "" && strings.Contains(display, "more") {
+				t.Errorf("display = %q, want no truncation", display)
+			}
+		})
+	}
+
+	// The prompt and saved header always list every component
+	oldLimit := maxDisplayComponents
+	t.Cleanup(func() { maxDisplayComponents = oldLimit })
+	maxDisplayComponents = 3
+	if got := strings.Count(ticket.MarshalForPrompt(), "component-"); got != 30 {
+		t.Errorf("prompt lists %d components, want 30", got)
+	}
+	if got := strings.Count(formatPlanHeader(ticket, nil, []string{"components"}), "component-"); got != 30 {
+		t.Errorf("saved header lists %d components, want 30", got)
+	}
+}
+
+func TestLinkedSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		ticket jira.Ticket
+		want   string
+	}{
+		{name: "linked", ticket: jira.Ticket{Summary: "Retry uploads", URL: "https://issues.redhat.com/browse/RHEL-1"}, want: "[Retry uploads](https://issues.redhat.com/browse/RHEL-1)"},
+		{name: "brackets escaped", ticket: jira.Ticket{Summary: "[export] Retry", URL: "https://issues.redhat.com/browse/RHEL-1"}, want: `[\[export\] Retry](https://issues.redhat.com/browse/RHEL-1)`},
+		{name: "no URL", ticket: jira.Ticket{Summary: "Retry uploads"}, want: "Retry uploads"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkedSummary(&tt.ticket); got != tt.want {
+				t.Errorf("linkedSummary() = %q, want %q", got, tt.want)
+			}
+			if header := formatPlanHeader(&tt.ticket, nil, nil); !strings.HasPrefix(header, "# Implementation Plan: "+tt.want+"\n") {
+				t.Errorf("header = %q, want the summary %q", header, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePlanNoHeader(t *testing.T) {
+	ticket := &jira.Ticket{Key: "RHEL-9", Summary: "Retry uploads"}
+	plan := "## Steps\n\n1. Retry failed uploads\n"
+
+	tests := []struct {
+		name     string
+		noHeader bool
+		format   string
+		wantID   bool
+	}{
+		{name: "markdown with header", format: FormatMarkdown, wantID: true},
+		{name: "markdown without header", noHeader: true, format: FormatMarkdown},
+		{name: "html without header", noHeader: true, format: FormatHTML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldNoHeader, oldFormat := noHeader, outputFormat
+			t.Cleanup(func() { noHeader, outputFormat = oldNoHeader, oldFormat })
+			noHeader, outputFormat = tt.noHeader, tt.format
+
+			path := filepath.Join(t.TempDir(), "RHEL-9.md")
+			if _, err := writePlanToFile(path, ticket, nil, plan, defaultHeaderFields, false); err != nil {
+				t.Fatalf("writePlanToFile() error = %v", err)
+			}
+
+			content := fileContent(t, path)
+			if got := strings.Contains(content, "Ticket ID:"); got != tt.wantID {
+				t.Errorf("saved plan contains Ticket ID = %v, want %v:\n%s", got, tt.wantID, content)
+			}
+			if got := strings.Contains(content, "Implementation Plan:"); got != tt.wantID {
+				t.Errorf("saved plan contains the title = %v, want %v:\n%s", got, tt.wantID, content)
+			}
+			if !strings.Contains(content, "Retry failed uploads") {
+				t.Errorf("saved plan is missing the plan body:\n%s", content)
+			}
+		})
+	}
+}
+
+func TestFormatPlanHeaderFields(t *testing.T) {
+	ticket := &jira.Ticket{
+		Key:       "RHEL-9",
+		Summary:   "Retry uploads",
+		Status:    jira.Status{Name: "In Progress"},
+		IssueType: jira.IssueType{Name: "Bug"},
+	}
+
+	tests := []struct {
+		name   string
+		ticket *jira.Ticket
+		gen    *generationInfo
+		fields []string
+		want   []string
+	}{
+		{
+			name:   "custom order",
+			fields: []string{" Status", "KEY ", "type"},
+			want:   []string{"**Status:** In Progress", "**Ticket ID:** RHEL-9", "**Type:** Bug"},
+		},
+		{
+			name:   "empty fields omitted",
+			fields: []string{"labels", "key", "environment"},
+			want:   []string{"**Ticket ID:** RHEL-9"},
+		},
+		{
+			name:   "model",
+			gen:    &generationInfo{Model: "claude-sonnet-4@20250514", StopReason: "end_turn"},
+			fields: []string{"key", "model"},
+			want:   []string{"**Ticket ID:** RHEL-9", "**Model:** claude-sonnet-4@20250514"},
+		},
+		{
+			name:   "cached model",
+			gen:    &generationInfo{Model: "claude-sonnet-4@20250514", Cached: true},
+			fields: []string{"model"},
+			want:   []string{"**Model:** claude-sonnet-4@20250514 (cached response)"},
+		},
+		{
+			name:   "model without generation info",
+			fields: []string{"key", "model"},
+			want:   []string{"**Ticket ID:** RHEL-9"},
+		},
+		{
+			name:   "votes",
+			ticket: &jira.Ticket{Key: "RHEL-9", Votes: 3, HasVoted: true},
+			fields: []string{"key", "votes"},
+			want:   []string{"**Ticket ID:** RHEL-9", "**Votes:** 3"},
+		},
+		{
+			name:   "no votes omitted",
+			fields: []string{"key", "votes"},
+			want:   []string{"**Ticket ID:** RHEL-9"},
+		},
+		{
+			name:   "security level",
+			ticket: &jira.Ticket{Key: "RHEL-9", SecurityLevel: &jira.SecurityLevel{ID: "10100", Name: "Red Hat Employee"}},
+			fields: []string{"key", "security"},
+			want:   []string{"**Ticket ID:** RHEL-9", "**Security Level:** Red Hat Employee"},
+		},
+		{
+			name:   "no security level omitted",
+			fields: []string{"key", "security"},
+			want:   []string{"**Ticket ID:** RHEL-9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := validateHeaderFields(tt.fields)
+			if err != nil {
+				t.Fatalf("validateHeaderFields() error = %v", err)
+			}
+			if tt.ticket == nil {
+				tt.ticket = ticket
+			}
+
+			header := formatPlanHeader(tt.ticket, tt.gen, fields)
+			lines := strings.Split(strings.TrimSpace(header), "\n")
+			if len(lines) != len(tt.want)+4 {
+				t.Fatalf("header = %q, want the title, %d field lines and the separator", header, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if lines[i+2] != want {
+					t.Errorf("field line %d = %q, want %q", i, lines[i+2], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHeaderFieldsUnknown(t *testing.T) {
+	if _, err := validateHeaderFields([]string{"key", "colour"}); err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Errorf("validateHeaderFields() error = %v, want it to name the unknown field", err)
+	}
+}
+
+func TestSavedPlanModelLine(t *testing.T) {
+	oldNoHeader, oldFormat := noHeader, outputFormat
+	t.Cleanup(func() { noHeader, outputFormat = oldNoHeader, oldFormat })
+	noHeader, outputFormat = false, FormatMarkdown
+
+	path := filepath.Join(t.TempDir(), "RHEL-9.md")
+	gen := &generationInfo{Model: "claude-opus-4-1@20250805"}
+	if _, err := writePlanToFile(path, &jira.Ticket{Key: "RHEL-9", Summary: "Retry uploads"}, gen, "## Steps\n", defaultHeaderFields, false); err != nil {
+		t.Fatalf("writePlanToFile() error = %v", err)
+	}
+	if want := "**Model:** claude-opus-4-1@20250805\n"; !strings.Contains(fileContent(t, path), want) {
+		t.Errorf("saved plan doesn't contain %q:\n%s", want, fileContent(t, path))
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// indexFileName is the name of the plan index written to the output directory after batch runs
+const indexFileName = "index.md"
+
+// savedPlan records a generated plan and the file it was saved to
+type savedPlan struct {
+	Ticket    *jira.Ticket
+	FilePath  string
+	Plan      string
+	Gen       *generationInfo
+	Generated time.Time
+
+	// DuplicateOf is the key of the ticket whose identical plan this one references, if any
+	DuplicateOf string
+}
+
+// indexEntry is a single row of the plan index
+type indexEntry struct {
+	Key     string
+	File    string
+	Summary string
+	Status  string
+}
+
+// indexRowPattern matches a plan row written by formatIndexRow
+var indexRowPattern = regexp.MustCompile(`^\| \[(.+?)\]\((.+?)\) \| (.*) \| ([^|]*) \|$`)
+
+// writePlanIndex creates or updates index.md in dir with a table row for each
+// saved plan. Rows from an existing index are preserved, and rows for the same
+// file are replaced. It returns the path of the index file.
+func writePlanIndex(dir string, plans []savedPlan) (string, error) {
+	indexPath := filepath.Join(dir, indexFileName)
+
+	entries, err := readPlanIndex(indexPath)
+	if err != nil {
+		return "", err
+	}
+
+	byFile := make(map[string]int)
+	for i, entry := range entries {
+		byFile[entry.File] = i
+	}
+
+	for _, plan := range plans {
+		file, err := filepath.Rel(dir, plan.FilePath)
+		if err != nil {
+			file = filepath.Base(plan.FilePath)
+		}
+
+		entry := indexEntry{
+			Key:     plan.Ticket.Key,
+			File:    filepath.ToSlash(file),
+			Summary: plan.Ticket.Summary,
+			Status:  plan.Ticket.Status.Name,
+		}
+		if plan.DuplicateOf != "" {
+			entry.Summary += fmt.Sprintf(" (duplicate of %s)", plan.DuplicateOf)
+		}
+		if i, ok := byFile[entry.File]; ok {
+			entries[i] = entry
+		} else {
+			byFile[entry.File] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Key != entries[j].Key {
+			return entries[i].Key < entries[j].Key
+		}
+		return entries[i].File < entries[j].File
+	})
+
+	var content strings.Builder
+	content.WriteString("# Implementation Plan Index\n\n")
+	content.WriteString(fmt.Sprintf("**Updated:** %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	content.WriteString(fmt.Sprintf("**Plans:** %d\n\n", len(entries)))
+	content.WriteString("| Ticket | Summary | Status |\n")
+	content.WriteString("|--------|---------|--------|\n")
+	for _, entry := range entries {
+		content.WriteString(formatIndexRow(entry))
+		content.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	if err := os.WriteFile(indexPath, []byte(content.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file %s: %w", indexPath, err)
+	}
+
+	return indexPath, nil
+}
+
+// readPlanIndex reads the entries of an existing index file; a missing file has no entries
+func readPlanIndex(indexPath string) ([]indexEntry, error) {
+	data, err := os.ReadFile(indexPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index %s: %w", indexPath, err)
+	}
+
+	var entries []indexEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := indexRowPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			entries = append(entries, indexEntry{
+				Key:     m[1],
+				File:    m[2],
+				Summary: unescapeTableCell(m[3]),
+				Status:  unescapeTableCell(m[4]),
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// formatIndexRow formats an index entry as a markdown table row
+func formatIndexRow(entry indexEntry) string {
+	return fmt.Sprintf("| [%s](%s) | %s | %s |", entry.Key, entry.File,
+		escapeTableCell(entry.Summary), escapeTableCell(entry.Status))
+}
+
+// escapeTableCell escapes pipes and flattens newlines so text fits in a markdown table cell
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// unescapeTableCell reverses escapeTableCell's pipe escaping
+func unescapeTableCell(text string) string {
+	return strings.ReplaceAll(text, `\|`, "|")
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+	"github.com/joshbranham/jira-implementation-generator/prompts"
+	"github.com/spf13/cobra"
+)
+
+var forceInitTemplate bool
+
+var initTemplateCmd = &cobra.Command{
+	Use:   "init-template [path]",
+	Short: "Write the default prompt template to disk for customization",
+	Long: `Init-template writes the built-in default prompt template to the given path
+(defaults to prompts/implementation-plan.poml) so it can be used as a starting
+point for a custom template. Existing files are not overwritten unless --force
+is given.`,
+	Example: `  jig init-template
+  jig init-template my-team-prompt.poml
+  jig init-template --force prompts/implementation-plan.poml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := prompt.GetDefaultTemplatePath()
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		if err := writeDefaultTemplate(path, forceInitTemplate); err != nil {
+			color.Red("❌ Failed to write template: %v", err)
+			os.Exit(1)
+		}
+		color.Green("💾 Default template written to: %s", path)
+	},
+}
+
+func init() {
+	initTemplateCmd.Flags().BoolVar(&forceInitTemplate, "force", false, "Overwrite the file if it already exists")
+}
+
+// writeDefaultTemplate writes the embedded default template to path, refusing
+// to overwrite an existing file unless force is set
+func writeDefaultTemplate(path string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to check %s: %w", path, err)
+		}
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(prompts.ImplementationPlanPOML), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/prompts"
+)
+
+func TestWriteDefaultTemplate(t *testing.T) {
+	const custom = "<poml><role>Customized</role></poml>"
+	tests := []struct {
+		name     string
+		existing bool
+		force    bool
+		wantErr  bool
+		want     string
+	}{
+		{name: "new file", want: prompts.ImplementationPlanPOML},
+		{name: "existing file kept", existing: true, wantErr: true, want: custom},
+		{name: "existing file forced", existing: true, force: true, want: prompts.ImplementationPlanPOML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prompts", "implementation-plan.poml")
+			if tt.existing {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := writeDefaultTemplate(path, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("writeDefaultTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := fileContent(t, path); got != tt.want {
+				t.Errorf("file content = %.40q..., want %.40q...", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// maxPickerRows is the number of candidates listed at once by the --interactive picker
+const maxPickerRows = 20
+
+// errNoSelection reports that the picker was closed without choosing any ticket
+var errNoSelection = errors.New("no tickets selected")
+
+// projectJQL returns the candidate query for --interactive --project, most recently updated first
+func projectJQL(project string) string {
+	return fmt.Sprintf("project = %s ORDER BY updated DESC", strconv.Quote(strings.TrimSpace(project)))
+}
+
+// fuzzyMatch reports whether every character of query appears in text in order,
+// ignoring case and spaces, so "rtyexp" matches "Add retry support to the export job"
+func fuzzyMatch(query, text string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
+// filterCandidates returns the tickets whose key and summary fuzzy-match query,
+// in their original order. An empty query matches every ticket.
+func filterCandidates(tickets []*jira.Ticket, query string) []*jira.Ticket {
+	var matches []*jira.Ticket
+	for _, ticket := range tickets {
+		if fuzzyMatch(query, ticket.Key+" "+ticket.Summary) {
+			matches = append(matches, ticket)
+		}
+	}
+	return matches
+}
+
+// parseSelection parses picker input such as "1,3-5" or "all" into zero-based
+// indexes of a list of n tickets. It reports false for input that isn't a
+// selection, which the picker treats as a new filter.
+func parseSelection(input string, n int) ([]int, bool, error) {
+	input = strings.TrimSpace(input)
+	if strings.EqualFold(input, "all") {
+		indexes := make([]int, n)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, true, nil
+	}
+	if input == "" || strings.IndexFunc(input, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != ',' && r != '-' && !unicode.IsSpace(r)
+	}) >= 0 {
+		return nil, false, nil
+	}
+
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid selection %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+				return nil, true, fmt.Errorf("invalid selection %q", part)
+			}
+		}
+		if start < 1 || end > n || start > end {
+			return nil, true, fmt.Errorf("selection %q is outside 1-%d", part, n)
+		}
+		for i := start - 1; i < end; i++ {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return indexes, true, nil
+}
+
+// pickTickets lets the user narrow the candidates with fuzzy filters and choose
+// the tickets to plan by number, or every match with "all", including matches
+// beyond the listed rows. Empty input clears the filter, and "q" or end of input
+// cancels with errNoSelection.
+func pickTickets(candidates []*jira.Ticket, in io.Reader, out io.Writer) ([]*jira.Ticket, error) {
+	scanner := bufio.NewScanner(in)
+	query := ""
+	for {
+		matches := filterCandidates(candidates, query)
+		shown := matches
+		if len(shown) > maxPickerRows {
+			shown = shown[:maxPickerRows]
+		}
+
+		fmt.Fprintln(out)
+		if len(shown) == 0 {
+			fmt.Fprintf(out, "No tickets match %q\n", query)
+		}
+		for i, ticket := range shown {
+			fmt.Fprintf(out, "%3d. %-12s %s [%s]\n", i+1, ticket.Key, ticket.Summary, ticket.Status.Name)
+		}
+		if hidden := len(matches) - len(shown); hidden > 0 {
+			fmt.Fprintf(out, "     ... and %d more; filter to list them\n", hidden)
+		}
+		fmt.Fprintf(out, "Type to filter, pick numbers (e.g. 1,3-5) or \"all\" (%d), empty to clear, q to quit: ", len(matches))
+
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			if err := scanner.Err(); err != nil {
+				return nil, fmt.Errorf("failed to read selection: %w", err)
+			}
+			return nil, errNoSelection
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if strings.EqualFold(input, "q") {
+			return nil, errNoSelection
+		}
+
+		// "all" picks every match, not just the listed rows
+		if strings.EqualFold(input, "all") {
+			shown = matches
+		}
+		indexes, isSelection, err := parseSelection(input, len(shown))
+		if err != nil {
+			fmt.Fprintf(out, "%v\n", err)
+			continue
+		}
+		if !isSelection {
+			query = input
+			continue
+		}
+
+		selected := make([]*jira.Ticket, 0, len(indexes))
+		for _, i := range indexes {
+			selected = append(selected, shown[i])
+		}
+		if len(selected) > 0 {
+			return selected, nil
+		}
+	}
+}
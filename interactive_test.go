@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		want  bool
+	}{
+		{"", "anything", true},
+		{"rtyexp", "Add retry support to the export job", true},
+		{"job export", "Add retry support to the export job", false},
+		{"retry export", "Add retry support to the export job", true},
+		{"RHEL-12", "RHEL-123 Fix boot", true},
+		{"zzz", "RHEL-123 Fix boot", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.query, tt.text); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.query, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input         string
+		n             int
+		want          []int
+		wantSelection bool
+		wantErr       bool
+	}{
+		{input: "all", n: 3, want: []int{0, 1, 2}, wantSelection: true},
+		{input: "1,3-4", n: 5, want: []int{0, 2, 3}, wantSelection: true},
+		{input: "2, 1-2", n: 3, want: []int{1, 0}, wantSelection: true},
+		{input: "6", n: 5, wantSelection: true, wantErr: true},
+		{input: "3-1", n: 5, wantSelection: true, wantErr: true},
+		{input: "export", n: 5},
+		{input: "", n: 5},
+	}
+	for _, tt := range tests {
+		got, isSelection, err := parseSelection(tt.input, tt.n)
+		if (err != nil) != tt.wantErr || isSelection != tt.wantSelection || !slices.Equal(got, tt.want) {
+			t.Errorf("parseSelection(%q, %d) = %v, %v, %v; want %v, %v, error %v",
+				tt.input, tt.n, got, isSelection, err, tt.want, tt.wantSelection, tt.wantErr)
+		}
+	}
+}
+
+func TestPickTickets(t *testing.T) {
+	var candidates []*jira.Ticket
+	for i := 1; i <= 30; i++ {
+		summary := "Fix the export job"
+		if i%2 == 0 {
+			summary = "Tune the kernel"
+		}
+		candidates = append(candidates, &jira.Ticket{Key: fmt.Sprintf("RHEL-%d", i), Summary: summary})
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		wantKeys []string
+		wantErr  error
+	}{
+		{name: "numbers", input: "1,3-4\n", wantKeys: []string{"RHEL-1", "RHEL-3", "RHEL-4"}},
+		{name: "filter then pick", input: "kernel\n2\n", wantKeys: []string{"RHEL-4"}},
+		{name: "invalid selection is retried", input: "25\n1\n", wantKeys: []string{"RHEL-1"}},
+		{name: "cleared filter", input: "kernel\n\n3\n", wantKeys: []string{"RHEL-3"}},
+		{name: "quit", input: "q\n", wantErr: errNoSelection},
+		{name: "end of input", input: "kernel\n", wantErr: errNoSelection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := pickTickets(candidates, strings.NewReader(tt.input), io.Discard)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("pickTickets() error = %v, want %v", err, tt.wantErr)
+			}
+			if keys := ticketKeys(selected); !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("pickTickets() = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestPickTicketsAllBeyondListedRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCount int
+	}{
+		{name: "every candidate", input: "all\n", wantCount: 45},
+		{name: "every match", input: "export\nall\n", wantCount: 23},
+	}
+
+	var candidates []*jira.Ticket
+	for i := 1; i <= 45; i++ {
+		summary := "Tune the kernel"
+		if i <= 23 {
+			summary = "Fix the export job"
+		}
+		candidates = append(candidates, &jira.Ticket{Key: fmt.Sprintf("RHEL-%d", i), Summary: summary})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			selected, err := pickTickets(candidates, strings.NewReader(tt.input), &out)
+			if err != nil {
+				t.Fatalf("pickTickets() error = %v", err)
+			}
+			if len(selected) != tt.wantCount {
+				t.Errorf("pickTickets() selected %d tickets, want %d", len(selected), tt.wantCount)
+			}
+			if hidden := fmt.Sprintf("... and %d more", tt.wantCount-maxPickerRows); !strings.Contains(out.String(), hidden) || strings.Contains(out.String(), "RHEL-21 ") {
+				t.Errorf("picker didn't list only the first %d rows and %q:\n%s", maxPickerRows, hidden, out.String())
+			}
+		})
+	}
+}
+
+// ticketKeys returns the keys of tickets
+func ticketKeys(tickets []*jira.Ticket) []string {
+	var keys []string
+	for _, ticket := range tickets {
+		keys = append(keys, ticket.Key)
+	}
+	return keys
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+// issueTypeConfig selects the template and model for a ticket by its issue type,
+// from --type-template and --type-model. Keys are lowercased issue type names.
+type issueTypeConfig struct {
+	Templates map[string]string
+	Models    map[string]string
+}
+
+// typeConfig is the per-issue-type configuration for the current run
+var typeConfig issueTypeConfig
+
+// parseIssueTypeMap parses repeatable Type=value flags, e.g. "Bug=claude-opus-4@20250514",
+// into a map keyed by the lowercased issue type. Issue type names may contain spaces.
+func parseIssueTypeMap(flags []string) (map[string]string, error) {
+	values := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		issueType, value, ok := strings.Cut(flag, "=")
+		issueType, value = strings.TrimSpace(issueType), strings.TrimSpace(value)
+		if !ok || issueType == "" || value == "" {
+			return nil, fmt.Errorf("%q must be in Type=value format", flag)
+		}
+		key := strings.ToLower(issueType)
+		if _, exists := values[key]; exists {
+			return nil, fmt.Errorf("duplicate issue type %q", issueType)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// template returns the template configured for the ticket's issue type, or fallback.
+// A prompt read from stdin (--template -) and the embedded explain and --structured
+// templates are used for every ticket, since a plan template would ask for the wrong output.
+func (c issueTypeConfig) template(ticket *jira.Ticket, fallback string) string {
+	switch fallback {
+	case stdinTemplatePath, prompt.EmbeddedExplainTemplatePath, prompt.EmbeddedStructuredTemplatePath:
+		return fallback
+	}
+	return lookupIssueType(c.Templates, ticket, fallback)
+}
+
+// model returns the model configured for the ticket's issue type, or fallback
+func (c issueTypeConfig) model(ticket *jira.Ticket, fallback string) string {
+	return lookupIssueType(c.Models, ticket, fallback)
+}
+
+// lookupIssueType returns the value for the ticket's issue type, ignoring case, or fallback
+func lookupIssueType(values map[string]string, ticket *jira.Ticket, fallback string) string {
+	if value, ok := values[strings.ToLower(strings.TrimSpace(ticket.IssueType.Name))]; ok {
+		return value
+	}
+	return fallback
+}
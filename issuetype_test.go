@@ -0,0 +1,78 @@
+package main
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+func TestParseIssueTypeMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "none", want: map[string]string{}},
+		{
+			name:  "lowercased types with spaces",
+			flags: []string{"Bug=prompts/bug.md", " Sub-task = prompts/subtask.md", "Tech Debt=prompts/debt.md"},
+			want:  map[string]string{"bug": "prompts/bug.md", "sub-task": "prompts/subtask.md", "tech debt": "prompts/debt.md"},
+		},
+		{name: "model with an equals sign", flags: []string{"Bug=model=v2"}, want: map[string]string{"bug": "model=v2"}},
+		{name: "missing value", flags: []string{"Bug="}, wantErr: true},
+		{name: "missing type", flags: []string{"=prompts/bug.md"}, wantErr: true},
+		{name: "no separator", flags: []string{"Bug"}, wantErr: true},
+		{name: "duplicate type ignoring case", flags: []string{"Bug=a.md", "bug=b.md"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIssueTypeMap(tt.flags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIssueTypeMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("parseIssueTypeMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueTypeConfig(t *testing.T) {
+	config := issueTypeConfig{
+		Templates: map[string]string{"bug": "prompts/bug.md"},
+		Models:    map[string]string{"bug": "claude-opus-4@20250514"},
+	}
+
+	tests := []struct {
+		name         string
+		issueType    string
+		template     string
+		wantTemplate string
+		wantModel    string
+	}{
+		{name: "Bug selects configured template and model", issueType: "Bug", template: "prompts/plan.md", wantTemplate: "prompts/bug.md", wantModel: "claude-opus-4@20250514"},
+		{name: "issue type case is ignored", issueType: " BUG ", template: "prompts/plan.md", wantTemplate: "prompts/bug.md", wantModel: "claude-opus-4@20250514"},
+		{name: "unconfigured type falls back", issueType: "Story", template: "prompts/plan.md", wantTemplate: "prompts/plan.md", wantModel: DefaultModel},
+		{name: "stdin prompt takes precedence", issueType: "Bug", template: stdinTemplatePath, wantTemplate: stdinTemplatePath, wantModel: "claude-opus-4@20250514"},
+		{name: "explain template takes precedence", issueType: "Bug", template: prompt.EmbeddedExplainTemplatePath, wantTemplate: prompt.EmbeddedExplainTemplatePath, wantModel: "claude-opus-4@20250514"},
+		{name: "structured template takes precedence", issueType: "Bug", template: prompt.EmbeddedStructuredTemplatePath, wantTemplate: prompt.EmbeddedStructuredTemplatePath, wantModel: "claude-opus-4@20250514"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := &jira.Ticket{Key: "A-1"}
+			ticket.IssueType.Name = tt.issueType
+
+			if got := config.template(ticket, tt.template); got != tt.wantTemplate {
+				t.Errorf("template() = %q, want %q", got, tt.wantTemplate)
+			}
+			if got := config.model(ticket, DefaultModel); got != tt.wantModel {
+				t.Errorf("model() = %q, want %q", got, tt.wantModel)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+// listFields are the only issue fields requested by the list command
+var listFields = []string{"summary", "status", "assignee"}
+
+// maxListSummary is the width summaries are truncated to in the list table
+const maxListSummary = 70
+
+var listCmd = &cobra.Command{
+	Use:   "list --jql <QUERY>",
+	Short: "List the tickets a JQL query matches without generating plans",
+	Long: `List searches Jira and prints the key, status, assignee and summary of each
+matching ticket, to preview a --jql batch before generating plans for it. Only
+those fields are fetched, so listing is much cheaper than a full run.`,
+	Example: `  jig list --jql "project = RHEL AND fixVersion = 9.6"
+  jig list --jql "project = RHEL AND statusCategory != Done" --assignee-me`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runList(os.Stdout)
+	},
+}
+
+func init() {
+	listCmd.Flags().StringVar(&jql, "jql", "", "JQL query to list the matching tickets of")
+	listCmd.Flags().IntVar(&maxResults, "max-results", 50, "Maximum number of tickets to list (0 for no limit)")
+	listCmd.Flags().BoolVar(&assigneeMe, "assignee-me", false, "Only list tickets assigned to you (requires a token)")
+	listCmd.MarkFlagRequired("jql")
+}
+
+func runList(out io.Writer) {
+	// Requesting no sprint or epic field keeps the search down to listFields
+	jiraClient := newJiraClient(jira.WithFields(listFields...), jira.WithSprintField(""), jira.WithEpicLinkField(""))
+	defer jiraClient.Close()
+
+	if assigneeMe {
+		if token == "" {
+			color.Red("❌ --assignee-me requires a token, since anonymous requests have no current user")
+			os.Exit(1)
+		}
+		jql = addJQLClause(jql, assigneeMeClause)
+	}
+
+	s := spinner.New(spinner.CharSets[35], 100*time.Millisecond)
+	s.Suffix = " Searching Jira tickets..."
+	s.Start()
+	tickets, err := jiraClient.SearchTickets(jql, maxResults)
+	s.Stop()
+	if _, err = reportInvalidIssues(err); err != nil {
+		color.Red("❌ Failed to search tickets: %v", err)
+		os.Exit(1)
+	}
+
+	if len(tickets) == 0 {
+		color.Yellow("⚠️  No tickets match the query")
+		return
+	}
+	writeTicketTable(out, tickets)
+	color.Cyan("\n%d tickets", len(tickets))
+}
+
+// writeTicketTable writes one aligned row per ticket with its key, status,
+// assignee and summary, truncated to maxListSummary characters
+func writeTicketTable(out io.Writer, tickets []*jira.Ticket) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tSTATUS\tASSIGNEE\tSUMMARY")
+	for _, ticket := range tickets {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ticket.Key, ticket.Status.Name, ticket.AssigneeName(), truncateSummary(ticket.Summary))
+	}
+	w.Flush()
+}
+
+// truncateSummary shortens a summary to maxListSummary characters, ending with
+// an ellipsis when cut, and flattens it to one line
+func truncateSummary(summary string) string {
+	summary = strings.Join(strings.Fields(summary), " ")
+	runes := []rune(summary)
+	if len(runes) <= maxListSummary {
+		return summary
+	}
+	return strings.TrimSpace(string(runes[:maxListSummary-1])) + "…"
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunList(t *testing.T) {
+	var gotFields, gotJQL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFields, gotJQL = r.URL.Query().Get("fields"), r.URL.Query().Get("jql")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"total": 2,
+			"issues": []map[string]any{
+				{"id": "1", "key": "A-1", "fields": map[string]any{
+					"summary":  "Retry uploads",
+					"status":   map[string]any{"name": "To Do"},
+					"assignee": map[string]any{"displayName": "Ada Lovelace"},
+				}},
+				{"id": "2", "key": "A-22", "fields": map[string]any{
+					"summary": strings.Repeat("Long summary ", 10),
+					"status":  map[string]any{"name": "In Progress"},
+				}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	oldBaseURL, oldToken, oldJQL, oldMax, oldAssigneeMe, oldCustomFields := jiraBaseURL, token, jql, maxResults, assigneeMe, customFields
+	t.Cleanup(func() {
+		jiraBaseURL, token, jql, maxResults, assigneeMe, customFields = oldBaseURL, oldToken, oldJQL, oldMax, oldAssigneeMe, oldCustomFields
+	})
+	t.Setenv("JIRA_TOKEN", "")
+	t.Setenv("JIRA_EMAIL", "")
+	jiraBaseURL, token, jql, maxResults, assigneeMe, customFields = server.URL, "", "project = A", 50, false, nil
+
+	var out bytes.Buffer
+	runList(&out)
+
+	if want := strings.Join(listFields, ","); gotFields != want {
+		t.Errorf("requested fields = %q, want %q", gotFields, want)
+	}
+	if gotJQL != "project = A" {
+		t.Errorf("JQL = %q, want %q", gotJQL, "project = A")
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	want := []string{
+		"KEY   STATUS       ASSIGNEE      SUMMARY",
+		"A-1   To Do        Ada Lovelace  Retry uploads",
+		"A-22  In Progress  Unassigned    " + truncateSummary(strings.Repeat("Long summary ", 10)),
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("table =\n%s\nwant %d lines", out.String(), len(want))
+	}
+	for i := range want {
+		if strings.TrimRight(lines[i], " ") != want[i] {
+			t.Errorf("table line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestTruncateSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		want    string
+	}{
+		{name: "short", summary: "Retry uploads", want: "Retry uploads"},
+		{name: "multiline", summary: "Retry\n  uploads\t now", want: "Retry uploads now"},
+		{name: "exact width", summary: strings.Repeat("a", maxListSummary), want: strings.Repeat("a", maxListSummary)},
+		{name: "too long", summary: strings.Repeat("é", maxListSummary+1), want: strings.Repeat("é", maxListSummary-1) + "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateSummary(tt.summary); got != tt.want {
+				t.Errorf("truncateSummary(%q) = %q, want %q", tt.summary, got, tt.want)
+			}
+		})
+	}
+}
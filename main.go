@@ -2,37 +2,168 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/anthropics/anthropic-sdk-go/vertex"
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/backoff"
 	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/markdown"
 	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+	"github.com/joshbranham/jira-implementation-generator/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
 const DefaultModel = "claude-sonnet-4@20250514"
+const DefaultReviewModel = "claude-3-5-haiku@20241022"
 const DefaultProjectID = "itpc-gcp-hcm-pe-eng-claude"
 const DefaultRegion = "us-east5"
 const DefaultJiraBaseURL = "https://issues.redhat.com"
+const DefaultOutputDir = "implementation-plans"
+
+// planMaxTokens is the maximum length of a generated plan in tokens
+const planMaxTokens = 4096
+
+// maxTokens is the response budget used by generatePlan, lowered by explain
+var maxTokens = planMaxTokens
+
+// maxContextTickets caps how many --context-tickets are fetched
+const maxContextTickets = 20
+
+// Supported formats for saved plan files and posted comments
+const (
+	FormatWiki     = "wiki"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
 
 var (
 	token        string
+	jiraEmail    string
 	region       string
 	projectID    string
 	jiraBaseURL  string
 	templatePath string
+	sprintField  string
+	jiraHeaders  []string
+	customFields []string
+
+	baseURLAliases []string
+	logRequests    bool
+
+	skipValidation bool
+	skipAuthTest   bool
+	fetchTimezone  bool
+	fetchProject   bool
+	renderMarkdown bool
+	maxAttempts    int
+	maxRetryWait   time.Duration
+	retryBudget    int
+	runTimeout     time.Duration
+
+	maxDisplayComponents int
+
+	jql            string
+	since          string
+	maxResults     int
+	boardID        int
+	ticketsFile    string
+	dedupePlans    bool
+	expandChildren bool
+	interactive    bool
+	pickProject    string
+	assigneeMe     bool
+	skipClosed     bool
+	statusFilter   []string
+
+	postComment       bool
+	commentVisibility string
+	commentFormat     string
+	allowRestricted   bool
+	attachPlan        bool
+	transition        string
+
+	contextTickets  []string
+	maxContextChars int
+	relatedTickets  []*jira.Ticket
+
+	headerFields  []string
+	noHeader      bool
+	epicLinkField string
+
+	maxDescriptionChars int
+	varFlags            []string
+	planModel           string
+	stdinAsTemplate     bool
+	stdinPrompt         string
+	typeModelFlags      []string
+	typeTemplateFlags   []string
+	templateVars        map[string]string
+	planLanguage        string
+	promptPrefix        string
+	promptSuffix        string
+	diffFile            string
+	strictXML           bool
+	maxDiffChars        int
+	diffContent         string
+
+	outputPath   string
+	appendPlan   bool
+	outputFormat string
+	organizeBy   string
+
+	copyToClipboard  bool
+	structuredOutput bool
+	includeThinking  bool
+	minPlanChars     int
+	minPlanLines     int
+	addTOC           bool
+
+	selfReview  bool
+	reviewModel string
+
+	summarizeComments  bool
+	summarizeThreshold int
+	summaryModel       string
+	maxCommentChars    int
+
+	cacheResponses bool
+	forceCache     bool
+	cacheTTL       time.Duration
+	responses      *responseCache
+	promptCache    string
+	temperature    float64
+
+	fileHeader     string
+	fileFooter     string
+	fileHeaderText string
+	fileFooterText string
+
+	verbose      bool
+	dryRun       bool
+	quiet        bool
+	streamOutput bool
+	forceSize    bool
 )
 
+// observer receives telemetry events from each run; replace it to record metrics
+var observer telemetry.Observer = telemetry.NopObserver{}
+
 var rootCmd = &cobra.Command{
-	Use:   "jig <TICKET_ID>",
+	Use:   "jig <TICKET_ID>... | --jql <QUERY>",
 	Short: "Generate implementation plans for Jira tickets using Google Cloud Vertex AI",
 	Long: `Jira Implementation Generator (jig) fetches Jira tickets from Jira
 and generates detailed implementation plans using Google Cloud Vertex AI.
@@ -42,19 +173,286 @@ access using Personal Access Tokens for private tickets.`,
 	Example: `  jig RHEL-12345
   jig --token=your_pat_here RHEL-12345
   jig --region=us-central1 --project-id=my-project RHEL-12345
-  jig --jira-base-url=https://my-jira.com RHEL-12345`,
-	Args: cobra.ExactArgs(1),
+  jig --jira-base-url=https://my-jira.com RHEL-12345
+  jig RHEL-12345 RHEL-12346 RHEL-12347
+  jig --jql "project = RHEL AND fixVersion = 9.6"
+  jig --board 1234
+  jig --tickets-file tickets.txt
+  jig --expand-children RHEL-12000
+  jig --interactive --project RHEL`,
+	PersistentPreRun: resolveJiraBaseURL,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && jql == "" && boardID == 0 && ticketsFile == "" && pickProject == "" {
+			return fmt.Errorf("requires at least one TICKET_ID, a --jql query, a --board, a --tickets-file or --interactive --project")
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		runJiraGenerator(context.Background(), args[0])
+		ticketIDs := args
+		if ticketsFile != "" {
+			fromFile, err := readTicketsFile(ticketsFile)
+			if err != nil {
+				color.Red("❌ Invalid --tickets-file: %v", err)
+				os.Exit(1)
+			}
+			ticketIDs = mergeTicketIDs(args, fromFile)
+			if len(ticketIDs) == 0 && jql == "" && boardID == 0 {
+				color.Red("❌ --tickets-file %s contains no ticket IDs", ticketsFile)
+				os.Exit(1)
+			}
+		}
+
+		ctx, stop := interruptContext()
+		defer stop()
+		runJiraGenerator(ctx, ticketIDs)
 	},
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&token, "token", "t", "", "Jira Personal Access Token (can also be set via JIRA_TOKEN environment variable)")
-	rootCmd.Flags().StringVarP(&region, "region", "r", DefaultRegion, "Google Cloud region for Vertex AI")
-	rootCmd.Flags().StringVarP(&projectID, "project-id", "p", DefaultProjectID, "Google Cloud project ID for Vertex AI")
-	rootCmd.Flags().StringVar(&jiraBaseURL, "jira-base-url", DefaultJiraBaseURL, "Base URL for Jira instance")
-	rootCmd.Flags().StringVar(&templatePath, "template", "", "Path to custom prompt template file (defaults to prompts/implementation-plan.md)")
+	rootCmd.PersistentFlags().StringVarP(&token, "token", "t", "", "Jira Personal Access Token (can also be set via JIRA_TOKEN environment variable)")
+	rootCmd.PersistentFlags().StringVar(&jiraEmail, "jira-email", "", "Account email for Jira Cloud, sending the token as an API token with Basic authentication (can also be set via JIRA_EMAIL)")
+	rootCmd.PersistentFlags().StringVarP(&region, "region", "r", DefaultRegion, "Google Cloud region for Vertex AI")
+	rootCmd.PersistentFlags().StringVarP(&projectID, "project-id", "p", DefaultProjectID, "Google Cloud project ID for Vertex AI")
+	rootCmd.PersistentFlags().StringVar(&jiraBaseURL, "jira-base-url", DefaultJiraBaseURL, "Base URL for Jira instance, or an alias such as redhat or apache")
+	rootCmd.PersistentFlags().StringArrayVar(&baseURLAliases, "base-url-alias", nil, "Define a --jira-base-url alias as name=url (repeatable; also read from $"+JiraAliasEnvVar+")")
+	rootCmd.PersistentFlags().StringVar(&sprintField, "sprint-field", jira.DefaultSprintField, "Custom field holding sprint information")
+	rootCmd.PersistentFlags().StringVar(&epicLinkField, "epic-field", jira.DefaultEpicLinkField, "Custom field holding the Epic Link (Jira Server)")
+	rootCmd.PersistentFlags().IntVar(&maxAttempts, "max-attempts", backoff.DefaultMaxAttempts, "Maximum attempts for each Jira and Vertex AI request (1 disables retries)")
+	rootCmd.PersistentFlags().DurationVar(&maxRetryWait, "max-retry-elapsed", 0, "Stop retrying a Jira or Vertex AI request once a retry would start this long after its first attempt (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&fetchTimezone, "fetch-user-timezone", false, "Look up the assignee's time zone for the prompt (requires a token)")
+	rootCmd.PersistentFlags().BoolVar(&fetchProject, "fetch-project", false, "Look up each ticket's project description and lead for the prompt")
+	rootCmd.PersistentFlags().StringSliceVar(&customFields, "custom-fields", nil, "Comma-separated custom field IDs to include in the prompt by field name (e.g. customfield_12313942)")
+	rootCmd.PersistentFlags().StringArrayVar(&jiraHeaders, "jira-header", nil, `Extra header for every Jira request, as "Key: Value" (repeatable), e.g. for auth proxies`)
+	rootCmd.PersistentFlags().BoolVar(&logRequests, "log-requests", false, "Log each Jira request (method, URL, status, duration) to stderr for debugging")
+	rootCmd.PersistentFlags().IntVar(&maxDisplayComponents, "max-display-components", 10, `Components shown in the ticket summary before "... and N more" (0 for all); prompts and saved headers always list every component`)
+	rootCmd.PersistentFlags().BoolVar(&skipValidation, "skip-validation", false, "Allow tickets with missing required fields (e.g. an empty summary)")
+	rootCmd.Flags().BoolVar(&skipAuthTest, "skip-auth-test", false, "Skip the authentication check before fetching tickets (auth errors still surface on fetch)")
+	rootCmd.Flags().StringVar(&jql, "jql", "", "Generate plans for every ticket matching a JQL query")
+	rootCmd.Flags().StringVar(&since, "since", "", `With --jql, only plan tickets updated within a duration (e.g. "24h", "7d") or since a date (e.g. "2025-01-31")`)
+	rootCmd.Flags().BoolVar(&assigneeMe, "assignee-me", false, "With --jql or --project, only plan tickets assigned to you (requires a token)")
+	rootCmd.Flags().StringVar(&ticketsFile, "tickets-file", "", "File of ticket IDs to plan, one per line (# comments allowed), combined with any TICKET_ID arguments")
+	rootCmd.Flags().IntVar(&boardID, "board", 0, "Generate plans for every ticket in the active sprint of an Agile board")
+	rootCmd.Flags().IntVar(&maxResults, "max-results", 50, "Maximum number of tickets to plan from a --jql query (0 for no limit)")
+	rootCmd.Flags().BoolVar(&postComment, "post-comment", false, "Post each generated plan as a comment on its ticket (requires a token)")
+	rootCmd.Flags().StringVar(&commentVisibility, "comment-visibility", "", `Restrict posted comments to a role or group, e.g. "role:Developers" or "group:jira-users"`)
+	rootCmd.Flags().StringVar(&commentFormat, "comment-format", "", "Format of posted comments: wiki or markdown (defaults to wiki on Jira Server/Data Center, markdown otherwise)")
+	rootCmd.Flags().BoolVar(&allowRestricted, "allow-restricted", false, "With --post-comment, also post plans on tickets that have a security level")
+	rootCmd.Flags().BoolVar(&attachPlan, "attach-plan", false, "Upload each saved plan file as an attachment on its ticket (requires a token)")
+	rootCmd.Flags().StringVar(&transition, "transition", "", `Move each ticket through this workflow transition (or to this status) after its plan is generated, e.g. "In Progress" (requires a token)`)
+	rootCmd.Flags().BoolVar(&dedupePlans, "dedupe", false, "In batch runs, save plans identical to an earlier one as a short reference to it")
+	rootCmd.Flags().BoolVar(&skipClosed, "skip-closed", false, "Don't plan tickets whose status is in the done category (e.g. Closed, Resolved, Verified)")
+	rootCmd.Flags().StringSliceVar(&statusFilter, "status", nil, `Only plan tickets with one of these comma-separated status names, e.g. "To Do,In Progress"`)
+	rootCmd.Flags().BoolVar(&interactive, "interactive", false, "Pick which of the --jql or --project tickets to plan from a fuzzy-searchable list (requires a terminal)")
+	rootCmd.Flags().StringVar(&pickProject, "project", "", "With --interactive, pick from the project's most recently updated tickets (up to --max-results)")
+	rootCmd.Flags().BoolVar(&expandChildren, "expand-children", false, "Plan the subtasks and epic children of each fetched ticket instead of the ticket itself")
+	rootCmd.Flags().StringSliceVar(&contextTickets, "context-tickets", nil, fmt.Sprintf("Comma-separated related tickets whose key, summary and status are included in the prompt for context (at most %d)", maxContextTickets))
+	rootCmd.Flags().IntVar(&maxContextChars, "max-context-chars", 4000, "Maximum characters of --context-tickets text included in the prompt (0 for unlimited)")
+	rootCmd.Flags().BoolVar(&summarizeComments, "summarize-comments", false, "Include the ticket's comments in the prompt ({{.CommentSummary}}), condensing long threads with a cheaper model")
+	rootCmd.Flags().IntVar(&summarizeThreshold, "summarize-threshold", DefaultSummarizeThreshold, "Number of comments at which --summarize-comments condenses the thread instead of including it verbatim")
+	rootCmd.Flags().IntVar(&maxCommentChars, "max-comment-chars", DefaultMaxCommentChars, "Maximum characters of a comment thread included verbatim by --summarize-comments, keeping the latest comments (0 for unlimited)")
+	rootCmd.Flags().StringVar(&summaryModel, "summary-model", DefaultReviewModel, "Model used by --summarize-comments")
+	rootCmd.Flags().IntVar(&retryBudget, "retry-budget", 10, "Maximum total Jira retries across the whole run before remaining requests fail fast (0 for no limit)")
+	rootCmd.Flags().DurationVar(&runTimeout, "run-timeout", 0, "Stop the whole run after this long, failing in-flight Jira requests and skipping the remaining tickets (0 for no limit)")
+	addGenerationFlags(rootCmd)
+
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(initTemplateCmd)
+	rootCmd.AddCommand(generateFromFileCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// addGenerationFlags registers the flags controlling prompt rendering, plan output
+// and saving on a command that generates implementation plans
+func addGenerationFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the plan to this file instead of a timestamped file in "+DefaultOutputDir+"/")
+	cmd.Flags().StringVar(&organizeBy, "organize-by", OrganizeFlat, "Nest saved plans in "+DefaultOutputDir+"/ by project, date (month), or project-date, e.g. "+DefaultOutputDir+"/RHEL/2025-06/")
+	cmd.Flags().BoolVar(&cacheResponses, "cache-responses", false, "Reuse the cached Claude response for an identical request (same model, prompt and settings) instead of calling Vertex AI again")
+	cmd.Flags().BoolVar(&forceCache, "force-cache", false, "With --cache-responses, also cache responses generated at a temperature above 0")
+	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", DefaultCacheTTL, "How long cached responses are reused with --cache-responses")
+	cmd.Flags().Float64Var(&temperature, "temperature", DefaultTemperature, "Sampling temperature from 0 to 1; 0 gives the most repeatable plans, which --cache-responses can cache")
+	cmd.Flags().StringVar(&promptCache, "prompt-cache", PromptCacheAuto, "Mark the part of the prompt shared by every ticket for Anthropic prompt caching: auto (batch runs only), on or off")
+	cmd.Flags().BoolVar(&selfReview, "self-review", false, "Critique the plan against the ticket with a second, cheaper model and append its notes in a Review Notes section")
+	cmd.Flags().StringVar(&reviewModel, "review-model", DefaultReviewModel, "Model used by --self-review")
+	cmd.Flags().BoolVar(&includeThinking, "include-thinking", false, "Save any extended-thinking blocks in the response to a .thinking.md file next to the plan")
+	cmd.Flags().BoolVar(&addTOC, "add-toc", false, "Insert a linked table of contents of the plan's headings at the top of the plan")
+	cmd.Flags().IntVar(&minPlanChars, "min-plan-chars", 0, "Retry generation once if the plan is shorter than this many characters (0 to disable)")
+	cmd.Flags().IntVar(&minPlanLines, "min-plan-lines", 0, "Retry generation once if the plan has fewer than this many non-blank lines (0 to disable)")
+	cmd.Flags().BoolVar(&copyToClipboard, "clipboard", false, "Also copy the generated plan to the system clipboard (in batch runs, the last plan wins)")
+	cmd.Flags().StringVar(&fileHeader, "file-header", "", "Text or path of a file to add above the metadata in saved plans (supports template variables)")
+	cmd.Flags().StringVar(&fileFooter, "file-footer", "", "Text or path of a file to add below the plan in saved plans (supports template variables)")
+	cmd.Flags().BoolVar(&structuredOutput, "structured", false, "Ask for the plan as JSON tasks (title, description, estimate) and save it as a .json file")
+	cmd.Flags().StringVar(&outputFormat, "format", FormatMarkdown, "Format of the saved plan file: markdown or html")
+	cmd.Flags().BoolVar(&appendPlan, "append", false, "Append the plan to the --output file (with a divider and timestamp) instead of overwriting it")
+	cmd.Flags().StringSliceVar(&headerFields, "header-fields", defaultHeaderFields, "Comma-separated, ordered metadata fields for saved plan headers ("+strings.Join(validHeaderFields(), ", ")+")")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Save only the plan body, without the title, metadata header or separator")
+	cmd.Flags().BoolVar(&renderMarkdown, "render-markdown", false, "Render the plan with terminal styling when stdout is a TTY (saved file stays raw markdown)")
+	cmd.Flags().IntVar(&maxDescriptionChars, "max-description-chars", 0, "Truncate the ticket description sent to the LLM to this many characters (0 for unlimited)")
+	cmd.Flags().StringVar(&planLanguage, "plan-language", "", `Language to write the plan in, e.g. "Brazilian Portuguese" (defaults to English)`)
+	cmd.Flags().StringArrayVar(&varFlags, "var", nil, `Template variable as key=value, available as {{.Vars.key}} (repeatable)`)
+	cmd.Flags().StringVar(&planModel, "model", DefaultModel, "Model used to generate plans")
+	cmd.Flags().StringArrayVar(&typeModelFlags, "type-model", nil, `Model for tickets of an issue type, as Type=model, e.g. "Bug=claude-opus-4@20250514" (repeatable; defaults to --model)`)
+	cmd.Flags().StringArrayVar(&typeTemplateFlags, "type-template", nil, `Template for tickets of an issue type, as Type=path, e.g. "Bug=prompts/bug.md" (repeatable; defaults to --template)`)
+	cmd.Flags().StringVar(&promptPrefix, "prompt-prefix", "", "Text to add before the rendered prompt, for one-off instructions")
+	cmd.Flags().StringVar(&promptSuffix, "prompt-suffix", "", `Text to add after the rendered prompt, e.g. "Keep the plan under 300 words"`)
+	cmd.Flags().StringVar(&diffFile, "diff-file", "", "Unified diff of existing code changes to include in the prompt ({{.Diff}})")
+	cmd.Flags().IntVar(&maxDiffChars, "max-diff-chars", 50000, "Truncate the --diff-file content sent to the LLM to this many characters (0 for unlimited)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print additional details, such as the estimated prompt size")
+	cmd.Flags().BoolVar(&streamOutput, "stream", false, "Stream the plan to the terminal as it is generated; Ctrl-C saves the partial plan")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Don't print the timing summary at the end of the run")
+	cmd.Flags().BoolVar(&forceSize, "force", false, "Send prompts even when they are estimated to exceed the model's context window")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Render and print the prompt with its estimated token count without calling the model")
+	cmd.Flags().BoolVar(&strictXML, "strict-xml", false, "Fail on POML template elements or attributes the renderer doesn't recognize (e.g. typos)")
+	cmd.Flags().StringVar(&templatePath, "template", "", `Path or http(s) URL of a custom prompt template file, or "-" to read the prompt from stdin (defaults to $JIG_TEMPLATE, then prompts/implementation-plan.poml or .md, then the built-in template)`)
+	cmd.Flags().BoolVar(&stdinAsTemplate, "stdin-as-template", false, "With --template -, execute the prompt read from stdin as a Go template against each ticket instead of sending it verbatim")
+}
+
+// prepareGeneration validates the generation flags and returns the template path to render,
+// exiting with an error message if the flags are invalid
+func prepareGeneration() string {
+	fields, err := validateHeaderFields(headerFields)
+	if err != nil {
+		color.Red("❌ Invalid --header-fields: %v", err)
+		os.Exit(1)
+	}
+	headerFields = fields
+
+	if organizeBy, err = validateOrganizeBy(organizeBy); err != nil {
+		color.Red("❌ Invalid --organize-by: %v", err)
+		os.Exit(1)
+	}
+
+	if promptCache, err = validatePromptCache(promptCache); err != nil {
+		color.Red("❌ Invalid --prompt-cache: %v", err)
+		os.Exit(1)
+	}
+	usePromptCache = promptCacheEnabled(promptCache, false)
+
+	if appendPlan && outputPath == "" {
+		color.Red("❌ --append requires --output")
+		os.Exit(1)
+	}
+
+	if structuredOutput {
+		conflicts := []struct {
+			flag string
+			set  bool
+		}{
+			{"--append", appendPlan},
+			{"--self-review", selfReview},
+			{"--format html", outputFormat == FormatHTML},
+		}
+		for _, conflict := range conflicts {
+			if conflict.set {
+				color.Red("❌ %s cannot be used with --structured", conflict.flag)
+				os.Exit(1)
+			}
+		}
+	}
+
+	switch outputFormat {
+	case FormatMarkdown:
+	case FormatHTML:
+		if appendPlan {
+			color.Red("❌ --append is only supported with --format markdown")
+			os.Exit(1)
+		}
+	default:
+		color.Red("❌ Invalid --format %q: must be %s or %s", outputFormat, FormatMarkdown, FormatHTML)
+		os.Exit(1)
+	}
+
+	if fileHeaderText, err = loadBrandingText(fileHeader); err != nil {
+		color.Red("❌ Invalid --file-header: %v", err)
+		os.Exit(1)
+	}
+	if fileFooterText, err = loadBrandingText(fileFooter); err != nil {
+		color.Red("❌ Invalid --file-footer: %v", err)
+		os.Exit(1)
+	}
+
+	if templateVars, err = parseTemplateVars(varFlags); err != nil {
+		color.Red("❌ Invalid --var: %v", err)
+		os.Exit(1)
+	}
+	if typeConfig.Models, err = parseIssueTypeMap(typeModelFlags); err != nil {
+		color.Red("❌ Invalid --type-model: %v", err)
+		os.Exit(1)
+	}
+	if typeConfig.Templates, err = parseIssueTypeMap(typeTemplateFlags); err != nil {
+		color.Red("❌ Invalid --type-template: %v", err)
+		os.Exit(1)
+	}
+
+	if temperature < 0 || temperature > 1 {
+		color.Red("❌ Invalid --temperature %g: must be between 0 and 1", temperature)
+		os.Exit(1)
+	}
+	if forceCache && !cacheResponses {
+		color.Red("❌ --force-cache requires --cache-responses")
+		os.Exit(1)
+	}
+	if cacheResponses && temperature != 0 && !forceCache {
+		color.Yellow("⚠️  Warning: --cache-responses only stores responses generated with --temperature 0 (use --force-cache to store others)")
+	}
+	if cacheResponses {
+		dir, err := defaultCacheDir()
+		if err != nil {
+			color.Red("❌ Failed to locate the cache directory: %v", err)
+			os.Exit(1)
+		}
+		responses = newResponseCache(dir, cacheTTL, time.Now)
+	}
+
+	if diffFile != "" {
+		data, err := os.ReadFile(diffFile)
+		if err != nil {
+			color.Red("❌ Failed to read --diff-file: %v", err)
+			os.Exit(1)
+		}
+		diffContent = string(data)
+	}
+
+	if templatePath == stdinTemplatePath {
+		if interactive {
+			color.Red("❌ --template - cannot be used with --interactive, which reads from stdin")
+			os.Exit(1)
+		}
+		if isTerminal(os.Stdin) {
+			color.Red("❌ --template - requires a prompt piped on stdin")
+			os.Exit(1)
+		}
+		if stdinPrompt, err = readStdinPrompt(os.Stdin); err != nil {
+			color.Red("❌ %v", err)
+			os.Exit(1)
+		}
+		return stdinTemplatePath
+	}
+	if stdinAsTemplate {
+		color.Red("❌ --stdin-as-template requires --template -")
+		os.Exit(1)
+	}
+
+	path, source := generationTemplatePath()
+	if verbose {
+		color.Cyan("📄 Using template from %s: %s", source, path)
+	}
+	return path
+}
+
+// generationTemplatePath resolves the template to render and where it came from.
+// Without --template, explain and --structured use their own embedded templates.
+func generationTemplatePath() (string, string) {
+	switch {
+	case explaining && templatePath == "":
+		// Plan templates from $JIG_TEMPLATE or the working directory don't apply to explain
+		return prompt.EmbeddedExplainTemplatePath, prompt.SourceEmbedded
+	case structuredOutput && templatePath == "":
+		// The default prose templates don't ask for JSON
+		return prompt.EmbeddedStructuredTemplatePath, prompt.SourceEmbedded
+	}
+	return prompt.ResolveTemplatePath(templatePath)
 }
 
 func main() {
@@ -63,161 +461,1074 @@ func main() {
 	}
 }
 
-func runJiraGenerator(ctx context.Context, ticketID string) {
-	// Get token from flag or environment variable
+// newJiraClient creates a Jira client from the CLI flags, falling back to the
+// JIRA_TOKEN and JIRA_EMAIL environment variables when the flags weren't given
+func newJiraClient(extraOpts ...jira.ClientOption) *jira.Client {
 	if token == "" {
 		token = os.Getenv("JIRA_TOKEN")
 	}
+	if jiraEmail == "" {
+		jiraEmail = os.Getenv("JIRA_EMAIL")
+	}
+	if jiraEmail != "" && token == "" {
+		color.Red("❌ --jira-email requires a token (an Atlassian API token for Jira Cloud)")
+		os.Exit(1)
+	}
 
-	// Initialize Jira client with optional authentication and custom base URL
-	var jiraClient *jira.Client
-	if token != "" {
+	opts := []jira.ClientOption{
+		jira.WithBaseURL(jiraBaseURL),
+		jira.WithSprintField(sprintField),
+		jira.WithEpicLinkField(epicLinkField),
+		jira.WithMaxAttempts(maxAttempts),
+		jira.WithMaxRetryElapsed(maxRetryWait),
+	}
+	switch {
+	case jiraEmail != "":
+		opts = append(opts, jira.WithBasicAuth(jiraEmail, token))
+	case token != "":
+		opts = append(opts, jira.WithToken(token))
+	}
+	if skipValidation {
+		opts = append(opts, jira.WithoutValidation())
+	}
+	if fetchTimezone {
+		opts = append(opts, jira.WithFetchUserTimezone())
+	}
+	if fetchProject {
+		opts = append(opts, jira.WithFetchProject())
+	}
+	if len(customFields) > 0 {
+		opts = append(opts, jira.WithCustomFields(customFields...))
+	}
+	if logRequests {
+		handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		opts = append(opts, jira.WithLogger(slog.New(handler)))
+	}
+	for _, header := range jiraHeaders {
+		key, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			color.Red(`❌ Invalid --jira-header %q: expected "Key: Value"`, header)
+			os.Exit(1)
+		}
+		opts = append(opts, jira.WithHeader(strings.TrimSpace(key), strings.TrimSpace(value)))
+	}
+
+	client := jira.NewClient(append(opts, extraOpts...)...)
+	// Remote templates are fetched through the same transport and timeout as Jira
+	prompt.SetHTTPClient(client.HTTPClient)
+	return client
+}
+
+// retrySpinner is a spinner whose suffix reflects the Jira client's current retry attempt
+type retrySpinner struct {
+	spinner *spinner.Spinner
+	label   string
+}
+
+// start replaces the active spinner and starts it with the given label
+func (r *retrySpinner) start(s *spinner.Spinner, label string) {
+	r.spinner, r.label = s, label
+	s.Suffix = fmt.Sprintf(" %s...", label)
+	s.Start()
+}
+
+// stop stops the active spinner
+func (r *retrySpinner) stop() {
+	if r.spinner != nil {
+		r.spinner.Stop()
+		r.spinner = nil
+	}
+}
+
+// notify updates the active spinner's suffix; it is used as a jira.RetryNotifyFunc
+func (r *retrySpinner) notify(attempt int, err error) {
+	if r.spinner == nil {
+		return
+	}
+	r.spinner.Lock()
+	r.spinner.Suffix = fmt.Sprintf(" %s (attempt %d/%d)...", r.label, attempt, maxAttempts)
+	r.spinner.Unlock()
+}
+
+// newAnthropicClient creates an Anthropic client authenticated against Vertex AI.
+// Requests are retried with the same backoff policy as Jira requests rather than
+// the SDK's own retries, so --max-attempts and --max-retry-elapsed apply to both.
+func newAnthropicClient(ctx context.Context) anthropic.Client {
+	policy := backoff.Policy{MaxAttempts: maxAttempts, MaxElapsed: maxRetryWait}
+	return anthropic.NewClient(
+		vertex.WithGoogleAuth(ctx, region, projectID),
+		option.WithMaxRetries(0),
+		option.WithMiddleware(func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+			return backoff.Do(req, policy, next, notifyVertexRetry)
+		}),
+	)
+}
+
+// notifyVertexRetry reports a retried Vertex AI request in verbose mode
+func notifyVertexRetry(attempt int, err error, delay time.Duration) {
+	if verbose {
+		color.Yellow("⚠️  Vertex AI request failed (%v); retrying in %s (attempt %d/%d)", err, delay.Round(time.Millisecond), attempt, maxAttempts)
+	}
+}
+
+// checkAuthentication reports the authentication mode and, unless
+// --skip-auth-test is set, verifies the token before any tickets are fetched
+func checkAuthentication(jiraClient *jira.Client, progress *retrySpinner) error {
+	switch {
+	case token != "" && skipAuthTest:
+		color.Blue("🔐 Using Personal Access Token for authentication (authentication check skipped)")
+	case token != "":
 		color.Blue("🔐 Using Personal Access Token for authentication")
-		jiraClient = jira.NewClient(
-			jira.WithToken(token),
-			jira.WithBaseURL(jiraBaseURL),
-		)
 
 		// Test authentication with spinner
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Suffix = " Testing authentication..."
-		s.Start()
-		if err := jiraClient.TestAuthentication(); err != nil {
-			s.Stop()
-			color.Red("❌ Authentication failed: %v", err)
-			os.Exit(1)
+		progress.start(spinner.New(spinner.CharSets[14], 100*time.Millisecond), "Testing authentication")
+		err := jiraClient.TestAuthentication()
+		progress.stop()
+		if err != nil {
+			return err
 		}
-		s.Stop()
 		color.Green("✅ Authentication successful")
-	} else {
+	default:
 		color.Yellow("🌐 Using anonymous access (public tickets only)")
-		jiraClient = jira.NewClient(jira.WithBaseURL(jiraBaseURL))
+	}
+	return nil
+}
+
+func runJiraGenerator(ctx context.Context, ticketIDs []string) {
+	templateFilePath := prepareGeneration()
+	timer := newPhaseTimer(time.Now)
+
+	// Bound the whole run with --run-timeout. Jira requests share the deadline but
+	// not Ctrl-C, so a partial plan is still saved and posted after an interrupt.
+	jiraCtx := context.Background()
+	if runTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, runTimeout)
+		defer cancel()
+		deadline, _ := ctx.Deadline()
+		jiraCtx, cancel = context.WithDeadline(jiraCtx, deadline)
+		defer cancel()
+	}
+
+	// Initialize Jira client with optional authentication and custom base URL
+	progress := &retrySpinner{}
+	clientOpts := []jira.ClientOption{jira.WithContext(jiraCtx), jira.WithRetryNotify(progress.notify)}
+	if retryBudget > 0 {
+		clientOpts = append(clientOpts, jira.WithRetryBudget(jira.NewRetryBudget(retryBudget)))
+	}
+	jiraClient := newJiraClient(clientOpts...)
+	defer jiraClient.Close()
+	if err := checkAuthentication(jiraClient, progress); err != nil {
+		color.Red("❌ Authentication failed: %v", err)
+		os.Exit(1)
 	}
 
 	color.Cyan("🏠 Using Jira instance: %s", jiraBaseURL)
 
-	// Fetch Jira ticket with spinner
-	s := spinner.New(spinner.CharSets[35], 100*time.Millisecond)
-	s.Suffix = fmt.Sprintf(" Fetching Jira ticket: %s", ticketID)
-	s.Start()
-	ticket, err := jiraClient.GetTicket(ticketID)
-	s.Stop()
+	visibility := commentPostingOptions()
+	fetchContextTickets(jiraClient, progress)
+
+	// A JQL query, a board, multiple ticket IDs or expanding children make this a
+	// batch run, where a failing ticket is reported and skipped rather than
+	// aborting the whole run
+	batch := jql != "" || pickProject != "" || boardID > 0 || len(ticketIDs) > 1 || expandChildren
+	usePromptCache = promptCacheEnabled(promptCache, batch)
+	failures := 0
+	if batch && outputPath != "" {
+		color.Red("❌ --output cannot be used when generating multiple plans")
+		os.Exit(1)
+	}
+	if structuredOutput && postComment {
+		color.Red("❌ --post-comment cannot be used with --structured")
+		os.Exit(1)
+	}
+
+	if pickProject != "" || interactive {
+		switch {
+		case !interactive:
+			color.Red("❌ --project requires --interactive")
+			os.Exit(1)
+		case pickProject != "" && jql != "":
+			color.Red("❌ --project cannot be used with --jql")
+			os.Exit(1)
+		case pickProject == "" && jql == "":
+			color.Red("❌ --interactive requires --jql or --project")
+			os.Exit(1)
+		case !isTerminal(os.Stdin) || !isTerminal(os.Stdout):
+			color.Red("❌ --interactive requires a terminal")
+			os.Exit(1)
+		}
+		if pickProject != "" {
+			jql = projectJQL(pickProject)
+		}
+	}
+
+	if assigneeMe {
+		switch {
+		case jql == "":
+			color.Red("❌ --assignee-me requires --jql or --project")
+			os.Exit(1)
+		case token == "":
+			color.Red("❌ --assignee-me requires a token, since anonymous requests have no current user")
+			os.Exit(1)
+		}
+		jql = addJQLClause(jql, assigneeMeClause)
+	}
+	if since != "" {
+		if jql == "" {
+			color.Red("❌ --since requires --jql")
+			os.Exit(1)
+		}
+		clause, err := sinceClause(since)
+		if err != nil {
+			color.Red("❌ Invalid --since: %v", err)
+			os.Exit(1)
+		}
+		jql = addJQLClause(jql, clause)
+	}
+	if verbose && (assigneeMe || since != "") {
+		color.Cyan("🔎 JQL: %s", jql)
+	}
+
+	var tickets []*jira.Ticket
+	if jql != "" {
+		progress.start(spinner.New(spinner.CharSets[35], 100*time.Millisecond), "Searching Jira tickets")
+		done := timer.track("", "fetch")
+		found, err := jiraClient.SearchTickets(jql, maxResults)
+		done()
+		progress.stop()
+		skipped, err := reportInvalidIssues(err)
+		failures += skipped
+		if err != nil {
+			observer.OnError("", err)
+			color.Red("❌ Failed to search Jira tickets: %v", err)
+			os.Exit(1)
+		}
+		for _, ticket := range found {
+			observer.OnTicketFetched(ticket)
+		}
+		color.Green("\n✅ Found %d tickets matching JQL", len(found))
+		if interactive {
+			if found, err = pickTickets(found, os.Stdin, os.Stdout); err != nil {
+				color.Red("❌ %v", err)
+				os.Exit(1)
+			}
+			color.Green("✅ Selected %d tickets", len(found))
+		}
+		tickets = append(tickets, found...)
+	}
+
+	if boardID > 0 {
+		progress.start(spinner.New(spinner.CharSets[35], 100*time.Millisecond), fmt.Sprintf("Fetching active sprint for board %d", boardID))
+		done := timer.track("", "fetch")
+		found, err := jiraClient.GetActiveSprintIssues(boardID)
+		done()
+		progress.stop()
+		skipped, err := reportInvalidIssues(err)
+		failures += skipped
+		if err != nil {
+			observer.OnError("", err)
+			color.Red("❌ Failed to fetch active sprint tickets: %v", err)
+			os.Exit(1)
+		}
+		for _, ticket := range found {
+			observer.OnTicketFetched(ticket)
+		}
+		color.Green("\n✅ Found %d tickets in the active sprint of board %d", len(found), boardID)
+		tickets = append(tickets, found...)
+	}
+
+	// Fetch several tickets in bulk, reporting each one that couldn't be fetched,
+	// or a single ticket directly
+	if len(ticketIDs) > 1 {
+		progress.start(spinner.New(spinner.CharSets[35], 100*time.Millisecond), fmt.Sprintf("Fetching %d Jira tickets", len(ticketIDs)))
+		done := timer.track("", "fetch")
+		found, err := jiraClient.GetTickets(ticketIDs)
+		done()
+		progress.stop()
+		if jira.IsUnauthorized(err) {
+			observer.OnError("", err)
+			color.Red("❌ Failed to fetch Jira tickets: %v", err)
+			os.Exit(1)
+		}
+		if err != nil {
+			for _, fetchErr := range unwrapJoined(err) {
+				observer.OnError("", fetchErr)
+				color.Red("❌ Failed to fetch Jira ticket: %v", fetchErr)
+			}
+			failures += len(ticketIDs) - len(found)
+		}
+		for _, ticket := range found {
+			observer.OnTicketFetched(ticket)
+		}
+		color.Green("\n✅ Successfully fetched %d of %d tickets", len(found), len(ticketIDs))
+		tickets = append(tickets, found...)
+	} else if len(ticketIDs) == 1 {
+		ticketID := ticketIDs[0]
+		progress.start(spinner.New(spinner.CharSets[35], 100*time.Millisecond), fmt.Sprintf("Fetching Jira ticket: %s", ticketID))
+		done := timer.track(ticketID, "fetch")
+		ticket, err := jiraClient.GetTicket(ticketID)
+		done()
+		progress.stop()
+		if err != nil {
+			observer.OnError(ticketID, err)
+			color.Red("❌ Failed to fetch Jira ticket: %v", err)
+			if !batch {
+				os.Exit(1)
+			}
+			failures++
+		} else {
+			observer.OnTicketFetched(ticket)
+			color.Green("\n✅ Successfully fetched ticket %s", ticket.Key)
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	if expandChildren {
+		var expandFailures int
+		tickets, expandFailures = expandChildTickets(jiraClient, progress, timer, tickets)
+		failures += expandFailures
+	}
+
+	if skipClosed || len(statusFilter) > 0 {
+		var skipped []skippedTicket
+		tickets, skipped = filterTicketsByStatus(tickets, skipClosed, statusFilter)
+		for _, skip := range skipped {
+			color.Yellow("⏭️  Skipping %s: %s", skip.Ticket.Key, skip.Reason)
+		}
+	}
+
+	// Initialize Anthropic client
+	var client anthropic.Client
+	if !dryRun {
+		color.Cyan("☁️  Using Google Cloud region: %s, project: %s", region, projectID)
+		client = newAnthropicClient(ctx)
+	}
+
+	var dedupe *planDeduper
+	if dedupePlans {
+		dedupe = newPlanDeduper()
+	}
+
+	// Batch runs report overall progress; on a terminal the bar replaces the per-ticket spinners
+	var batchBar *batchProgress
+	if batch {
+		tty := isTerminal(os.Stdout)
+		batchBar = newBatchProgress(newProgressSink(os.Stdout, tty), len(tickets), time.Now)
+		showSpinners = !tty
+	}
+
+	var saved []savedPlan
+	for i, ticket := range tickets {
+		if ctx.Err() != nil {
+			break
+		}
+		if batch {
+			color.HiCyan("\n[%d/%d] %s", i+1, len(tickets), ticket.Key)
+		}
+
+		commentOpts := commentSummaryOptions(ctx, jiraClient, client, ticket, progress, timer)
+		plan, err := generatePlan(ctx, client, ticket, templateFilePath, timer, dedupe, commentOpts...)
+		if batch {
+			batchBar.advance(ticket.Key)
+		}
+		if err != nil {
+			if !batch {
+				os.Exit(1)
+			}
+			failures++
+			continue
+		}
+		if plan != nil {
+			saved = append(saved, *plan)
+			if postComment {
+				postPlanComment(jiraClient, progress, plan, visibility)
+			}
+			if attachPlan {
+				attachPlanFile(jiraClient, progress, plan)
+			}
+			if transition != "" && (plan.Gen == nil || !plan.Gen.Interrupted) {
+				transitionPlannedTicket(jiraClient, progress, plan.Ticket)
+			}
+		}
+	}
+
+	writeRunManifest(saved)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		color.Red("❌ Run timed out after %s (--run-timeout); %d of %d tickets planned", runTimeout, len(saved), len(tickets))
+		os.Exit(1)
+	}
+	if ctx.Err() != nil {
+		color.Red("❌ Interrupted")
+		os.Exit(exitInterrupted)
+	}
+
+	if !quiet {
+		printTimingSummary(timer, batch)
+	}
+
+	if !batch || dryRun {
+		return
+	}
+
+	if len(saved) > 0 {
+		indexPath, err := writePlanIndex(DefaultOutputDir, saved)
+		if err != nil {
+			color.Yellow("⚠️  Warning: Failed to write plan index: %v", err)
+		} else {
+			color.Green("\n📇 Plan index updated: %s", indexPath)
+		}
+	}
+
+	color.Green("✅ Generated %d of %d implementation plans", len(saved), len(tickets)+failures)
+	if failures > 0 {
+		color.Red("❌ %d tickets failed", failures)
+		os.Exit(1)
+	}
+}
+
+// unwrapJoined returns the errors combined by errors.Join, or err itself
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// reportInvalidIssues warns about each issue a search skipped as invalid,
+// returning how many were skipped and any other errors in err
+func reportInvalidIssues(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	skipped := 0
+	var others []error
+	for _, e := range unwrapJoined(err) {
+		var invalid *jira.InvalidIssueError
+		if !errors.As(e, &invalid) {
+			others = append(others, e)
+			continue
+		}
+		observer.OnError(invalid.TicketID, invalid)
+		color.Yellow("⚠️  Warning: Skipping %s: %v", invalid.TicketID, invalid.Err)
+		skipped++
+	}
+	return skipped, errors.Join(others...)
+}
+
+// commentPostingOptions validates the comment posting flags, returning the parsed
+// visibility restriction (nil for none) or exiting with an error message
+func commentPostingOptions() *jira.CommentVisibility {
+	if commentVisibility != "" && !postComment {
+		color.Red("❌ --comment-visibility requires --post-comment")
+		os.Exit(1)
+	}
+	if commentFormat != "" && !postComment {
+		color.Red("❌ --comment-format requires --post-comment")
+		os.Exit(1)
+	}
+	if allowRestricted && !postComment {
+		color.Red("❌ --allow-restricted requires --post-comment")
+		os.Exit(1)
+	}
+	if commentFormat != "" && commentFormat != FormatWiki && commentFormat != FormatMarkdown {
+		color.Red("❌ Invalid --comment-format %q: must be %s or %s", commentFormat, FormatWiki, FormatMarkdown)
+		os.Exit(1)
+	}
+	if attachPlan && token == "" {
+		color.Red("❌ --attach-plan requires a Personal Access Token; anonymous access cannot upload attachments")
+		os.Exit(1)
+	}
+	if transition != "" && token == "" {
+		color.Red("❌ --transition requires a Personal Access Token; anonymous access cannot transition tickets")
+		os.Exit(1)
+	}
+	if !postComment {
+		return nil
+	}
+	if token == "" {
+		color.Red("❌ --post-comment requires a Personal Access Token; anonymous access cannot post comments")
+		os.Exit(1)
+	}
+	if commentVisibility == "" {
+		return nil
+	}
+
+	visibility, err := jira.ParseCommentVisibility(commentVisibility)
 	if err != nil {
-		color.Red("❌ Failed to fetch Jira ticket: %v", err)
+		color.Red("❌ Invalid --comment-visibility: %v", err)
 		os.Exit(1)
 	}
+	return visibility
+}
 
-	color.Green("\n✅ Successfully fetched ticket")
-	printTicketInfo(ticket)
+// postPlanComment posts a saved plan as a comment on its ticket, warning rather
+// than failing the run if the comment cannot be posted. Plans for tickets with a
+// security level aren't posted unless --allow-restricted is given, since they may
+// disclose restricted details.
+func postPlanComment(client *jira.Client, progress *retrySpinner, plan *savedPlan, visibility *jira.CommentVisibility) {
+	if level := plan.Ticket.SecurityLevel; level != nil && !allowRestricted {
+		color.Yellow("⚠️  Warning: Not posting the plan on %s, which has security level %q (use --allow-restricted to post anyway)", plan.Ticket.Key, level.Name)
+		return
+	}
+	if commentFormat == "" {
+		commentFormat = detectCommentFormat(client)
+	}
+	body := plan.Plan
+	if commentFormat == FormatWiki {
+		body = markdown.RenderWiki(body)
+	}
+
+	progress.start(spinner.New(spinner.CharSets[14], 100*time.Millisecond), fmt.Sprintf("Posting plan as a comment on %s", plan.Ticket.Key))
+	err := client.AddComment(plan.Ticket.Key, body, visibility)
+	progress.stop()
+	if err != nil {
+		color.Yellow("⚠️  Warning: Failed to post comment on %s: %v", plan.Ticket.Key, err)
+		return
+	}
+
+	if visibility != nil {
+		color.Green("💬 Plan posted as a comment on %s (visible to %s %s)", plan.Ticket.Key, visibility.Type, visibility.Value)
+	} else {
+		color.Green("💬 Plan posted as a comment on %s", plan.Ticket.Key)
+	}
+}
+
+// detectCommentFormat picks the comment format for the Jira deployment: wiki markup
+// for Server and Data Center, whose comments don't render markdown, and markdown
+// otherwise or when the deployment type can't be determined
+func detectCommentFormat(client *jira.Client) string {
+	info, err := client.GetServerInfo()
+	if err != nil || info.IsCloud() || info.DeploymentType == "" {
+		return FormatMarkdown
+	}
+	return FormatWiki
+}
+
+// attachPlanFile uploads a saved plan file as an attachment on its ticket, warning
+// rather than failing the run if the upload fails
+func attachPlanFile(client *jira.Client, progress *retrySpinner, plan *savedPlan) {
+	content, err := os.ReadFile(plan.FilePath)
+	if err != nil {
+		color.Yellow("⚠️  Warning: Failed to read %s for attaching: %v", plan.FilePath, err)
+		return
+	}
+
+	filename := filepath.Base(plan.FilePath)
+	progress.start(spinner.New(spinner.CharSets[14], 100*time.Millisecond), fmt.Sprintf("Attaching %s to %s", filename, plan.Ticket.Key))
+	err = client.AddAttachment(plan.Ticket.Key, filename, content)
+	progress.stop()
+	if err != nil {
+		color.Yellow("⚠️  Warning: Failed to attach plan to %s: %v", plan.Ticket.Key, err)
+		return
+	}
+	color.Green("📎 Plan attached to %s as %s", plan.Ticket.Key, filename)
+}
+
+// transitionPlannedTicket moves a ticket through the --transition after its plan
+// is generated, warning rather than failing the run if the transition fails
+func transitionPlannedTicket(client *jira.Client, progress *retrySpinner, ticket *jira.Ticket) {
+	progress.start(spinner.New(spinner.CharSets[14], 100*time.Millisecond), fmt.Sprintf("Transitioning %s to %s", ticket.Key, transition))
+	err := client.TransitionTicket(ticket.Key, transition)
+	progress.stop()
+	if err != nil {
+		color.Yellow("⚠️  Warning: Failed to transition %s: %v", ticket.Key, err)
+		return
+	}
+	color.Green("🔀 %s transitioned with %q", ticket.Key, transition)
+}
+
+// fetchContextTickets fetches the --context-tickets into relatedTickets. Tickets
+// that can't be fetched are skipped with a warning rather than failing the run.
+func fetchContextTickets(jiraClient *jira.Client, progress *retrySpinner) {
+	if len(contextTickets) == 0 {
+		return
+	}
+	if len(contextTickets) > maxContextTickets {
+		color.Red("❌ Too many --context-tickets: %d (at most %d)", len(contextTickets), maxContextTickets)
+		os.Exit(1)
+	}
+
+	progress.start(spinner.New(spinner.CharSets[35], 100*time.Millisecond), fmt.Sprintf("Fetching %d context tickets", len(contextTickets)))
+	tickets, err := jiraClient.GetTickets(contextTickets)
+	progress.stop()
+	if err != nil {
+		for _, err := range unwrapJoined(err) {
+			color.Yellow("⚠️  Warning: Skipping context ticket: %v", err)
+		}
+	}
 
-	// Determine template path
-	templateFilePath := templatePath
-	if templateFilePath == "" {
-		templateFilePath = prompt.GetDefaultTemplatePath()
+	relatedTickets = tickets
+	if len(tickets) > 0 {
+		color.Green("✅ Including %d related tickets for context", len(tickets))
 	}
+}
+
+// parseTemplateVars parses --var key=value flags into a map, rejecting malformed
+// entries and keys given more than once
+func parseTemplateVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%q must be in key=value format", flag)
+		}
+		if _, exists := vars[key]; exists {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// renderOptions builds the prompt rendering options from the CLI flags
+func renderOptions() []prompt.RenderOption {
+	opts := []prompt.RenderOption{
+		prompt.WithMaxDescriptionChars(maxDescriptionChars),
+		prompt.WithLanguage(planLanguage),
+		prompt.WithPromptPrefix(promptPrefix),
+		prompt.WithPromptSuffix(promptSuffix),
+		prompt.WithDiff(diffContent, maxDiffChars),
+		prompt.WithVars(templateVars),
+	}
+	if strictXML {
+		opts = append(opts, prompt.WithStrictXML())
+	}
+	if len(relatedTickets) > 0 {
+		opts = append(opts, prompt.WithRelatedTickets(relatedTickets, maxContextChars))
+	}
+	return opts
+}
+
+// generatePlan renders the prompt for a ticket, generates its implementation plan,
+// prints it and saves it to the output directory, recording the render and generate
+// phases with timer. Plans identical to one already seen by dedupe are saved as a
+// reference to it. Extra render options, such as the ticket's comment summary, are
+// applied after the flag options. It returns the saved plan, which is nil if saving
+// failed or in dry-run mode. Errors are reported to the user before returning.
+func generatePlan(ctx context.Context, client anthropic.Client, ticket *jira.Ticket, templateFilePath string, timer *phaseTimer, dedupe *planDeduper, extraOpts ...prompt.RenderOption) (*savedPlan, error) {
+	printTicketInfo(ticket)
+	templateFilePath = typeConfig.template(ticket, templateFilePath)
+	model := typeConfig.model(ticket, planModel)
 
 	// Load and render prompt template
-	color.Cyan("\n📋 Loading prompt template: %s", templateFilePath)
-	promptText, err := prompt.LoadAndRenderTemplate(templateFilePath, ticket)
+	if templateFilePath == stdinTemplatePath {
+		color.Cyan("\n📋 Using prompt from stdin")
+	} else {
+		color.Cyan("\n📋 Loading prompt template: %s", templateFilePath)
+	}
+	done := timer.track(ticket.Key, "render")
+	var promptText string
+	var unusedWarnings []string
+	var err error
+	opts := append(renderOptions(), extraOpts...)
+	if vars, err := prompt.EnrichVars(ctx, prompt.RegisteredEnricher(), ticket, templateVars); err != nil {
+		color.Yellow("⚠️  Warning: Failed to enrich the prompt for %s: %v", ticket.Key, err)
+	} else {
+		opts = append(opts, prompt.WithVars(vars))
+	}
+	if usePromptCache {
+		opts = append(opts, prompt.WithContextLast())
+	}
+	switch {
+	case templateFilePath == stdinTemplatePath:
+		promptText, err = renderStdinPrompt(stdinPrompt, ticket, stdinAsTemplate, opts...)
+	case dryRun:
+		promptText, unusedWarnings, err = prompt.DryRunRender(templateFilePath, ticket, opts...)
+	default:
+		promptText, err = prompt.LoadAndRenderTemplate(templateFilePath, ticket, opts...)
+	}
+	done()
 	if err != nil {
+		observer.OnError(ticket.Key, err)
 		color.Red("❌ Failed to load prompt template: %v", err)
-		os.Exit(1)
+		return nil, err
 	}
 
-	// Initialize Anthropic client
-	color.Cyan("☁️  Using Google Cloud region: %s, project: %s", region, projectID)
-	client := anthropic.NewClient(
-		vertex.WithGoogleAuth(ctx, region, projectID),
-	)
+	// Find the ticket-independent prefix by rendering the template for another ticket
+	staticPrompt := ""
+	if usePromptCache && !dryRun && templateFilePath != stdinTemplatePath {
+		if probe, err := prompt.LoadAndRenderTemplate(templateFilePath, prompt.SampleTicket(), opts...); err == nil {
+			staticPrompt = prompt.CacheablePrefix(promptText, probe)
+		}
+		if verbose {
+			color.Cyan("🗄️  Cacheable prompt prefix: ~%d tokens", prompt.EstimateTokens(staticPrompt))
+		}
+	}
 
-	// Generate implementation plan with spinner
-	s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Suffix = " 🤖 Generating implementation plan with Claude..."
-	s.Start()
-	message, err := client.Messages.New(ctx, anthropic.MessageNewParams{
-		MaxTokens: 4096,
+	estimate := prompt.EstimateTokens(promptText)
+	if verbose || dryRun {
+		color.Cyan("🔢 Estimated prompt size: ~%d tokens", estimate)
+	}
+	if err := prompt.CheckPromptSize(model, estimate, maxTokens); err != nil {
+		if !forceSize && !dryRun {
+			observer.OnError(ticket.Key, err)
+			color.Red("❌ %v; shorten it with --max-description-chars, or use --force to send it anyway", err)
+			return nil, err
+		}
+		color.Yellow("⚠️  Warning: %v", err)
+	} else if prompt.NearContextLimit(estimate) {
+		color.Yellow("⚠️  Warning: Prompt is ~%d tokens, close to the %d token context limit (consider --max-description-chars)", estimate, prompt.ContextWindow(model))
+	}
+
+	if dryRun {
+		printSeparator()
+		color.HiMagenta("📝 RENDERED PROMPT (dry run)")
+		printSeparator()
+		fmt.Println(promptText)
+		printSeparator()
+		for _, warning := range unusedWarnings {
+			color.Yellow("⚠️  Warning: %s", warning)
+		}
+		return nil, nil
+	}
+
+	params := anthropic.MessageNewParams{
+		MaxTokens: int64(maxTokens),
 		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(promptText)),
+			planPromptMessage(promptText, staticPrompt),
 		},
-		Model: DefaultModel,
-	})
-	s.Stop()
+		Model:       anthropic.Model(model),
+		Temperature: anthropic.Float(temperature),
+	}
+
+	done = timer.track(ticket.Key, "generate")
+	started := time.Now()
+	fileStream := newPlanFileStream(ticket)
+	cacheKey, err := responses.key(params)
 	if err != nil {
-		color.Red("❌ Failed to generate implementation plan: %v", err)
-		os.Exit(1)
+		color.Yellow("⚠️  Warning: Response cache disabled for this ticket: %v", err)
+	}
+	message, cached := responses.get(cacheKey)
+	interrupted := false
+	// sent is the request that produced message, which a retry replaces
+	sent := &params
+	if cached {
+		color.Green("♻️  Using cached response (--cache-responses)")
+	} else {
+		request := func(params anthropic.MessageNewParams) (*anthropic.Message, bool, error) {
+			return requestPlan(ctx, client, params, fileStream)
+		}
+		message, interrupted, err = requestNonEmptyPlan(request, &params, promptText)
+		if err == nil && !interrupted && !structuredOutput {
+			// Retry a suspiciously short plan once, keeping the retry even if it is still short
+			if reason := shortPlanReason(messageText(message), minPlanChars, minPlanLines); reason != "" {
+				color.Yellow("⚠️  Warning: Plan is suspiciously short (%s); retrying once", reason)
+				retry := shortPlanRetryParams(params, message, reason)
+				sent = &retry
+				message, interrupted, err = requestPlan(ctx, client, retry, fileStream)
+				if err == nil && !interrupted {
+					if reason := emptyPlanReason(message); reason != "" {
+						err = &emptyPlanError{Reason: reason}
+					} else if reason := shortPlanReason(messageText(message), minPlanChars, minPlanLines); reason != "" {
+						color.Yellow("⚠️  Warning: Plan is still short after retrying (%s)", reason)
+					}
+				}
+			}
+		}
+	}
+	var structured *structuredPlan
+	if err == nil && structuredOutput && !interrupted {
+		send := func(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+			return client.Messages.New(ctx, params)
+		}
+		var parsed *anthropic.Message
+		structured, parsed, err = requestStructuredPlan(ctx, send, params, message)
+		if parsed != message {
+			// A corrected plan answers the follow-up request, not sent, so it isn't cached
+			message, sent = parsed, nil
+		}
+	}
+	// Only responses that produced a plan are cached, after any structured parse
+	if err == nil && !interrupted && !cached && sent != nil {
+		if err := responses.store(*sent, message, forceCache); err != nil {
+			color.Yellow("⚠️  Warning: Failed to cache response: %v", err)
+		}
+	}
+	done()
+	if err != nil {
+		observer.OnError(ticket.Key, err)
+		color.Red("❌ Failed to generate %s: %v", strings.ToLower(generatedNoun()), err)
+		return nil, err
 	}
 
-	color.Green("\n✅ Implementation plan generated successfully!")
-	printSeparator()
-	color.HiMagenta("🚀 IMPLEMENTATION PLAN")
-	printSeparator()
+	gen := newGenerationInfo(message)
+	gen.Interrupted = interrupted
+	gen.Cached = cached
+	if !cached {
+		observer.OnPlanGenerated(ticket.Key, telemetry.Usage{
+			Model:        gen.Model,
+			InputTokens:  gen.InputTokens,
+			OutputTokens: gen.OutputTokens,
+			Duration:     time.Since(started),
+		})
+		if verbose && staticPrompt != "" {
+			color.Cyan("🗄️  Prompt cache: %d tokens read, %d tokens written", message.Usage.CacheReadInputTokens, message.Usage.CacheCreationInputTokens)
+		}
+	}
 
 	var implementationPlan strings.Builder
-	for i := range message.Content {
-		content := message.Content[i].Text
-		fmt.Printf("%+v\n", content)
-		implementationPlan.WriteString(content)
-		implementationPlan.WriteString("\n")
+	if structured != nil {
+		text, err := formatStructuredPlan(structured, ticket, gen)
+		if err != nil {
+			observer.OnError(ticket.Key, err)
+			color.Red("❌ Failed to generate implementation plan: %v", err)
+			return nil, err
+		}
+		implementationPlan.WriteString(text)
+	} else {
+		implementationPlan.WriteString(messageText(message))
+	}
+
+	if interrupted && explaining {
+		printSeparator()
+		color.Yellow("⚠️  Generation interrupted")
+		return nil, nil
+	} else if interrupted {
+		printSeparator()
+		color.Yellow("⚠️  Generation interrupted; saving the partial plan")
+	} else if streamOutput && !cached {
+		printSeparator()
+		color.Green("✅ %s generated successfully!", generatedNoun())
+	} else {
+		color.Green("\n✅ %s generated successfully!", generatedNoun())
+		printSeparator()
+		color.HiMagenta(generatedHeading())
+		printSeparator()
+		printPlan(implementationPlan.String(), renderMarkdown && structured == nil && isTerminal(os.Stdout))
+		printSeparator()
+	}
+	if explaining {
+		// An explanation is only printed, not reviewed or saved
+		return nil, nil
 	}
-	printSeparator()
 
-	// Save implementation plan to file
-	if err := saveImplementationPlan(ticketID, ticket, implementationPlan.String(), "implementation-plans"); err != nil {
+	plan := implementationPlan.String()
+	if selfReview && !interrupted {
+		plan = selfReviewPlan(ctx, &claudeReviewer{client: client, model: reviewModel}, ticket, plan, timer)
+	}
+	if addTOC && structured == nil {
+		plan = markdown.AddTableOfContents(plan)
+	}
+
+	if copyToClipboard {
+		copyPlanToClipboard(plan)
+	}
+
+	// Save implementation plan to file, or a stub if it duplicates an earlier plan
+	var filePath string
+	// Duplicates are detected on the generated plan, ignoring any review notes
+	var first *savedPlan
+	if structured == nil {
+		first = dedupe.duplicateOf(implementationPlan.String())
+	}
+	planDir := organizedPlanDir(DefaultOutputDir, organizeBy, ticket, time.Now())
+	switch {
+	case structured != nil:
+		filePath, err = saveStructuredPlan(ticket.Key, plan, planDir, outputPath)
+	case fileStream.holds(plan, messageText(message)):
+		// The plan was written to --output as it streamed in
+		filePath = outputPath
+		color.Green("\n💾 Implementation plan saved to: %s", filePath)
+	case outputPath != "":
+		filePath, err = writePlanToFile(outputPath, ticket, gen, plan, headerFields, appendPlan)
+	case first != nil:
+		color.Yellow("♻️  Plan is identical to the plan for %s; saving a reference instead", first.Ticket.Key)
+		filePath, err = saveImplementationPlan(ticket.Key, ticket, gen, duplicatePlanStub(first, planDir), planDir, headerFields)
+	default:
+		filePath, err = saveImplementationPlan(ticket.Key, ticket, gen, plan, planDir, headerFields)
+	}
+	if err != nil {
 		color.Yellow("⚠️  Warning: Failed to save implementation plan to file: %v", err)
+		return nil, nil
+	}
+	if includeThinking {
+		if thinkingPath, err := saveThinking(filePath, ticket.Key, message); err != nil {
+			color.Yellow("⚠️  Warning: Failed to save extended thinking: %v", err)
+		} else if thinkingPath != "" {
+			color.Green("🧠 Extended thinking saved to: %s", thinkingPath)
+		}
 	}
+
+	saved := &savedPlan{Ticket: ticket, FilePath: filePath, Plan: plan, Gen: gen, Generated: time.Now()}
+	if first != nil {
+		saved.DuplicateOf = first.Ticket.Key
+	} else {
+		dedupe.record(implementationPlan.String(), *saved)
+	}
+	return saved, nil
 }
 
-// saveImplementationPlan saves the implementation plan to a markdown file
-func saveImplementationPlan(ticketID string, ticket *jira.Ticket, plan string, dir string) error {
+// requestPlan sends the plan request, streaming it to the terminal with --stream or
+// showing a spinner otherwise. A stream interrupted after content arrived returns
+// the partial message with interrupted set, rather than an error. When fileStream
+// is set, the streamed plan is also written to the --output file as it arrives.
+func requestPlan(ctx context.Context, client anthropic.Client, params anthropic.MessageNewParams, fileStream *planFileStream) (message *anthropic.Message, interrupted bool, err error) {
+	if streamOutput {
+		// Stream the plan to the terminal as it is generated
+		color.Cyan("\n🤖 Streaming %s from Claude...", strings.ToLower(generatedNoun()))
+		printSeparator()
+		color.HiMagenta(generatedHeading())
+		printSeparator()
+		var sink io.Writer
+		if fileStream != nil {
+			if err := fileStream.begin(); err != nil {
+				color.Yellow("⚠️  Warning: Failed to start writing the plan to %s: %v", fileStream.path, err)
+			} else {
+				sink = fileStream
+			}
+		}
+		message, err = streamPlan(ctx, client, params, sink)
+		fmt.Println()
+		if sink != nil {
+			fileStream.end(err == nil)
+			if err != nil && ctx.Err() == nil {
+				color.Yellow("⚠️  Partial plan left in %s", fileStream.path)
+			}
+		}
+		if err != nil && ctx.Err() != nil && len(message.Content) > 0 {
+			return message, true, nil
+		}
+		return message, false, err
+	}
+
+	// Generate implementation plan with spinner
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Suffix = fmt.Sprintf(" 🤖 Generating %s with Claude...", strings.ToLower(generatedNoun()))
+	if showSpinners {
+		s.Start()
+	}
+	message, err = client.Messages.New(ctx, params)
+	s.Stop()
+	return message, false, err
+}
+
+// saveImplementationPlan saves the implementation plan to a markdown file and returns its path
+func saveImplementationPlan(ticketID string, ticket *jira.Ticket, gen *generationInfo, plan string, dir string, headerFields []string) (string, error) {
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	// Generate filename with ticket ID and timestamp
+	// Write the metadata header, any file branding and the plan to a file named
+	// with the ticket ID and timestamp
 	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("%s_%s.md", ticketID, timestamp)
-	filePath := filepath.Join(dir, filename)
-
-	// Create markdown content with metadata header
-	var content strings.Builder
-	content.WriteString(fmt.Sprintf("# Implementation Plan: %s\n\n", ticket.Summary))
-	content.WriteString(fmt.Sprintf("**Ticket ID:** %s\n", ticketID))
-	content.WriteString(fmt.Sprintf("**Generated:** %s\n", time.Now().Format("2006-01-02 15:04:05")))
-	content.WriteString(fmt.Sprintf("**Status:** %s\n", ticket.Status.Name))
-	content.WriteString(fmt.Sprintf("**Type:** %s\n", ticket.IssueType.Name))
-	content.WriteString(fmt.Sprintf("**Priority:** %s\n", ticket.Priority.Name))
-
-	if ticket.Assignee != nil {
-		content.WriteString(fmt.Sprintf("**Assignee:** %s\n", ticket.Assignee.DisplayName))
-	} else {
-		content.WriteString("**Assignee:** Unassigned\n")
+	filePath, err := writePlanFile(func() (*os.File, string, error) {
+		return createUniqueFile(dir, fmt.Sprintf("%s_%s", ticketID, timestamp), planFileExtension())
+	}, ticket, gen, plan, headerFields)
+	if err != nil {
+		return "", err
 	}
 
-	content.WriteString(fmt.Sprintf("**Reporter:** %s\n", ticket.Reporter.DisplayName))
+	color.Green("\n💾 Implementation plan saved to: %s", filePath)
+	return filePath, nil
+}
 
-	if len(ticket.Components) > 0 {
-		var compNames []string
-		for _, comp := range ticket.Components {
-			compNames = append(compNames, comp.Name)
+// writePlanToFile writes the implementation plan to a specific file. In append mode
+// an existing file keeps its content and the plan is added after a divider with
+// the generation timestamp; a missing file is created with the full header and
+// any file branding.
+func writePlanToFile(filePath string, ticket *jira.Ticket, gen *generationInfo, plan string, headerFields []string, appendMode bool) (string, error) {
+	if dir := filepath.Dir(filePath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
-		content.WriteString(fmt.Sprintf("**Components:** %s\n", strings.Join(compNames, ", ")))
 	}
 
-	if len(ticket.Labels) > 0 {
-		content.WriteString(fmt.Sprintf("**Labels:** %s\n", strings.Join(ticket.Labels, ", ")))
+	existing, err := os.Stat(filePath)
+	if !appendMode || err != nil || existing.Size() == 0 {
+		_, err := writePlanFile(func() (*os.File, string, error) {
+			file, err := os.Create(filePath)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to open file %s: %w", filePath, err)
+			}
+			return file, filePath, nil
+		}, ticket, gen, plan, headerFields)
+		if err != nil {
+			return "", err
+		}
+
+		color.Green("\n💾 Implementation plan saved to: %s", filePath)
+		return filePath, nil
 	}
 
-	content.WriteString("\n---\n\n")
+	var content strings.Builder
+	content.WriteString("\n\n---\n\n")
+	marker := ""
+	if gen != nil && gen.Interrupted {
+		marker = " [interrupted]"
+	}
+	content.WriteString(fmt.Sprintf("## Regenerated: %s%s\n\n", time.Now().Format("2006-01-02 15:04:05"), marker))
 	content.WriteString(plan)
 
-	// Write to file
-	if err := os.WriteFile(filePath, []byte(content.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(formatPlanFile(ticket, content.String())); err != nil {
+		return "", fmt.Errorf("failed to write file %s: %w", filePath, err)
 	}
 
 	color.Green("\n💾 Implementation plan saved to: %s", filePath)
-	return nil
+	return filePath, nil
+}
+
+// planFileExtension returns the file extension for saved plans in the selected --format
+func planFileExtension() string {
+	if outputFormat == FormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+// formatPlanFile converts a plan's markdown content (header included) to the selected --format
+func formatPlanFile(ticket *jira.Ticket, content string) string {
+	if outputFormat == FormatHTML {
+		return markdown.HTMLDocument(fmt.Sprintf("%s: %s", ticket.Key, ticket.Summary), markdown.RenderHTML(content))
+	}
+	return content
+}
+
+// createUniqueFile creates a new file named base+ext in dir without overwriting an
+// existing file, appending -2, -3, etc. to the base name until the name is free
+func createUniqueFile(dir, base, ext string) (*os.File, string, error) {
+	for n := 1; ; n++ {
+		name := base + ext
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+
+		filePath := filepath.Join(dir, name)
+		file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			return file, filePath, nil
+		}
+		if !os.IsExist(err) {
+			return nil, "", fmt.Errorf("failed to create file %s: %w", filePath, err)
+		}
+	}
+}
+
+// printPlan prints the implementation plan, styling the markdown for the terminal when render is set
+func printPlan(plan string, render bool) {
+	if render {
+		fmt.Print(markdown.RenderTerminal(plan))
+		return
+	}
+	fmt.Print(plan)
+}
+
+// isTerminal reports whether the file is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
 
 // printSeparator prints a decorative separator
@@ -235,6 +1546,11 @@ func printTicketInfo(ticket *jira.Ticket) {
 	color.HiWhite("🎫 Ticket: ")
 	color.Green("%s - %s", ticket.Key, ticket.Summary)
 
+	if ticket.URL != "" {
+		color.HiWhite("🔗 URL: ")
+		color.Cyan("%s", ticket.URL)
+	}
+
 	color.HiWhite("📊 Status: ")
 	color.Cyan("%s", ticket.Status.Name)
 
@@ -244,29 +1560,19 @@ func printTicketInfo(ticket *jira.Ticket) {
 	color.HiWhite("⚡ Priority: ")
 	color.Cyan("%s", ticket.Priority.Name)
 
+	color.HiWhite("👤 Assignee: ")
 	if ticket.Assignee != nil {
-		color.HiWhite("👤 Assignee: ")
-		color.Cyan("%s", ticket.Assignee.DisplayName)
+		color.Cyan("%s", ticket.AssigneeName())
 	} else {
-		color.HiWhite("👤 Assignee: ")
-		color.Yellow("Unassigned")
+		color.Yellow("%s", ticket.AssigneeName())
 	}
 
 	color.HiWhite("📝 Reporter: ")
-	color.Cyan("%s", ticket.Reporter.DisplayName)
+	color.Cyan("%s", ticket.ReporterName())
 
 	if len(ticket.Components) > 0 {
 		color.HiWhite("🔧 Components: ")
-		for i, comp := range ticket.Components {
-			if i > 0 {
-				fmt.Print(", ")
-			}
-			color.Cyan("%s", comp.Name)
-			if comp.Lead != nil {
-				color.White(" (Lead: %s)", comp.Lead.DisplayName)
-			}
-		}
-		fmt.Println()
+		color.Cyan("%s", ticket.ComponentNamesLimit(maxDisplayComponents))
 	} else {
 		color.HiWhite("🔧 Components: ")
 		color.Yellow("None")
@@ -277,7 +1583,52 @@ func printTicketInfo(ticket *jira.Ticket) {
 		color.Cyan("%s", strings.Join(ticket.Labels, ", "))
 	}
 
+	if sprint := ticket.ActiveSprint(); sprint != nil {
+		color.HiWhite("🏃 Sprint: ")
+		color.Cyan("%s", sprint.Describe())
+	}
+
+	if ticket.SecurityLevel != nil {
+		color.HiWhite("🔒 Security Level: ")
+		color.Yellow("%s (visibility restricted)", ticket.SecurityLevel.Name)
+	}
+
+	if ticket.Votes > 0 {
+		color.HiWhite("👍 Votes: ")
+		if ticket.HasVoted {
+			color.Cyan("%d (including yours)", ticket.Votes)
+		} else {
+			color.Cyan("%d", ticket.Votes)
+		}
+	}
+
 	color.HiWhite("📄 Description: ")
-	color.White("%.200s...", ticket.Description)
+	color.White("%s", truncateText(ticket.Description, 200))
 	printSeparator()
 }
+
+// truncateText shortens text to at most maxRunes runes, cutting at the last word
+// boundary and appending "..." only when the text was actually truncated
+func truncateText(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	truncated := runes[:maxRunes]
+	if i := lastSpaceIndex(truncated); i > 0 {
+		truncated = truncated[:i]
+	}
+
+	return strings.TrimRightFunc(string(truncated), unicode.IsSpace) + "..."
+}
+
+// lastSpaceIndex returns the index of the last whitespace rune, or -1 if there is none
+func lastSpaceIndex(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
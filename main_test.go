@@ -0,0 +1,319 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{name: "shorter than the limit", text: "Short description", maxRunes: 200, want: "Short description"},
+		{name: "exactly the limit", text: strings.Repeat("é", 200), maxRunes: 200, want: strings.Repeat("é", 200)},
+		{
+			// 199 ASCII bytes then a 2-byte rune straddling byte 200
+			name:     "accented rune straddling 200 bytes",
+			text:     strings.Repeat("a", 199) + "é" + " more",
+			maxRunes: 200,
+			want:     strings.Repeat("a", 199) + "é...",
+		},
+		{
+			name:     "emoji at the limit",
+			text:     strings.Repeat("🚀", 150) + " launch",
+			maxRunes: 120,
+			want:     strings.Repeat("🚀", 120) + "...",
+		},
+		{name: "cut at the last word boundary", text: "Café crème brûlée for everyone", maxRunes: 16, want: "Café crème..."},
+		{name: "no spaces", text: strings.Repeat("ü", 250), maxRunes: 200, want: strings.Repeat("ü", 200) + "..."},
+		{name: "trailing space trimmed", text: "naïve    approach", maxRunes: 8, want: "naïve..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateText(tt.text, tt.maxRunes)
+			if got != tt.want {
+				t.Errorf("truncateText() = %q, want %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateText() = %q, which is not valid UTF-8", got)
+			}
+		})
+	}
+}
+
+func TestCreateUniqueFile(t *testing.T) {
+	dir := t.TempDir()
+	base := "RHEL-1_20250101_120000"
+
+	var paths []string
+	for range 3 {
+		file, path, err := createUniqueFile(dir, base, ".md")
+		if err != nil {
+			t.Fatalf("createUniqueFile() error = %v", err)
+		}
+		file.WriteString(path)
+		file.Close()
+		paths = append(paths, path)
+	}
+
+	// Saves within the same second get -2, -3, ... rather than overwriting
+	want := []string{base + ".md", base + "-2.md", base + "-3.md"}
+	for i, path := range paths {
+		if path != filepath.Join(dir, want[i]) {
+			t.Errorf("save %d path = %s, want %s", i+1, path, want[i])
+		}
+		if content, err := os.ReadFile(path); err != nil || string(content) != path {
+			t.Errorf("save %d content = %q, %v; want its own path", i+1, content, err)
+		}
+	}
+}
+
+func TestCheckAuthentication(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        string
+		skipAuthTest bool
+		status       int
+		wantErr      bool
+		wantCalled   bool
+	}{
+		{name: "token is checked", token: "pat", status: http.StatusOK, wantCalled: true},
+		{name: "invalid token fails", token: "expired", status: http.StatusUnauthorized, wantErr: true, wantCalled: true},
+		{name: "skip auth test", token: "expired", skipAuthTest: true, status: http.StatusUnauthorized},
+		{name: "anonymous", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/rest/api/2/myself" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				called = true
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			oldToken, oldSkip := token, skipAuthTest
+			t.Cleanup(func() { token, skipAuthTest = oldToken, oldSkip })
+			token, skipAuthTest = tt.token, tt.skipAuthTest
+
+			client := jira.NewClient(jira.WithBaseURL(server.URL), jira.WithToken(tt.token), jira.WithMaxAttempts(1))
+			err := checkAuthentication(client, &retrySpinner{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAuthentication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("/rest/api/2/myself called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestPrintPlan(t *testing.T) {
+	const plan = "# Plan\n\n**Bold** step\n"
+	tests := []struct {
+		name     string
+		render   bool
+		wantANSI bool
+	}{
+		{name: "raw", render: false},
+		{name: "rendered", render: true, wantANSI: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := captureStdout(t, func() { printPlan(plan, tt.render) })
+			if got := strings.Contains(out, "\x1b["); got != tt.wantANSI {
+				t.Errorf("printPlan() output has ANSI styling = %v, want %v: %q", got, tt.wantANSI, out)
+			}
+			if !tt.render && out != plan {
+				t.Errorf("printPlan() = %q, want the raw plan %q", out, plan)
+			}
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	// Piped or redirected output is never rendered
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if isTerminal(file) {
+		t.Error("isTerminal() = true for a regular file")
+	}
+}
+
+func TestWritePlanToFileAppend(t *testing.T) {
+	oldNoHeader, oldFormat := noHeader, outputFormat
+	t.Cleanup(func() { noHeader, outputFormat = oldNoHeader, oldFormat })
+	noHeader, outputFormat = false, FormatMarkdown
+
+	ticket := &jira.Ticket{Key: "RHEL-9", Summary: "Retry uploads"}
+	path := filepath.Join(t.TempDir(), "plans", "RHEL-9.md")
+	if _, err := writePlanToFile(path, ticket, nil, "First plan\n", defaultHeaderFields, true); err != nil {
+		t.Fatalf("first writePlanToFile() error = %v", err)
+	}
+	first := fileContent(t, path)
+	if !strings.Contains(first, "Ticket ID:") || strings.Contains(first, "Regenerated:") {
+		t.Errorf("first plan = %q, want a header and no regenerated section", first)
+	}
+
+	if _, err := writePlanToFile(path, ticket, &generationInfo{Interrupted: true}, "Second plan\n", defaultHeaderFields, true); err != nil {
+		t.Fatalf("second writePlanToFile() error = %v", err)
+	}
+	content := fileContent(t, path)
+	if !strings.HasPrefix(content, first) {
+		t.Errorf("appended file = %q, want it to start with the prior content %q", content, first)
+	}
+	appended := strings.TrimPrefix(content, first)
+	if !strings.Contains(appended, "## Regenerated: ") || !strings.Contains(appended, "[interrupted]") || !strings.HasSuffix(appended, "Second plan\n") {
+		t.Errorf("appended section = %q, want a regenerated heading and the new plan", appended)
+	}
+	if strings.Count(content, "Ticket ID:") != 1 {
+		t.Errorf("appended file = %q, want the header only once", content)
+	}
+
+	// Without --append the file is replaced
+	if _, err := writePlanToFile(path, ticket, nil, "Third plan\n", defaultHeaderFields, false); err != nil {
+		t.Fatalf("third writePlanToFile() error = %v", err)
+	}
+	if content := fileContent(t, path); strings.Contains(content, "First plan") || !strings.Contains(content, "Third plan") {
+		t.Errorf("overwritten file = %q, want only the third plan", content)
+	}
+}
+
+func TestParseTemplateVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "none", want: map[string]string{}},
+		{name: "vars", flags: []string{"team=storage", "quarter=Q3"}, want: map[string]string{"team": "storage", "quarter": "Q3"}},
+		{name: "value with equals and spaces", flags: []string{" note =a=b c"}, want: map[string]string{"note": "a=b c"}},
+		{name: "empty value", flags: []string{"compliance="}, want: map[string]string{"compliance": ""}},
+		{name: "missing equals", flags: []string{"team"}, wantErr: true},
+		{name: "empty key", flags: []string{"=storage"}, wantErr: true},
+		{name: "key with a space", flags: []string{"team name=storage"}, wantErr: true},
+		{name: "duplicate key", flags: []string{"team=storage", "team=network"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTemplateVars(tt.flags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTemplateVars(%q) error = %v, wantErr %v", tt.flags, err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseTemplateVars(%q) = %v, want %v", tt.flags, got, tt.want)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("vars[%q] = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateVarsRender(t *testing.T) {
+	oldVars := templateVars
+	t.Cleanup(func() { templateVars = oldVars })
+
+	vars, err := parseTemplateVars([]string{"team=storage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	templateVars = vars
+
+	path := filepath.Join(t.TempDir(), "plan.md")
+	if err := os.WriteFile(path, []byte(`Team: {{.Vars.team}}, quarter: {{or .Vars.quarter "unset"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := prompt.LoadAndRenderTemplate(path, prompt.SampleTicket(), renderOptions()...)
+	if err != nil {
+		t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+	}
+	if want := "Team: storage, quarter: unset"; rendered != want {
+		t.Errorf("rendered = %q, want %q", rendered, want)
+	}
+}
+
+func TestPostPlanCommentSecurityLevel(t *testing.T) {
+	tests := []struct {
+		name            string
+		security        *jira.SecurityLevel
+		allowRestricted bool
+		wantPosted      bool
+	}{
+		{name: "unrestricted", wantPosted: true},
+		{name: "restricted", security: &jira.SecurityLevel{ID: "10100", Name: "Red Hat Employee"}},
+		{name: "restricted with --allow-restricted", security: &jira.SecurityLevel{ID: "10100", Name: "Red Hat Employee"}, allowRestricted: true, wantPosted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue/RHEL-1/comment" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				posted = true
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"1"}`))
+			}))
+			defer server.Close()
+
+			oldAllow, oldFormat := allowRestricted, commentFormat
+			t.Cleanup(func() { allowRestricted, commentFormat = oldAllow, oldFormat })
+			allowRestricted, commentFormat = tt.allowRestricted, FormatMarkdown
+
+			plan := &savedPlan{Ticket: &jira.Ticket{Key: "RHEL-1", SecurityLevel: tt.security}, Plan: "# Plan"}
+			client := jira.NewClient(jira.WithBaseURL(server.URL), jira.WithToken("pat"), jira.WithMaxAttempts(1))
+			postPlanComment(client, &retrySpinner{}, plan, nil)
+
+			if posted != tt.wantPosted {
+				t.Errorf("posted = %v, want %v", posted, tt.wantPosted)
+			}
+		})
+	}
+}
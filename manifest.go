@@ -0,0 +1,137 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// manifestFileName is the name of the machine-readable list of generated plans
+// written next to the plans after each run
+const manifestFileName = "manifest.json"
+
+// planManifest lists the plans generated into a directory, for CI pipelines
+// that need to detect changed plans or attach them as artifacts
+type planManifest struct {
+	Updated   time.Time       `json:"updated"`
+	Artifacts []manifestEntry `json:"artifacts"`
+}
+
+// manifestEntry describes one generated plan file
+type manifestEntry struct {
+	File      string    `json:"file"`
+	Ticket    string    `json:"ticket"`
+	Model     string    `json:"model,omitempty"`
+	Generated time.Time `json:"generated"`
+	SHA256    string    `json:"sha256"`
+}
+
+// writeRunManifest updates the manifest in each directory plans were saved to,
+// warning rather than failing the run if a manifest cannot be written
+func writeRunManifest(plans []savedPlan) {
+	byDir := make(map[string][]savedPlan)
+	var dirs []string
+	for _, plan := range plans {
+		dir := filepath.Dir(plan.FilePath)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], plan)
+	}
+
+	for _, dir := range dirs {
+		if _, err := writeManifest(dir, byDir[dir]); err != nil {
+			color.Yellow("⚠️  Warning: Failed to write manifest: %v", err)
+		}
+	}
+}
+
+// writeManifest creates or updates manifest.json in dir with an entry for each
+// saved plan. Entries from an existing manifest are preserved, and entries for
+// the same file are replaced. It returns the path of the manifest file.
+func writeManifest(dir string, plans []savedPlan) (string, error) {
+	manifestPath := filepath.Join(dir, manifestFileName)
+
+	manifest, err := readManifest(manifestPath)
+	if err != nil {
+		return "", err
+	}
+
+	byFile := make(map[string]int)
+	for i, entry := range manifest.Artifacts {
+		byFile[entry.File] = i
+	}
+
+	for _, plan := range plans {
+		file, err := filepath.Rel(dir, plan.FilePath)
+		if err != nil {
+			file = filepath.Base(plan.FilePath)
+		}
+
+		entry := manifestEntry{
+			File:      filepath.ToSlash(file),
+			Ticket:    plan.Ticket.Key,
+			Generated: plan.Generated.UTC(),
+			SHA256:    hashPlan(plan.Plan),
+		}
+		if plan.Gen != nil {
+			entry.Model = plan.Gen.Model
+		}
+
+		if i, ok := byFile[entry.File]; ok {
+			manifest.Artifacts[i] = entry
+		} else {
+			byFile[entry.File] = len(manifest.Artifacts)
+			manifest.Artifacts = append(manifest.Artifacts, entry)
+		}
+	}
+
+	sort.SliceStable(manifest.Artifacts, func(i, j int) bool {
+		return manifest.Artifacts[i].File < manifest.Artifacts[j].File
+	})
+	manifest.Updated = time.Now().UTC()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file %s: %w", manifestPath, err)
+	}
+
+	return manifestPath, nil
+}
+
+// readManifest reads an existing manifest file; a missing file is an empty manifest
+func readManifest(manifestPath string) (*planManifest, error) {
+	manifest := &planManifest{}
+
+	data, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", manifestPath, err)
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", manifestPath, err)
+	}
+
+	return manifest, nil
+}
+
+// hashPlan returns the hex-encoded SHA-256 of a plan's body. The file's metadata
+// header and branding are left out, since the header's generation timestamp would
+// give identical plans different hashes.
+func hashPlan(plan string) string {
+	sum := sha256.Sum256([]byte(plan))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestWriteManifestHashesPlanBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    string
+		second   string
+		wantSame bool
+	}{
+		{name: "unchanged plan", first: "## Plan\n\n- Step one\n", second: "## Plan\n\n- Step one\n", wantSame: true},
+		{name: "changed plan", first: "## Plan\n\n- Step one\n", second: "## Plan\n\n- Step two\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			ticket := &jira.Ticket{Key: "DEMO-1"}
+
+			var hashes []string
+			for i, plan := range []string{tt.first, tt.second} {
+				// Each run writes the plan under a header with its own timestamp
+				generated := time.Date(2025, time.January, 6, 9, i, 0, 0, time.UTC)
+				path := filepath.Join(dir, "DEMO-1.md")
+				header := "**Generated:** " + generated.Format(time.RFC3339) + "\n\n"
+				if err := os.WriteFile(path, []byte(header+plan), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err := writeManifest(dir, []savedPlan{{Ticket: ticket, FilePath: path, Plan: plan, Generated: generated}}); err != nil {
+					t.Fatalf("writeManifest() error = %v", err)
+				}
+				manifest, err := readManifest(filepath.Join(dir, manifestFileName))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(manifest.Artifacts) != 1 {
+					t.Fatalf("manifest has %d entries, want 1", len(manifest.Artifacts))
+				}
+				hashes = append(hashes, manifest.Artifacts[0].SHA256)
+			}
+
+			if same := hashes[0] == hashes[1]; same != tt.wantSame {
+				t.Errorf("hashes %s and %s: same = %v, want %v", hashes[0], hashes[1], same, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/telemetry"
+)
+
+// recordingObserver records telemetry events in the order they are received
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnTicketFetched(ticket *jira.Ticket) {
+	o.events = append(o.events, "fetched "+ticket.Key)
+}
+
+func (o *recordingObserver) OnPlanGenerated(ticketKey string, usage telemetry.Usage) {
+	o.events = append(o.events, "generated "+ticketKey)
+}
+
+func (o *recordingObserver) OnError(ticketKey string, err error) {
+	o.events = append(o.events, "error "+ticketKey)
+}
+
+// useObserver replaces the telemetry observer for the duration of a test
+func useObserver(t *testing.T, o telemetry.Observer) {
+	old := observer
+	t.Cleanup(func() { observer = old })
+	observer = o
+}
+
+func TestObserverEventOrder(t *testing.T) {
+	// RHEL-1 has a valid and an invalid child; fetching the children of RHEL-9 fails
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("jql"), `"RHEL-1"`) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		issues := []map[string]any{
+			{"key": "RHEL-2", "fields": map[string]any{"summary": "Valid child"}},
+			{"key": "RHEL-3", "fields": map[string]any{"summary": ""}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"startAt": 0, "total": len(issues), "issues": issues})
+	}))
+	defer server.Close()
+
+	recorder := &recordingObserver{}
+	useObserver(t, recorder)
+
+	client := jira.NewClient(jira.WithBaseURL(server.URL), jira.WithEpicLinkField(""), jira.WithMaxAttempts(1))
+	parents := []*jira.Ticket{{Key: "RHEL-1", Summary: "Parent"}, {Key: "RHEL-9", Summary: "Broken parent"}}
+	tickets, failures := expandChildTickets(client, &retrySpinner{}, newPhaseTimer(time.Now), parents)
+
+	want := []string{"error RHEL-3", "fetched RHEL-2", "error RHEL-9"}
+	if !slices.Equal(recorder.events, want) {
+		t.Errorf("events = %q, want %q", recorder.events, want)
+	}
+	if len(tickets) != 1 || tickets[0].Key != "RHEL-2" || failures != 2 {
+		t.Errorf("expandChildTickets() = %d tickets, %d failures, want RHEL-2 and 2 failures", len(tickets), failures)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// Supported --organize-by modes for nesting saved plans in the output directory
+const (
+	OrganizeFlat        = "flat"
+	OrganizeProject     = "project"
+	OrganizeDate        = "date"
+	OrganizeProjectDate = "project-date"
+)
+
+// organizeDateLayout names the monthly subdirectories, e.g. "2025-06"
+const organizeDateLayout = "2006-01"
+
+// unknownProjectDir holds plans for tickets without a project key
+const unknownProjectDir = "unknown"
+
+// validateOrganizeBy checks an --organize-by mode, treating an empty mode as flat
+func validateOrganizeBy(mode string) (string, error) {
+	switch mode {
+	case "", OrganizeFlat:
+		return OrganizeFlat, nil
+	case OrganizeProject, OrganizeDate, OrganizeProjectDate:
+		return mode, nil
+	}
+	return "", fmt.Errorf("%q must be one of %s, %s, %s or %s", mode, OrganizeFlat, OrganizeProject, OrganizeDate, OrganizeProjectDate)
+}
+
+// organizedPlanDir returns the directory under base that a ticket's plan is saved
+// to, e.g. base/RHEL/2025-06 for project-date
+func organizedPlanDir(base, mode string, ticket *jira.Ticket, now time.Time) string {
+	project := unknownProjectDir
+	if ticket != nil {
+		if key := strings.TrimSpace(ticket.Project.Key); key != "" {
+			// Project keys are plain identifiers, but don't let one escape the output directory
+			project = filepath.Base(filepath.Clean("/" + key))
+		}
+	}
+	month := now.Format(organizeDateLayout)
+
+	switch mode {
+	case OrganizeProject:
+		return filepath.Join(base, project)
+	case OrganizeDate:
+		return filepath.Join(base, month)
+	case OrganizeProjectDate:
+		return filepath.Join(base, project, month)
+	}
+	return base
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestValidateOrganizeBy(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{mode: "", want: OrganizeFlat},
+		{mode: OrganizeFlat, want: OrganizeFlat},
+		{mode: OrganizeProject, want: OrganizeProject},
+		{mode: OrganizeDate, want: OrganizeDate},
+		{mode: OrganizeProjectDate, want: OrganizeProjectDate},
+		{mode: "month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := validateOrganizeBy(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateOrganizeBy(%q) error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("validateOrganizeBy(%q) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrganizedPlanDir(t *testing.T) {
+	now := time.Date(2025, 6, 30, 23, 0, 0, 0, time.UTC)
+	ticket := &jira.Ticket{Key: "RHEL-1", Project: jira.Project{Key: "RHEL"}}
+
+	tests := []struct {
+		name   string
+		mode   string
+		ticket *jira.Ticket
+		want   string
+	}{
+		{name: "flat", mode: OrganizeFlat, ticket: ticket, want: "implementation-plans"},
+		{name: "project", mode: OrganizeProject, ticket: ticket, want: filepath.Join("implementation-plans", "RHEL")},
+		{name: "date", mode: OrganizeDate, ticket: ticket, want: filepath.Join("implementation-plans", "2025-06")},
+		{name: "project-date", mode: OrganizeProjectDate, ticket: ticket, want: filepath.Join("implementation-plans", "RHEL", "2025-06")},
+		{name: "no project key", mode: OrganizeProject, ticket: &jira.Ticket{Key: "RHEL-1"}, want: filepath.Join("implementation-plans", unknownProjectDir)},
+		{name: "no ticket", mode: OrganizeProjectDate, want: filepath.Join("implementation-plans", unknownProjectDir, "2025-06")},
+		{name: "path in project key", mode: OrganizeProject, ticket: &jira.Ticket{Project: jira.Project{Key: "../../etc"}}, want: filepath.Join("implementation-plans", "etc")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := organizedPlanDir("implementation-plans", tt.mode, tt.ticket, now); got != tt.want {
+				t.Errorf("organizedPlanDir(%q) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveOrganizedPlan(t *testing.T) {
+	ticket := &jira.Ticket{Key: "RHEL-1", Summary: "Retry uploads", Project: jira.Project{Key: "RHEL"}}
+	base := t.TempDir()
+	dir := organizedPlanDir(base, OrganizeProjectDate, ticket, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	path, err := saveImplementationPlan(ticket.Key, ticket, nil, "## Steps\n", dir, defaultHeaderFields)
+	if err != nil {
+		t.Fatalf("saveImplementationPlan() error = %v", err)
+	}
+	if got, want := filepath.Dir(path), filepath.Join(base, "RHEL", "2025-06"); got != want {
+		t.Errorf("plan saved in %s, want %s", got, want)
+	}
+}
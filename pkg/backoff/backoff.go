@@ -0,0 +1,230 @@
+// Package backoff implements the retry policy shared by the Jira client and the
+// Vertex AI client, so both retry the same transient failures with the same
+// exponential backoff and honor Retry-After the same way
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of attempts made for each request
+	DefaultMaxAttempts = 3
+
+	// DefaultBaseDelay is the delay before the first retry, doubled on each subsequent retry
+	DefaultBaseDelay = 500 * time.Millisecond
+)
+
+// Policy decides whether and when a failed attempt is retried
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first; values
+	// below 1 allow a single attempt
+	MaxAttempts int
+
+	// MaxElapsed caps the time from the first attempt until a retry would start;
+	// zero means no limit
+	MaxElapsed time.Duration
+
+	// BaseDelay is the delay before the first retry, doubled on each subsequent
+	// retry; zero uses DefaultBaseDelay
+	BaseDelay time.Duration
+
+	// Allow, when set, is asked before each retry the policy would make; returning
+	// false gives up, e.g. once a retry budget shared between requests is spent
+	Allow func() bool
+}
+
+// DefaultPolicy returns the policy used when none is configured
+func DefaultPolicy() Policy {
+	return Policy{MaxAttempts: DefaultMaxAttempts, BaseDelay: DefaultBaseDelay}
+}
+
+// Delay returns how long to wait after the given failed attempt (starting at 1),
+// honoring a server-requested retryAfter when it is positive
+func (p Policy) Delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	return base << (attempt - 1)
+}
+
+// Next reports the delay before retrying after the given failed attempt, and
+// false when the policy gives up: the attempts are used up, or the retry would
+// start after MaxElapsed has passed since the first attempt
+func (p Policy) Next(attempt int, elapsed, retryAfter time.Duration) (time.Duration, bool) {
+	if attempt >= max(p.MaxAttempts, 1) {
+		return 0, false
+	}
+	delay := p.Delay(attempt, retryAfter)
+	if p.MaxElapsed > 0 && elapsed+delay > p.MaxElapsed {
+		return 0, false
+	}
+	return delay, true
+}
+
+// RetryableStatus reports whether a response status indicates a transient
+// failure worth retrying, including rate limiting and Vertex AI's 529 overloaded
+func RetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+		529:
+		return true
+	}
+	return false
+}
+
+// RetryAfter parses a Retry-After header given in seconds or as an HTTP date,
+// returning zero when it is missing, invalid or already past
+func RetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// NotifyFunc is called before each retry with the upcoming attempt number
+// (starting at 2), the error that caused the previous attempt to fail and the
+// delay before the retry
+type NotifyFunc func(attempt int, err error, delay time.Duration)
+
+// RetryableError marks an error returned by a Retry operation as transient,
+// optionally with a server-requested delay before the next attempt
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable marks err as transient for Retry, waiting at least retryAfter when positive
+func Retryable(err error, retryAfter time.Duration) error {
+	return &RetryableError{Err: err, RetryAfter: retryAfter}
+}
+
+// Retry calls op until it succeeds, returns an error not marked with Retryable,
+// the policy gives up or ctx is done. It returns the last error from op, without
+// the Retryable wrapper.
+func Retry(ctx context.Context, p Policy, op func(attempt int) error, notify NotifyFunc) error {
+	started := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := op(attempt)
+		var retryable *RetryableError
+		if err == nil || !errors.As(err, &retryable) {
+			return err
+		}
+
+		delay, ok := p.Next(attempt, time.Since(started), retryable.RetryAfter)
+		if !ok || ctx.Err() != nil || (p.Allow != nil && !p.Allow()) {
+			return retryable.Err
+		}
+		if notify != nil {
+			notify(attempt+1, retryable.Err, delay)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return retryable.Err
+		}
+	}
+}
+
+// Do sends an HTTP request with send, retrying network errors and retryable
+// statuses under the policy. Each retry gets a fresh copy of the request body. The
+// final response is returned as-is for the caller to handle once the policy gives up.
+func Do(req *http.Request, p Policy, send func(*http.Request) (*http.Response, error), notify NotifyFunc) (*http.Response, error) {
+	var resp *http.Response
+	err := Retry(req.Context(), p, func(attempt int) error {
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return err
+		}
+
+		var sendErr error
+		resp, sendErr = send(attemptReq)
+		if sendErr != nil {
+			return Retryable(sendErr, 0)
+		}
+		if !RetryableStatus(resp.StatusCode) {
+			return nil
+		}
+		return Retryable(&statusError{resp: resp}, RetryAfter(resp.Header, time.Now()))
+	}, func(attempt int, err error, delay time.Duration) {
+		// Discard the failed response before retrying; the last one is returned open
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			resp = nil
+		}
+		if notify != nil {
+			notify(attempt, err, delay)
+		}
+	})
+
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.resp, nil
+	}
+	return resp, err
+}
+
+// statusError reports a retryable response status from Do
+type statusError struct {
+	resp *http.Response
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("server returned %s", e.resp.Status)
+}
+
+// rewind returns the request for an attempt, with a fresh body from GetBody for retries
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+	return attemptReq, nil
+}
+
+// sleep waits for d or until ctx is done, returning the context's error in that case
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
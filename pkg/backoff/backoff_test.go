@@ -0,0 +1,220 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noSleep replaces sleep for the duration of a test, recording the requested delays
+func noSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	original := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = original })
+	return &delays
+}
+
+func TestRetry(t *testing.T) {
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+
+	tests := []struct {
+		name         string
+		policy       Policy
+		failures     int
+		permanent    bool
+		wantErr      error
+		wantAttempts int
+		wantDelays   []time.Duration
+	}{
+		{
+			name:         "succeeds first time",
+			policy:       DefaultPolicy(),
+			wantAttempts: 1,
+		},
+		{
+			name:         "succeeds after retry",
+			policy:       DefaultPolicy(),
+			failures:     2,
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{DefaultBaseDelay, 2 * DefaultBaseDelay},
+		},
+		{
+			name:         "gives up after max attempts",
+			policy:       DefaultPolicy(),
+			failures:     5,
+			wantErr:      errTransient,
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{DefaultBaseDelay, 2 * DefaultBaseDelay},
+		},
+		{
+			name:         "gives up when elapsed limit would be passed",
+			policy:       Policy{MaxAttempts: 5, MaxElapsed: time.Millisecond, BaseDelay: time.Second},
+			failures:     5,
+			wantErr:      errTransient,
+			wantAttempts: 1,
+		},
+		{
+			name:         "gives up when not allowed",
+			policy:       Policy{MaxAttempts: 5, Allow: func() bool { return false }},
+			failures:     5,
+			wantErr:      errTransient,
+			wantAttempts: 1,
+		},
+		{
+			name:         "does not retry permanent errors",
+			policy:       DefaultPolicy(),
+			failures:     5,
+			permanent:    true,
+			wantErr:      errPermanent,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays := noSleep(t)
+			attempts := 0
+			var notified []int
+			err := Retry(context.Background(), tt.policy, func(attempt int) error {
+				attempts++
+				if attempt > tt.failures {
+					return nil
+				}
+				if tt.permanent {
+					return errPermanent
+				}
+				return Retryable(errTransient, 0)
+			}, func(attempt int, err error, delay time.Duration) {
+				notified = append(notified, attempt)
+			})
+
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+			var retryable *RetryableError
+			if errors.As(err, &retryable) {
+				t.Errorf("Retry() returned the Retryable wrapper: %v", err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if len(notified) != attempts-1 {
+				t.Errorf("notified %d times, want %d", len(notified), attempts-1)
+			}
+			if len(*delays) != len(tt.wantDelays) {
+				t.Fatalf("delays = %v, want %v", *delays, tt.wantDelays)
+			}
+			for i, want := range tt.wantDelays {
+				if (*delays)[i] != want {
+					t.Errorf("delay %d = %v, want %v", i, (*delays)[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	delays := noSleep(t)
+	calls := 0
+	err := Retry(context.Background(), DefaultPolicy(), func(attempt int) error {
+		calls++
+		if calls == 1 {
+			return Retryable(errors.New("rate limited"), 7*time.Second)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+		t.Errorf("delays = %v, want [7s]", *delays)
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err := Retry(ctx, DefaultPolicy(), func(attempt int) error {
+		attempts++
+		return Retryable(errors.New("transient"), 0)
+	}, nil)
+	if err == nil || attempts != 1 {
+		t.Errorf("Retry() = %v after %d attempts, want an error after 1", err, attempts)
+	}
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{"succeeds after retry", []int{http.StatusServiceUnavailable, http.StatusOK}, http.StatusOK, 2},
+		{"gives up with the last response", []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, http.StatusBadGateway, 3},
+		{"does not retry client errors", []int{http.StatusNotFound}, http.StatusNotFound, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noSleep(t)
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := requests.Add(1)
+				w.WriteHeader(tt.statuses[min(int(n), len(tt.statuses))-1])
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest("GET", server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := Do(req, DefaultPolicy(), http.DefaultClient.Do, nil)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"soon", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		if got := RetryAfter(header, now); got != tt.want {
+			t.Errorf("RetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
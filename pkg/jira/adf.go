@@ -0,0 +1,51 @@
+package jira
+
+import "strings"
+
+// parseRichText extracts plain text from a field that is either a plain string
+// (Jira Server, REST API v2) or an Atlassian Document Format document (Jira Cloud)
+func parseRichText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		var text strings.Builder
+		writeADFNode(&text, v)
+		return strings.TrimSpace(text.String())
+	}
+	return ""
+}
+
+// writeADFNode appends the plain text of an ADF node and its children
+func writeADFNode(text *strings.Builder, node map[string]interface{}) {
+	nodeType := getStringFromMap(node, "type")
+
+	switch nodeType {
+	case "text":
+		text.WriteString(getStringFromMap(node, "text"))
+		return
+	case "hardBreak":
+		text.WriteString("\n")
+		return
+	case "mention", "emoji":
+		if attrs, ok := node["attrs"].(map[string]interface{}); ok {
+			text.WriteString(getStringFromMap(attrs, "text"))
+		}
+		return
+	case "listItem":
+		text.WriteString("- ")
+	}
+
+	if children, ok := node["content"].([]interface{}); ok {
+		for _, child := range children {
+			if childNode, ok := child.(map[string]interface{}); ok {
+				writeADFNode(text, childNode)
+			}
+		}
+	}
+
+	switch nodeType {
+	case "paragraph", "heading", "codeBlock", "blockquote", "rule":
+		text.WriteString("\n\n")
+	}
+}
@@ -0,0 +1,58 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment any
+		want        string
+	}{
+		{name: "server string", environment: "RHEL 9.4\nkernel 5.14", want: "RHEL 9.4\nkernel 5.14"},
+		{
+			name: "cloud ADF",
+			environment: map[string]any{
+				"type": "doc",
+				"content": []any{
+					map[string]any{"type": "paragraph", "content": []any{
+						map[string]any{"type": "text", "text": "RHEL 9.4"},
+						map[string]any{"type": "hardBreak"},
+						map[string]any{"type": "text", "text": "kernel 5.14"},
+					}},
+					map[string]any{"type": "bulletList", "content": []any{
+						map[string]any{"type": "listItem", "content": []any{
+							map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": "x86_64"}}},
+						}},
+					}},
+				},
+			},
+			want: "RHEL 9.4\nkernel 5.14\n\n- x86_64",
+		},
+		{name: "null", environment: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				issue["fields"].(map[string]any)["environment"] = tt.environment
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if ticket.Environment != tt.want {
+				t.Errorf("Environment = %q, want %q", ticket.Environment, tt.want)
+			}
+		})
+	}
+}
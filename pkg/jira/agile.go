@@ -0,0 +1,57 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+)
+
+// sprintListResponse wraps the Agile API response listing a board's sprints
+type sprintListResponse struct {
+	Values []map[string]interface{} `json:"values"`
+}
+
+// GetActiveSprints returns the active sprints of an Agile board
+func (c *Client) GetActiveSprints(boardID int) ([]Sprint, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?state=active", c.BaseURL, boardID)
+
+	var sprintList sprintListResponse
+	if err := c.getJSON(url, &sprintList); err != nil {
+		return nil, fmt.Errorf("failed to list sprints for board %d: %w", boardID, err)
+	}
+
+	var sprints []Sprint
+	for _, value := range sprintList.Values {
+		sprints = append(sprints, parseCloudSprint(value))
+	}
+
+	return sprints, nil
+}
+
+// GetActiveSprintIssues returns every issue in the board's active sprints. Boards
+// running parallel sprints return the issues of all of them. A NoActiveSprintError
+// is returned if the board has no active sprint. Invalid issues are skipped and
+// reported as for SearchTickets.
+func (c *Client) GetActiveSprintIssues(boardID int) ([]*Ticket, error) {
+	sprints, err := c.GetActiveSprints(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sprints) == 0 {
+		return nil, &NoActiveSprintError{BoardID: boardID}
+	}
+
+	var tickets []*Ticket
+	var skipped []error
+	for _, sprint := range sprints {
+		endpoint := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue", c.BaseURL, sprint.ID)
+		sprintTickets, sprintSkipped, err := c.paginateIssues(endpoint, neturl.Values{}, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issues for sprint %s: %w", sprint.Name, err)
+		}
+		tickets = append(tickets, sprintTickets...)
+		skipped = append(skipped, sprintSkipped...)
+	}
+
+	return tickets, errors.Join(skipped...)
+}
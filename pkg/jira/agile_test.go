@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// sprintsHandler serves a board's active sprints
+func sprintsHandler(t *testing.T, sprints ...map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("state"); got != "active" {
+			t.Errorf("state = %q, want active", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{"values": sprints}); err != nil {
+			t.Errorf("failed to encode sprints: %v", err)
+		}
+	}
+}
+
+func TestGetActiveSprintIssues(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/rest/agile/1.0/board/42/sprint", sprintsHandler(t,
+		map[string]any{"id": 10, "name": "Sprint 10", "state": "active"},
+		map[string]any{"id": 11, "name": "Parallel 11", "state": "active"},
+	))
+	mux.Handle("/rest/agile/1.0/sprint/10/issue", searchHandler(t, []map[string]any{
+		issueJSON("RHEL-1", "First"),
+		issueJSON("RHEL-2", "Second"),
+	}))
+	mux.Handle("/rest/agile/1.0/sprint/11/issue", searchHandler(t, []map[string]any{
+		issueJSON("RHEL-3", "Third"),
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tickets, err := newRetryTestClient(server).GetActiveSprintIssues(42)
+	if err != nil {
+		t.Fatalf("GetActiveSprintIssues() error = %v", err)
+	}
+
+	var keys []string
+	for _, ticket := range tickets {
+		keys = append(keys, ticket.Key)
+	}
+	want := []string{"RHEL-1", "RHEL-2", "RHEL-3"}
+	if len(keys) != len(want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("keys[%d] = %q, want %q", i, keys[i], want[i])
+		}
+	}
+}
+
+func TestGetActiveSprintIssuesNoActiveSprint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/rest/agile/1.0/board/7/sprint", sprintsHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, err := newRetryTestClient(server).GetActiveSprintIssues(7)
+	var noSprint *NoActiveSprintError
+	if !errors.As(err, &noSprint) || noSprint.BoardID != 7 {
+		t.Errorf("GetActiveSprintIssues() error = %v, want NoActiveSprintError for board 7", err)
+	}
+}
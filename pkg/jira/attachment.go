@@ -0,0 +1,54 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// AddAttachment uploads content as a file attached to a ticket. Jira requires the
+// X-Atlassian-Token: no-check header to accept multipart uploads. Uploading
+// requires an authentication token.
+func (c *Client) AddAttachment(ticketID, filename string, content []byte) error {
+	if c.token == "" {
+		return fmt.Errorf("uploading attachments requires an authentication token")
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment form: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to write attachment form: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write attachment form: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/attachments", c.BaseURL, ticketID)
+	req, err := c.newRequest("POST", url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	// A lost response doesn't mean the upload failed, so it is never resent,
+	// which would attach the file twice
+	resp, err := c.doOnce(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return c.apiError(resp.StatusCode, respBody)
+	}
+
+	return nil
+}
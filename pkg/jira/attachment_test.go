@@ -0,0 +1,31 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAddAttachmentIsNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("X-Atlassian-Token") != "no-check" {
+			t.Errorf("X-Atlassian-Token = %q, want no-check", r.Header.Get("X-Atlassian-Token"))
+		}
+		if _, _, err := r.FormFile("file"); err != nil {
+			t.Errorf("request has no file part: %v", err)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server, WithToken("token"))
+	if err := client.AddAttachment("A-1", "plan.md", []byte("# Plan")); err == nil {
+		t.Fatal("AddAttachment() succeeded on a 502")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
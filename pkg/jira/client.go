@@ -1,12 +1,21 @@
 package jira
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"mime"
 	"net/http"
+	neturl "net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/backoff"
 )
 
 const (
@@ -14,11 +23,72 @@ const (
 	RedHatJiraBaseURL = "https://issues.redhat.com"
 )
 
+// DefaultEpicLinkField is the Epic Link custom field on issues.redhat.com
+const DefaultEpicLinkField = "customfield_12311140"
+
+// DefaultFields is the set of issue fields requested by GetTicket, matching
+// the fields that parseTicket understands
+var DefaultFields = []string{
+	"summary",
+	"description",
+	"status",
+	"issuetype",
+	"priority",
+	"assignee",
+	"reporter",
+	"created",
+	"updated",
+	"labels",
+	"components",
+	"project",
+	"parent",
+	"duedate",
+	"environment",
+	"votes",
+	"security",
+	"versions",
+	"fixVersions",
+}
+
 // Client represents a Jira API client
 type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
-	token      string // Personal Access Token
+	token      string // Personal Access Token, or API token with email
+	email      string // Account email for Basic authentication, see WithBasicAuth
+
+	fields         []string
+	sprintField    string
+	epicLinkField  string
+	skipValidation bool
+	retryPolicy    backoff.Policy
+	retryNotify    RetryNotifyFunc
+	retryBudget    *RetryBudget
+	timeout        time.Duration
+	headers        http.Header
+	customFields   []string
+
+	// ctx bounds every request, see WithContext
+	ctx context.Context
+
+	// logger receives a debug record for each request, see WithLogger
+	logger *slog.Logger
+
+	// transport is the client's own connection pool, unless replaced by WithHTTPClient
+	transport       *http.Transport
+	maxIdleConns    int
+	idleConnTimeout time.Duration
+
+	fetchUserTimezone bool
+
+	// fetchProject enables project lookups, cached by key, see WithFetchProject
+	fetchProject bool
+	projectMu    sync.Mutex
+	projects     map[string]*Project
+
+	// fieldNames caches the display names of custom fields, see WithCustomFields
+	fieldNamesOnce sync.Once
+	fieldNames     map[string]string
 }
 
 // ClientOption represents a configuration option for the client
@@ -31,26 +101,125 @@ func WithToken(token string) ClientOption {
 	}
 }
 
-// WithBaseURL sets a custom base URL for the Jira instance
+// WithBasicAuth authenticates with Basic authentication using an account email
+// and API token, as Jira Cloud requires, instead of a Bearer personal access token
+func WithBasicAuth(email, apiToken string) ClientOption {
+	return func(c *Client) {
+		c.email = email
+		c.token = apiToken
+	}
+}
+
+// WithBaseURL sets a custom base URL for the Jira instance. A context path is
+// preserved, so "https://example.com/jira" requests "https://example.com/jira/rest/api/2/..."
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
-		c.BaseURL = baseURL
+		c.BaseURL = normalizeBaseURL(baseURL)
+	}
+}
+
+// normalizeBaseURL trims surrounding whitespace and trailing slashes from a base
+// URL so endpoint paths can be appended directly. Any path component, such as
+// the context path of an on-prem deployment, is kept as-is.
+func normalizeBaseURL(baseURL string) string {
+	return strings.TrimRight(strings.TrimSpace(baseURL), "/")
+}
+
+// WithHTTPClient replaces the default HTTP client entirely, giving full control
+// over transports, proxies and tracing. The client's transport determines proxy
+// behavior; WithTimeout may still be combined with it.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.HTTPClient = httpClient
+		}
+	}
+}
+
+// WithTimeout sets the overall timeout for each HTTP request. It is applied after
+// all other options to a copy of the HTTP client, so a client passed to
+// WithHTTPClient is never modified regardless of option order.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithContext makes every request use ctx, so that, for example, a deadline on a
+// whole run also cuts short requests and the waits between their retries
+func WithContext(ctx context.Context) ClientOption {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithHeader adds a header to every request made by the client, e.g. for proxies
+// that require X-Atlassian-Token or their own authentication headers. It may be
+// given multiple times; values for the same key accumulate. Custom headers are
+// applied after the standard ones, so they can also override them.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithFields limits GetTicket to the given issue fields to reduce payload size.
+// Calling WithFields with no fields requests every field from Jira.
+func WithFields(fields ...string) ClientOption {
+	return func(c *Client) {
+		c.fields = fields
+	}
+}
+
+// WithEpicLinkField sets the custom field holding the Epic Link on Jira Server instances
+func WithEpicLinkField(field string) ClientOption {
+	return func(c *Client) {
+		c.epicLinkField = field
+	}
+}
+
+// WithoutValidation disables the required-field check performed by GetTicket,
+// allowing bare tickets (e.g. a key without a summary) to be returned
+func WithoutValidation() ClientOption {
+	return func(c *Client) {
+		c.skipValidation = true
 	}
 }
 
 // NewClient creates a new Jira client for issues.redhat.com
 func NewClient(opts ...ClientOption) *Client {
+	transport := newTransport()
 	client := &Client{
 		BaseURL: RedHatJiraBaseURL,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: transport,
+			Timeout:   30 * time.Second,
 		},
+		fields:          DefaultFields,
+		sprintField:     DefaultSprintField,
+		epicLinkField:   DefaultEpicLinkField,
+		retryPolicy:     backoff.DefaultPolicy(),
+		transport:       transport,
+		maxIdleConns:    DefaultMaxIdleConns,
+		idleConnTimeout: DefaultIdleConnTimeout,
+		logger:          slog.New(slog.DiscardHandler),
+		ctx:             context.Background(),
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(client)
 	}
+	client.configureTransport()
+
+	if client.timeout > 0 {
+		httpClient := *client.HTTPClient
+		httpClient.Timeout = client.timeout
+		client.HTTPClient = &httpClient
+	}
 
 	return client
 }
@@ -58,8 +227,49 @@ func NewClient(opts ...ClientOption) *Client {
 // GetTicket fetches a Jira ticket by its ID or key
 func (c *Client) GetTicket(ticketID string) (*Ticket, error) {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s", c.BaseURL, ticketID)
+	if fields := c.requestFields(); len(fields) > 0 {
+		query := neturl.Values{}
+		query.Set("fields", strings.Join(fields, ","))
+		url += "?" + query.Encode()
+	}
+
+	var jiraResp JiraResponse
+	if err := c.getJSON(url, &jiraResp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, &TicketNotFoundError{TicketID: ticketID}
+		}
+		return nil, err
+	}
+
+	return c.parseAndValidate(&jiraResp)
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// parseAndValidate parses an issue response and validates the resulting ticket
+// unless validation has been disabled
+func (c *Client) parseAndValidate(resp *JiraResponse) (*Ticket, error) {
+	ticket, err := c.parseTicket(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ticket: %w", err)
+	}
+
+	if !c.skipValidation {
+		if err := ticket.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	c.populateAssigneeTimezone(ticket)
+	c.populateProject(ticket)
+	c.populateCustomFieldNames(ticket)
+
+	return ticket, nil
+}
+
+// newRequest creates a request with the standard JSON and authentication headers,
+// plus any custom headers from WithHeader
+func (c *Client) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(c.ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -68,44 +278,108 @@ func (c *Client) GetTicket(ticketID string) (*Ticket, error) {
 	req.Header.Set("Content-Type", "application/json")
 
 	// Add authentication header if token is provided
-	if c.token != "" {
+	switch {
+	case c.token != "" && c.email != "":
+		req.SetBasicAuth(c.email, c.token)
+	case c.token != "":
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	return req, nil
+}
+
+// getJSON performs a GET request and decodes the JSON response into v,
+// returning an APIError for any non-200 response. Network errors, transient
+// statuses and malformed or truncated JSON (e.g. cut off by a proxy) are retried
+// as attempts of the same request, sharing one attempt limit, retry budget and backoff.
+func (c *Client) getJSON(url string, v interface{}) error {
+	req, err := c.newRequest("GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, &TicketNotFoundError{TicketID: ticketID}
+	return c.retry(req.Context(), func(attempt int) error {
+		return c.fetchJSON(req, attempt, v)
+	}, nil)
+}
+
+// fetchJSON makes a single attempt at a GET request and decodes the JSON response
+// into v. Failures worth another attempt are marked with backoff.Retryable.
+func (c *Client) fetchJSON(req *http.Request, attempt int, v interface{}) error {
+	url := req.URL.String()
+	resp, err := c.send(req, attempt)
+	if err != nil {
+		return backoff.Retryable(fmt.Errorf("failed to execute request: %w", err), 0)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    string(body),
+		apiErr := c.apiError(resp.StatusCode, body)
+		if backoff.RetryableStatus(resp.StatusCode) {
+			return backoff.Retryable(apiErr, backoff.RetryAfter(resp.Header, time.Now()))
 		}
+		return apiErr
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return backoff.Retryable(&ResponseParseError{URL: url, Err: fmt.Errorf("failed to read response body: %w", err)}, 0)
 	}
 
-	var jiraResp JiraResponse
-	if err := json.Unmarshal(body, &jiraResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	// Jira returns an HTML page with a 200 status while in maintenance
+	if isNonJSONResponse(resp.Header.Get("Content-Type"), body) {
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("server returned a non-JSON response (likely an HTML maintenance or login page) with status %s", resp.Status),
+		}
 	}
 
-	ticket, err := c.parseTicket(&jiraResp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse ticket: %w", err)
+	if err := json.Unmarshal(body, v); err != nil {
+		parseErr := &ResponseParseError{URL: url, Err: fmt.Errorf("failed to unmarshal response: %w", err)}
+		if parseErr.Retryable() {
+			return backoff.Retryable(parseErr, 0)
+		}
+		return parseErr
 	}
 
-	return ticket, nil
+	return nil
+}
+
+// isNonJSONResponse reports whether a response body is HTML or another non-JSON
+// format, based on the media type of its Content-Type header (ignoring parameters
+// such as charset) and its first non-whitespace byte
+func isNonJSONResponse(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/html", "application/xhtml+xml":
+			return true
+		}
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '<'
+}
+
+// requestFields returns the issue fields to request, including any configured
+// custom fields, or nil to request every field
+func (c *Client) requestFields() []string {
+	if len(c.fields) == 0 {
+		return nil
+	}
+
+	fields := append([]string{}, c.fields...)
+	if c.sprintField != "" {
+		fields = append(fields, c.sprintField)
+	}
+	if c.epicLinkField != "" {
+		fields = append(fields, c.epicLinkField)
+	}
+	return append(fields, c.customFields...)
 }
 
 // parseTicket converts a JiraResponse to a Ticket struct
@@ -115,6 +389,7 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 	ticket := &Ticket{
 		ID:  resp.ID,
 		Key: resp.Key,
+		URL: c.BrowseURL(resp.Key),
 	}
 
 	// Parse summary
@@ -122,17 +397,13 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 		ticket.Summary = summary
 	}
 
-	// Parse description
-	if description, ok := fields["description"].(string); ok {
-		ticket.Description = description
-	}
+	// Parse description and environment, which may be plain text or ADF
+	ticket.Description = parseRichText(fields["description"])
+	ticket.Environment = parseRichText(fields["environment"])
 
 	// Parse status
 	if statusField, ok := fields["status"].(map[string]interface{}); ok {
-		ticket.Status = Status{
-			ID:   getStringFromMap(statusField, "id"),
-			Name: getStringFromMap(statusField, "name"),
-		}
+		ticket.Status = parseStatus(statusField)
 	}
 
 	// Parse issue type
@@ -153,20 +424,13 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 
 	// Parse assignee
 	if assigneeField, ok := fields["assignee"].(map[string]interface{}); ok && assigneeField != nil {
-		ticket.Assignee = &User{
-			AccountID:    getStringFromMap(assigneeField, "accountId"),
-			DisplayName:  getStringFromMap(assigneeField, "displayName"),
-			EmailAddress: getStringFromMap(assigneeField, "emailAddress"),
-		}
+		ticket.Assignee = parseUser(assigneeField)
 	}
 
 	// Parse reporter
-	if reporterField, ok := fields["reporter"].(map[string]interface{}); ok {
-		ticket.Reporter = User{
-			AccountID:    getStringFromMap(reporterField, "accountId"),
-			DisplayName:  getStringFromMap(reporterField, "displayName"),
-			EmailAddress: getStringFromMap(reporterField, "emailAddress"),
-		}
+	// Anonymous and imported tickets can have a null reporter, left nil
+	if reporterField, ok := fields["reporter"].(map[string]interface{}); ok && reporterField != nil {
+		ticket.Reporter = parseUser(reporterField)
 	}
 
 	// Parse timestamps
@@ -182,14 +446,49 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 		}
 	}
 
-	// Parse labels
-	if labelsField, ok := fields["labels"].([]interface{}); ok {
-		for _, label := range labelsField {
-			if labelStr, ok := label.(string); ok {
-				ticket.Labels = append(ticket.Labels, labelStr)
+	// Parse due date
+	if dueDate, ok := fields["duedate"].(string); ok {
+		if t, err := time.Parse("2006-01-02", dueDate); err == nil {
+			ticket.DueDate = t
+		}
+	}
+
+	// Parse votes, absent when voting is disabled
+	if votes, ok := fields["votes"].(map[string]interface{}); ok {
+		if count, ok := votes["votes"].(float64); ok {
+			ticket.Votes = int(count)
+		}
+		ticket.HasVoted, _ = votes["hasVoted"].(bool)
+	}
+
+	// Parse security level, absent for unrestricted tickets
+	if security, ok := fields["security"].(map[string]interface{}); ok {
+		ticket.SecurityLevel = &SecurityLevel{
+			ID:   getStringFromMap(security, "id"),
+			Name: getStringFromMap(security, "name"),
+		}
+	}
+
+	// Parse epic from the parent issue (Jira Cloud) or the Epic Link field (Jira Server)
+	if parentField, ok := fields["parent"].(map[string]interface{}); ok {
+		if parentFields, ok := parentField["fields"].(map[string]interface{}); ok {
+			if issueType, ok := parentFields["issuetype"].(map[string]interface{}); ok && getStringFromMap(issueType, "name") == "Epic" {
+				ticket.Epic = getStringFromMap(parentField, "key")
 			}
 		}
 	}
+	if ticket.Epic == "" && c.epicLinkField != "" {
+		if epicLink, ok := fields[c.epicLinkField].(string); ok {
+			ticket.Epic = epicLink
+		}
+	}
+
+	// Parse labels
+	ticket.Labels = parseLabels(fields["labels"])
+
+	// Parse affects and fix versions
+	ticket.AffectsVersions = parseVersions(fields["versions"])
+	ticket.FixVersions = parseVersions(fields["fixVersions"])
 
 	// Parse components
 	if componentsField, ok := fields["components"].([]interface{}); ok {
@@ -204,11 +503,7 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 
 				// Parse component lead
 				if leadField, ok := compMap["lead"].(map[string]interface{}); ok && leadField != nil {
-					component.Lead = &User{
-						AccountID:    getStringFromMap(leadField, "accountId"),
-						DisplayName:  getStringFromMap(leadField, "displayName"),
-						EmailAddress: getStringFromMap(leadField, "emailAddress"),
-					}
+					component.Lead = parseUser(leadField)
 				}
 
 				ticket.Components = append(ticket.Components, component)
@@ -216,6 +511,11 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 		}
 	}
 
+	// Parse sprints
+	if c.sprintField != "" {
+		ticket.Sprints = parseSprints(fields[c.sprintField])
+	}
+
 	// Parse project
 	if projectField, ok := fields["project"].(map[string]interface{}); ok {
 		ticket.Project = Project{
@@ -225,6 +525,9 @@ func (c *Client) parseTicket(resp *JiraResponse) (*Ticket, error) {
 		}
 	}
 
+	// Parse configured custom fields
+	ticket.CustomFields = c.parseCustomFields(fields)
+
 	return ticket, nil
 }
 
@@ -236,22 +539,22 @@ func (c *Client) TestAuthentication() error {
 
 	url := fmt.Sprintf("%s/rest/api/2/myself", c.BaseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := c.newRequest("GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
-
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("authentication failed: invalid token")
+		if hint := c.authHint(); hint != "" {
+			return fmt.Errorf("authentication failed: invalid token (%s): %w", hint, ErrUnauthorized)
+		}
+		return fmt.Errorf("authentication failed: invalid token: %w", ErrUnauthorized)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -262,41 +565,79 @@ func (c *Client) TestAuthentication() error {
 	return nil
 }
 
-// GetTicketSummary returns a formatted summary of the ticket including components
-func (t *Ticket) GetTicketSummary() string {
-	var summary strings.Builder
-
-	summary.WriteString(fmt.Sprintf("Ticket: %s - %s\n", t.Key, t.Summary))
-	summary.WriteString(fmt.Sprintf("Status: %s | Type: %s | Priority: %s\n",
-		t.Status.Name, t.IssueType.Name, t.Priority.Name))
+// parseUser parses a user object. Jira Cloud identifies users by accountId, while
+// Server and Data Center use the name (username) field and often omit accountId.
+func parseUser(m map[string]interface{}) *User {
+	return &User{
+		AccountID:    getStringFromMap(m, "accountId"),
+		Name:         getStringFromMap(m, "name"),
+		DisplayName:  getStringFromMap(m, "displayName"),
+		EmailAddress: getStringFromMap(m, "emailAddress"),
+		TimeZone:     getStringFromMap(m, "timeZone"),
+	}
+}
 
-	if t.Assignee != nil {
-		summary.WriteString(fmt.Sprintf("Assignee: %s\n", t.Assignee.DisplayName))
-	} else {
-		summary.WriteString("Assignee: Unassigned\n")
+// BrowseURL returns the web page of a ticket, e.g. https://issues.redhat.com/browse/RHEL-12345,
+// or an empty string for an empty key
+func (c *Client) BrowseURL(key string) string {
+	if key == "" {
+		return ""
 	}
+	return fmt.Sprintf("%s/browse/%s", c.BaseURL, neturl.PathEscape(key))
+}
 
-	summary.WriteString(fmt.Sprintf("Reporter: %s\n", t.Reporter.DisplayName))
+// parseStatus parses a status object, including its category key
+func parseStatus(m map[string]interface{}) Status {
+	status := Status{
+		ID:   getStringFromMap(m, "id"),
+		Name: getStringFromMap(m, "name"),
+	}
+	if category, ok := m["statusCategory"].(map[string]interface{}); ok {
+		status.Category = getStringFromMap(category, "key")
+	}
+	return status
+}
 
-	if len(t.Components) > 0 {
-		summary.WriteString("Components: ")
-		for i, comp := range t.Components {
-			if i > 0 {
-				summary.WriteString(", ")
+// parseLabels parses the labels field, which is an array of strings on most instances
+// but a single comma-joined string on some customized ones
+func parseLabels(value interface{}) []string {
+	var labels []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, label := range v {
+			if labelStr, ok := label.(string); ok {
+				labels = append(labels, labelStr)
 			}
-			summary.WriteString(comp.Name)
-			if comp.Lead != nil {
-				summary.WriteString(fmt.Sprintf(" (Lead: %s)", comp.Lead.DisplayName))
+		}
+	case string:
+		for _, label := range strings.Split(v, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
 			}
 		}
-		summary.WriteString("\n")
 	}
+	return labels
+}
 
-	if len(t.Labels) > 0 {
-		summary.WriteString(fmt.Sprintf("Labels: %s\n", strings.Join(t.Labels, ", ")))
+// parseVersions parses an array of version objects, such as versions or fixVersions
+func parseVersions(value interface{}) []Version {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var versions []Version
+	for _, item := range items {
+		if versionMap, ok := item.(map[string]interface{}); ok {
+			released, _ := versionMap["released"].(bool)
+			versions = append(versions, Version{
+				ID:       getStringFromMap(versionMap, "id"),
+				Name:     getStringFromMap(versionMap, "name"),
+				Released: released,
+			})
+		}
 	}
-
-	return summary.String()
+	return versions
 }
 
 // getStringFromMap safely extracts a string value from a map
@@ -305,4 +646,4 @@ func getStringFromMap(m map[string]interface{}, key string) string {
 		return val
 	}
 	return ""
-}
\ No newline at end of file
+}
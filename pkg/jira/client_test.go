@@ -0,0 +1,124 @@
+package jira_test
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira/jiratest"
+)
+
+func TestGetTicketReplay(t *testing.T) {
+	tests := []struct {
+		ticketID string
+		cassette string
+		golden   string
+	}{
+		{"RHEL-21345", "jiratest/testdata/RHEL-21345.cassette.json", "jiratest/testdata/RHEL-21345.ticket.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ticketID, func(t *testing.T) {
+			client, err := jiratest.NewReplayClient(tt.cassette)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := jiratest.CheckTicket(client, tt.ticketID, tt.golden); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGetTicketReplayMissingInteraction(t *testing.T) {
+	client, err := jiratest.NewReplayClient("jiratest/testdata/RHEL-21345.cassette.json", jira.WithMaxAttempts(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTicket("RHEL-1"); err == nil {
+		t.Error("GetTicket() succeeded for a ticket missing from the cassette")
+	}
+}
+
+func TestGetTicketGzipResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []jira.ClientOption
+	}{
+		{name: "transport requested gzip"},
+		{name: "Accept-Encoding set by caller", opts: []jira.ClientOption{jira.WithHeader("Accept-Encoding", "gzip")}},
+		{name: "custom transport", opts: []jira.ClientOption{jira.WithHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Compress regardless of the request, as some proxies and CDNs do
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Encoding", "gzip")
+				gz := gzip.NewWriter(w)
+				defer gz.Close()
+				gz.Write([]byte(`{"key":"RHEL-7","fields":{"summary":"Compressed","status":{"name":"New"}}}`))
+			}))
+			defer server.Close()
+
+			client := jira.NewClient(append([]jira.ClientOption{jira.WithBaseURL(server.URL)}, tt.opts...)...)
+			ticket, err := client.GetTicket("RHEL-7")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if ticket.Key != "RHEL-7" || ticket.Summary != "Compressed" {
+				t.Errorf("ticket = %s %q, want RHEL-7 \"Compressed\"", ticket.Key, ticket.Summary)
+			}
+		})
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		call func(*jira.Client) error
+	}{
+		{name: "GetTicket", path: "/rest/api/2/issue/RHEL-7", call: func(c *jira.Client) error { _, err := c.GetTicket("RHEL-7"); return err }},
+		{name: "TestAuthentication", path: "/rest/api/2/myself", call: func(c *jira.Client) error { return c.TestAuthentication() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("path = %q, want %q", r.URL.Path, tt.path)
+				}
+				header = r.Header.Clone()
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"key":"RHEL-7","fields":{"summary":"Headers","status":{"name":"New"}}}`))
+			}))
+			defer server.Close()
+
+			client := jira.NewClient(
+				jira.WithBaseURL(server.URL),
+				jira.WithToken("token"),
+				jira.WithHeader("X-Atlassian-Token", "no-check"),
+				jira.WithHeader("X-Proxy-Route", "a"),
+				jira.WithHeader("X-Proxy-Route", "b"),
+			)
+			if err := tt.call(client); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+
+			if got := header.Get("X-Atlassian-Token"); got != "no-check" {
+				t.Errorf("X-Atlassian-Token = %q, want no-check", got)
+			}
+			if got := header.Values("X-Proxy-Route"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+				t.Errorf("X-Proxy-Route = %q, want [a b]", got)
+			}
+			if got := header.Get("Authorization"); got != "Bearer token" {
+				t.Errorf("Authorization = %q, want the token to still be sent", got)
+			}
+		})
+	}
+}
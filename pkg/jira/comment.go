@@ -0,0 +1,130 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commentPageSize is the number of comments requested per page by GetComments
+const commentPageSize = 100
+
+// Comment is a comment on a ticket
+type Comment struct {
+	ID      string
+	Author  User
+	Body    string
+	Created time.Time
+}
+
+// commentPage is a page of the issue comment API response
+type commentPage struct {
+	StartAt  int                      `json:"startAt"`
+	Total    int                      `json:"total"`
+	Comments []map[string]interface{} `json:"comments"`
+}
+
+// CommentVisibility restricts a comment to members of a project role or group
+type CommentVisibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ParseCommentVisibility parses a visibility restriction in "type:value" form,
+// e.g. "role:Developers" or "group:jira-users"
+func ParseCommentVisibility(s string) (*CommentVisibility, error) {
+	kind, value, ok := strings.Cut(s, ":")
+	kind, value = strings.ToLower(strings.TrimSpace(kind)), strings.TrimSpace(value)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("invalid comment visibility %q: expected type:value, e.g. role:Developers", s)
+	}
+	if kind != "role" && kind != "group" {
+		return nil, fmt.Errorf("invalid comment visibility type %q: must be role or group", kind)
+	}
+	return &CommentVisibility{Type: kind, Value: value}, nil
+}
+
+// commentRequest is the body of a request adding a comment to an issue
+type commentRequest struct {
+	Body       string             `json:"body"`
+	Visibility *CommentVisibility `json:"visibility,omitempty"`
+}
+
+// AddComment posts a comment to a ticket, optionally restricted to a role or group.
+// Posting requires an authentication token.
+func (c *Client) AddComment(ticketID, body string, visibility *CommentVisibility) error {
+	if c.token == "" {
+		return fmt.Errorf("posting comments requires an authentication token")
+	}
+
+	payload, err := json.Marshal(commentRequest{Body: body, Visibility: visibility})
+	if err != nil {
+		return fmt.Errorf("failed to encode comment: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/comment", c.BaseURL, ticketID)
+	req, err := c.newRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return c.apiError(resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
+// GetComments fetches all comments on a ticket, oldest first
+func (c *Client) GetComments(ticketID string) ([]Comment, error) {
+	var comments []Comment
+	for {
+		query := neturl.Values{}
+		query.Set("startAt", strconv.Itoa(len(comments)))
+		query.Set("maxResults", strconv.Itoa(commentPageSize))
+		query.Set("orderBy", "created")
+		url := fmt.Sprintf("%s/rest/api/2/issue/%s/comment?%s", c.BaseURL, ticketID, query.Encode())
+
+		var page commentPage
+		if err := c.getJSON(url, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch comments for %s: %w", ticketID, err)
+		}
+		for _, raw := range page.Comments {
+			comments = append(comments, parseComment(raw))
+		}
+
+		if len(page.Comments) == 0 || len(comments) >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// parseComment parses a comment object from the issue comment API
+func parseComment(m map[string]interface{}) Comment {
+	comment := Comment{
+		ID:   getStringFromMap(m, "id"),
+		Body: strings.TrimSpace(parseRichText(m["body"])),
+	}
+	if author, ok := m["author"].(map[string]interface{}); ok {
+		comment.Author = *parseUser(author)
+	}
+	if created, ok := m["created"].(string); ok {
+		if t, err := time.Parse("2006-01-02T15:04:05.000-0700", created); err == nil {
+			comment.Created = t
+		}
+	}
+	return comment
+}
@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAddCommentVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility string
+		want       string
+	}{
+		{name: "public", want: `{"body":"Plan"}`},
+		{name: "role", visibility: "role:Developers", want: `{"body":"Plan","visibility":{"type":"role","value":"Developers"}}`},
+		{name: "group", visibility: " Group : jira-users ", want: `{"body":"Plan","visibility":{"type":"group","value":"jira-users"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotPath, gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotMethod, gotPath, gotBody = r.Method, r.URL.Path, string(body)
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			var visibility *CommentVisibility
+			if tt.visibility != "" {
+				var err error
+				if visibility, err = ParseCommentVisibility(tt.visibility); err != nil {
+					t.Fatalf("ParseCommentVisibility() error = %v", err)
+				}
+			}
+			if err := NewClient(WithBaseURL(server.URL), WithToken("token")).AddComment("RHEL-1", "Plan", visibility); err != nil {
+				t.Fatalf("AddComment() error = %v", err)
+			}
+
+			if gotMethod != http.MethodPost || gotPath != "/rest/api/2/issue/RHEL-1/comment" {
+				t.Errorf("request = %s %s, want POST /rest/api/2/issue/RHEL-1/comment", gotMethod, gotPath)
+			}
+			if !jsonEqual(t, gotBody, tt.want) {
+				t.Errorf("body = %s, want %s", gotBody, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommentVisibilityInvalid(t *testing.T) {
+	for _, s := range []string{"Developers", "role:", "user:alice"} {
+		if _, err := ParseCommentVisibility(s); err == nil {
+			t.Errorf("ParseCommentVisibility(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+// jsonEqual reports whether two JSON documents are equal, ignoring formatting
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		t.Fatalf("invalid JSON %q: %v", a, err)
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		t.Fatalf("invalid JSON %q: %v", b, err)
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return string(ja) == string(jb)
+}
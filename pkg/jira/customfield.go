@@ -0,0 +1,122 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithCustomFields requests additional custom fields (e.g. "customfield_12313942")
+// and exposes their values as text in Ticket.CustomFields, keyed by field ID, with
+// their display names in Ticket.CustomFieldNames
+func WithCustomFields(fields ...string) ClientOption {
+	return func(c *Client) {
+		c.customFields = append(c.customFields, fields...)
+	}
+}
+
+// fieldInfo is an entry of the field list API response
+type fieldInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetFieldNames returns the display names of the instance's fields, keyed by ID
+func (c *Client) GetFieldNames() (map[string]string, error) {
+	var fields []fieldInfo
+	if err := c.getJSON(fmt.Sprintf("%s/rest/api/2/field", c.BaseURL), &fields); err != nil {
+		return nil, fmt.Errorf("failed to list fields: %w", err)
+	}
+
+	names := make(map[string]string, len(fields))
+	for _, field := range fields {
+		names[field.ID] = field.Name
+	}
+	return names, nil
+}
+
+// populateCustomFieldNames sets the display names of the ticket's custom fields.
+// The field list is fetched once per client; if it can't be read, the fields are
+// left without names and shown by ID.
+func (c *Client) populateCustomFieldNames(ticket *Ticket) {
+	if len(ticket.CustomFields) == 0 {
+		return
+	}
+
+	c.fieldNamesOnce.Do(func() {
+		c.fieldNames, _ = c.GetFieldNames()
+	})
+
+	for id := range ticket.CustomFields {
+		if name := c.fieldNames[id]; name != "" {
+			if ticket.CustomFieldNames == nil {
+				ticket.CustomFieldNames = make(map[string]string)
+			}
+			ticket.CustomFieldNames[id] = name
+		}
+	}
+}
+
+// parseCustomFields flattens the configured custom fields of an issue to text,
+// skipping fields that are unset or have no displayable value
+func (c *Client) parseCustomFields(fields map[string]interface{}) map[string]string {
+	var values map[string]string
+	for _, field := range c.customFields {
+		text := formatFieldValue(fields[field])
+		if text == "" {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[field] = text
+	}
+	return values
+}
+
+// formatFieldValue flattens a custom field value to text. Select options
+// ({"value": "..."}) become their value, cascading selects
+// ({"value": "Parent", "child": {"value": "Child"}}) become "Parent / Child",
+// multi-selects and other arrays are comma-joined, and users, versions and other
+// objects use their display name, name or key.
+func formatFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			if text := formatFieldValue(item); text != "" {
+				items = append(items, text)
+			}
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		return formatOptionValue(v)
+	}
+	return ""
+}
+
+// formatOptionValue flattens a select option, following any cascading child
+// option, or falls back to the object's display name, name or key
+func formatOptionValue(m map[string]interface{}) string {
+	if value := getStringFromMap(m, "value"); value != "" {
+		if child, ok := m["child"].(map[string]interface{}); ok {
+			if childValue := formatOptionValue(child); childValue != "" {
+				return value + " / " + childValue
+			}
+		}
+		return value
+	}
+
+	for _, key := range []string{"displayName", "name", "key"} {
+		if text := getStringFromMap(m, key); text != "" {
+			return text
+		}
+	}
+	return ""
+}
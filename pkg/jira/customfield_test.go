@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCustomFieldNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		fieldStatus int
+		want        map[string]string
+		wantText    string
+	}{
+		{
+			name:        "names from the field list",
+			fieldStatus: http.StatusOK,
+			want:        map[string]string{"customfield_10001": "Team"},
+			wantText:    "Team: Platform",
+		},
+		{
+			name:        "field list unavailable",
+			fieldStatus: http.StatusForbidden,
+			wantText:    "customfield_10001: Platform",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fieldRequests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/rest/api/2/field" {
+					fieldRequests.Add(1)
+					w.WriteHeader(tt.fieldStatus)
+					json.NewEncoder(w).Encode([]fieldInfo{{ID: "summary", Name: "Summary"}, {ID: "customfield_10001", Name: "Team"}})
+					return
+				}
+				issue := issueJSON(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "Summary")
+				issue["fields"].(map[string]any)["customfield_10001"] = map[string]any{"value": "Platform"}
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithCustomFields("customfield_10001"))
+			for _, key := range []string{"A-1", "A-2"} {
+				ticket, err := client.GetTicket(key)
+				if err != nil {
+					t.Fatalf("GetTicket(%s) error = %v", key, err)
+				}
+				if len(ticket.CustomFieldNames) != len(tt.want) || ticket.CustomFieldNames["customfield_10001"] != tt.want["customfield_10001"] {
+					t.Errorf("CustomFieldNames = %v, want %v", ticket.CustomFieldNames, tt.want)
+				}
+				if text := ticket.MarshalForPrompt(); !strings.Contains(text, tt.wantText) {
+					t.Errorf("MarshalForPrompt() doesn't contain %q:\n%s", tt.wantText, text)
+				}
+			}
+
+			// The field list is requested once per client, even when it fails
+			if got := fieldRequests.Load(); got != 1 {
+				t.Errorf("field list requests = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -1,6 +1,22 @@
 package jira
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotFound is matched by errors.Is for missing tickets and 404 API responses
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnauthorized is matched by errors.Is for rejected credentials and 401 API responses
+	ErrUnauthorized = errors.New("unauthorized")
+)
 
 // TicketNotFoundError represents an error when a ticket is not found
 type TicketNotFoundError struct {
@@ -11,18 +27,163 @@ func (e *TicketNotFoundError) Error() string {
 	return fmt.Sprintf("ticket %s not found", e.TicketID)
 }
 
-// APIError represents a general API error
+// Is reports whether target is ErrNotFound
+func (e *TicketNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// APIError represents a general API error. Message holds the raw response body,
+// while Errors and ErrorFields hold the messages parsed from Jira's JSON error format.
+// Hint, when set, suggests a likely fix (e.g. the wrong authentication mode).
 type APIError struct {
-	StatusCode int
-	Message    string
+	StatusCode  int
+	Message     string
+	Errors      []string
+	ErrorFields map[string]string
+	Hint        string
+}
+
+// jiraErrorResponse is the JSON body Jira returns for failed requests
+type jiraErrorResponse struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// newAPIError creates an APIError from a response, parsing Jira's structured
+// error messages when the body is JSON
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Message:    string(body),
+	}
+
+	var parsed jiraErrorResponse
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		apiErr.Errors = parsed.ErrorMessages
+		if len(parsed.Errors) > 0 {
+			apiErr.ErrorFields = parsed.Errors
+		}
+	}
+
+	return apiErr
 }
 
 func (e *APIError) Error() string {
-	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	msg := e.message()
+	if e.Hint != "" {
+		msg += fmt.Sprintf(" (hint: %s)", e.Hint)
+	}
+	return msg
+}
+
+// message formats the status code and Jira's error messages
+func (e *APIError) message() string {
+	if len(e.Errors) == 0 && len(e.ErrorFields) == 0 {
+		return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	}
+
+	messages := append([]string{}, e.Errors...)
+	fields := make([]string, 0, len(e.ErrorFields))
+	for field := range e.ErrorFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, e.ErrorFields[field]))
+	}
+
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, strings.Join(messages, "; "))
+}
+
+// Is matches ErrNotFound and ErrUnauthorized against the response status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
+
+// ResponseParseError represents a successful response whose body could not be
+// read or decoded as JSON, as happens when a proxy truncates the response
+type ResponseParseError struct {
+	URL string
+	Err error
+}
+
+func (e *ResponseParseError) Error() string {
+	return fmt.Sprintf("invalid response from %s: %v", e.URL, e.Err)
+}
+
+func (e *ResponseParseError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the body was malformed or cut short, so fetching it
+// again may succeed. Well-formed JSON of an unexpected shape is not retryable.
+func (e *ResponseParseError) Retryable() bool {
+	var syntaxErr *json.SyntaxError
+	return errors.As(e.Err, &syntaxErr) || errors.Is(e.Err, io.ErrUnexpectedEOF)
 }
 
-// IsTicketNotFound checks if the error is a TicketNotFoundError
+// NoActiveSprintError represents an Agile board without an active sprint
+type NoActiveSprintError struct {
+	BoardID int
+}
+
+func (e *NoActiveSprintError) Error() string {
+	return fmt.Sprintf("board %d has no active sprint", e.BoardID)
+}
+
+// TransitionUnavailableError reports that a transition can't be made from the
+// ticket's current status, listing the transitions that can
+type TransitionUnavailableError struct {
+	TicketID   string
+	Transition string
+	Available  []string
+}
+
+func (e *TransitionUnavailableError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("transition %q is not available for %s: no transitions are available from its current status", e.Transition, e.TicketID)
+	}
+	return fmt.Sprintf("transition %q is not available for %s (available: %s)", e.Transition, e.TicketID, strings.Join(e.Available, ", "))
+}
+
+// IsTicketNotFound checks if the error, or any error it wraps, is a not-found error
 func IsTicketNotFound(err error) bool {
-	_, ok := err.(*TicketNotFoundError)
-	return ok
-}
\ No newline at end of file
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized checks if the error, or any error it wraps, is an authentication failure
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// InvalidIssueError reports an issue in search results that was skipped because
+// it couldn't be parsed or failed validation
+type InvalidIssueError struct {
+	TicketID string
+	ID       string
+	Err      error
+}
+
+func (e *InvalidIssueError) Error() string {
+	return fmt.Sprintf("skipped invalid issue %s: %v", e.TicketID, e.Err)
+}
+
+func (e *InvalidIssueError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError represents a ticket that was fetched but is missing required fields
+type ValidationError struct {
+	TicketID string
+	Field    string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("ticket %s has an empty %s (the response may have been stripped due to missing permissions; try authenticating with a token)", e.TicketID, e.Field)
+}
@@ -0,0 +1,120 @@
+package jira
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorSentinels(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		call       func(*Client) error
+		wantTarget error
+		wantNot    error
+	}{
+		{
+			name:       "missing ticket",
+			status:     http.StatusNotFound,
+			call:       func(c *Client) error { _, err := c.GetTicket("RHEL-1"); return err },
+			wantTarget: ErrNotFound,
+			wantNot:    ErrUnauthorized,
+		},
+		{
+			name:       "rejected token on ticket fetch",
+			status:     http.StatusUnauthorized,
+			call:       func(c *Client) error { _, err := c.GetTicket("RHEL-1"); return err },
+			wantTarget: ErrUnauthorized,
+			wantNot:    ErrNotFound,
+		},
+		{
+			name:       "rejected token on auth test",
+			status:     http.StatusUnauthorized,
+			call:       func(c *Client) error { return c.TestAuthentication() },
+			wantTarget: ErrUnauthorized,
+			wantNot:    ErrNotFound,
+		},
+		{
+			name:       "missing comments",
+			status:     http.StatusNotFound,
+			call:       func(c *Client) error { _, err := c.GetComments("RHEL-1"); return err },
+			wantTarget: ErrNotFound,
+			wantNot:    ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := tt.call(newRetryTestClient(server, WithToken("token")))
+			if !errors.Is(err, tt.wantTarget) {
+				t.Errorf("error = %v, want it to match %v", err, tt.wantTarget)
+			}
+			if errors.Is(err, tt.wantNot) {
+				t.Errorf("error = %v, want it not to match %v", err, tt.wantNot)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessages(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		want       string
+		wantErrors []string
+		wantFields map[string]string
+	}{
+		{
+			name:       "error messages",
+			status:     http.StatusNotFound,
+			body:       `{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`,
+			want:       "API error 404: Issue does not exist or you do not have permission to see it.",
+			wantErrors: []string{"Issue does not exist or you do not have permission to see it."},
+		},
+		{
+			name:       "field errors sorted by field",
+			status:     http.StatusBadRequest,
+			body:       `{"errorMessages":["Invalid request"],"errors":{"summary":"Summary is required","assignee":"User does not exist"}}`,
+			want:       "API error 400: Invalid request; assignee: User does not exist; summary: Summary is required",
+			wantErrors: []string{"Invalid request"},
+			wantFields: map[string]string{"summary": "Summary is required", "assignee": "User does not exist"},
+		},
+		{
+			name:   "plain text body",
+			status: http.StatusBadGateway,
+			body:   "Bad Gateway",
+			want:   "API error 502: Bad Gateway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(tt.status, []byte(tt.body))
+			if got := apiErr.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if len(apiErr.Errors) != len(tt.wantErrors) {
+				t.Errorf("Errors = %q, want %q", apiErr.Errors, tt.wantErrors)
+			}
+			if len(apiErr.ErrorFields) != len(tt.wantFields) {
+				t.Errorf("ErrorFields = %v, want %v", apiErr.ErrorFields, tt.wantFields)
+			}
+			for field, want := range tt.wantFields {
+				if got := apiErr.ErrorFields[field]; got != want {
+					t.Errorf("ErrorFields[%q] = %q, want %q", field, got, want)
+				}
+			}
+			if apiErr.Message != tt.body {
+				t.Errorf("Message = %q, want the raw body", apiErr.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []ClientOption
+		wantFields string
+	}{
+		{
+			name:       "default fields",
+			wantFields: strings.Join(append(append([]string{}, DefaultFields...), DefaultSprintField, DefaultEpicLinkField), ","),
+		},
+		{
+			name:       "reduced fields",
+			opts:       []ClientOption{WithFields("summary", "status"), WithSprintField(""), WithEpicLinkField("")},
+			wantFields: "summary,status",
+		},
+		{
+			name: "all fields",
+			opts: []ClientOption{WithFields()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFields string
+			var hasFields bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotFields, hasFields = r.URL.Query().Get("fields"), r.URL.Query().Has("fields")
+				// Only the requested fields come back
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issueJSON("A-1", "Summary"))
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(append([]ClientOption{WithBaseURL(server.URL)}, tt.opts...)...).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if gotFields != tt.wantFields || hasFields != (tt.wantFields != "") {
+				t.Errorf("fields = %q (set %v), want %q", gotFields, hasFields, tt.wantFields)
+			}
+
+			// Requested fields missing from the payload are left empty
+			if ticket.Summary != "Summary" || ticket.Status.Name != "New" {
+				t.Errorf("ticket = %q %q, want the summary and status parsed", ticket.Summary, ticket.Status.Name)
+			}
+			if ticket.Assignee != nil || len(ticket.Labels) != 0 || ticket.Description != "" {
+				t.Errorf("ticket = %+v, want absent fields left empty", ticket)
+			}
+		})
+	}
+}
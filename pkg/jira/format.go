@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AssigneeName returns the assignee's display name, or "Unassigned"
+func (t *Ticket) AssigneeName() string {
+	if t.Assignee == nil {
+		return "Unassigned"
+	}
+	return t.Assignee.DisplayName
+}
+
+// ReporterName returns the reporter's display name, or "Unknown" for tickets
+// without a reporter, such as anonymous or imported ones
+func (t *Ticket) ReporterName() string {
+	if t.Reporter == nil || t.Reporter.DisplayName == "" {
+		return "Unknown"
+	}
+	return t.Reporter.DisplayName
+}
+
+// Mention returns wiki markup that mentions the user in a comment: the username
+// on Server and Data Center, or the account ID on Cloud. It falls back to the
+// display name when neither is known.
+func (u *User) Mention() string {
+	switch {
+	case u.Name != "":
+		return fmt.Sprintf("[~%s]", u.Name)
+	case u.AccountID != "":
+		return fmt.Sprintf("[~accountid:%s]", u.AccountID)
+	}
+	return u.DisplayName
+}
+
+// ComponentNames returns the ticket's components as a comma-separated list,
+// each followed by its lead when one is set
+func (t *Ticket) ComponentNames() string {
+	return t.ComponentNamesLimit(0)
+}
+
+// ComponentNamesLimit is like ComponentNames, but lists at most limit components
+// followed by "... and N more" for the rest. A limit of zero or less lists all of them.
+func (t *Ticket) ComponentNamesLimit(limit int) string {
+	names := make([]string, 0, len(t.Components))
+	for i, comp := range t.Components {
+		if limit > 0 && i == limit {
+			names = append(names, fmt.Sprintf("... and %d more", len(t.Components)-limit))
+			break
+		}
+		if comp.Lead != nil {
+			names = append(names, fmt.Sprintf("%s (Lead: %s)", comp.Name, comp.Lead.DisplayName))
+		} else {
+			names = append(names, comp.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// Describe formats the sprint as its name followed by its date range, when known
+func (s *Sprint) Describe() string {
+	if s.StartDate.IsZero() || s.EndDate.IsZero() {
+		return s.Name
+	}
+	return fmt.Sprintf("%s (%s to %s)", s.Name, s.StartDate.Format("2006-01-02"), s.EndDate.Format("2006-01-02"))
+}
+
+// GetTicketSummary returns a formatted summary of the ticket including components
+func (t *Ticket) GetTicketSummary() string {
+	var summary strings.Builder
+	t.writeSummary(&summary)
+	return summary.String()
+}
+
+// MarshalForPrompt returns the canonical text representation of the ticket sent to
+// the LLM: the ticket summary followed by the assignee's time zone, epic, sprint, due
+// date, votes, custom fields, project, environment and description when set. The
+// output is stable for a given ticket.
+func (t *Ticket) MarshalForPrompt() string {
+	var text strings.Builder
+	t.writeSummary(&text)
+
+	if t.Assignee != nil && t.Assignee.TimeZone != "" {
+		text.WriteString(fmt.Sprintf("Assignee Time Zone: %s\n", t.Assignee.TimeZone))
+	}
+	if t.Epic != "" {
+		text.WriteString(fmt.Sprintf("Epic: %s\n", t.Epic))
+	}
+	if sprint := t.ActiveSprint(); sprint != nil {
+		text.WriteString(fmt.Sprintf("Sprint: %s\n", sprint.Describe()))
+	}
+	if !t.DueDate.IsZero() {
+		text.WriteString(fmt.Sprintf("Due Date: %s\n", t.DueDate.Format("2006-01-02")))
+	}
+	if t.Votes > 0 {
+		text.WriteString(fmt.Sprintf("Votes: %d\n", t.Votes))
+	}
+	fieldIDs := make([]string, 0, len(t.CustomFields))
+	for id := range t.CustomFields {
+		fieldIDs = append(fieldIDs, id)
+	}
+	sort.Strings(fieldIDs)
+	for _, id := range fieldIDs {
+		text.WriteString(fmt.Sprintf("%s: %s\n", t.CustomFieldName(id), t.CustomFields[id]))
+	}
+	if t.Project.Description != "" {
+		lead := ""
+		if t.Project.Lead != nil {
+			lead = fmt.Sprintf(" (project lead: %s)", t.Project.Lead.DisplayName)
+		}
+		text.WriteString(fmt.Sprintf("\nProject %s%s:\n%s\n", t.Project.Name, lead, strings.TrimSpace(t.Project.Description)))
+	}
+	if t.Environment != "" {
+		text.WriteString(fmt.Sprintf("\nEnvironment:\n%s\n", strings.TrimSpace(t.Environment)))
+	}
+	if t.Description != "" {
+		text.WriteString(fmt.Sprintf("\nDescription:\n%s\n", strings.TrimSpace(t.Description)))
+	}
+
+	return text.String()
+}
+
+// writeSummary writes the key, status, people, components and labels shared by
+// GetTicketSummary and MarshalForPrompt
+func (t *Ticket) writeSummary(w *strings.Builder) {
+	w.WriteString(fmt.Sprintf("Ticket: %s - %s\n", t.Key, t.Summary))
+	w.WriteString(fmt.Sprintf("Status: %s | Type: %s | Priority: %s\n",
+		t.Status.Name, t.IssueType.Name, t.Priority.Name))
+	w.WriteString(fmt.Sprintf("Assignee: %s\n", t.AssigneeName()))
+	w.WriteString(fmt.Sprintf("Reporter: %s\n", t.ReporterName()))
+
+	if len(t.Components) > 0 {
+		w.WriteString(fmt.Sprintf("Components: %s\n", t.ComponentNames()))
+	}
+	if len(t.Labels) > 0 {
+		w.WriteString(fmt.Sprintf("Labels: %s\n", strings.Join(t.Labels, ", ")))
+	}
+}
@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// fullTicket returns a ticket with every field used by MarshalForPrompt set
+func fullTicket() *Ticket {
+	return &Ticket{
+		Key:         "DEMO-123",
+		Summary:     "Add retry support to the export job",
+		Description: "The nightly export job fails on transient network errors.\n\nAcceptance criteria:\n* Failed uploads are retried\n",
+		Environment: "RHEL 9.4, x86_64",
+		Status:      Status{Name: "In Progress"},
+		IssueType:   IssueType{Name: "Story"},
+		Priority:    Priority{Name: "Major"},
+		Assignee:    &User{DisplayName: "Alex Assignee", TimeZone: "Europe/Berlin"},
+		Reporter:    &User{DisplayName: "Riley Reporter"},
+		DueDate:     time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC),
+		Labels:      []string{"reliability", "export"},
+		Votes:       7,
+		Components: []Component{
+			{Name: "Export", Lead: &User{DisplayName: "Lee Lead"}},
+			{Name: "Storage"},
+		},
+		Project: Project{
+			Key:         "DEMO",
+			Name:        "Demo Project",
+			Description: "Data pipelines and exports",
+			Lead:        &User{DisplayName: "Pat Lead"},
+		},
+		Sprints: []Sprint{{
+			Name:      "Demo Sprint 7",
+			State:     "active",
+			StartDate: time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC),
+		}},
+		Epic:             "DEMO-100",
+		CustomFields:     map[string]string{"customfield_10002": "5", "customfield_10001": "Platform / Networking"},
+		CustomFieldNames: map[string]string{"customfield_10001": "Team"},
+	}
+}
+
+func TestMarshalForPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		ticket *Ticket
+		golden string
+	}{
+		{name: "fully populated", ticket: fullTicket(), golden: "ticket_full.golden"},
+		{
+			name:   "minimal",
+			ticket: &Ticket{Key: "DEMO-1", Summary: "Minimal", Status: Status{Name: "New"}, IssueType: IssueType{Name: "Task"}},
+			golden: "ticket_minimal.golden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.ticket.MarshalForPrompt()
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("MarshalForPrompt() =\n%s\nwant\n%s", got, want)
+			}
+			if again := tt.ticket.MarshalForPrompt(); again != got {
+				t.Errorf("MarshalForPrompt() is not stable:\n%s\nthen\n%s", got, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package jira
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipBody decompresses a gzip-encoded response body, closing both the reader and
+// the underlying body
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// decodeContentEncoding transparently decompresses gzip-encoded response bodies.
+// The default transport already does this when it requested gzip itself, but
+// proxies and CDNs may compress responses regardless, and custom transports or
+// a WithHeader Accept-Encoding disable the transport's handling.
+func decodeContentEncoding(resp *http.Response) error {
+	if resp.Uncompressed || !strings.EqualFold(strings.TrimSpace(resp.Header.Get("Content-Encoding")), "gzip") {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if errors.Is(err, io.EOF) {
+		// An empty body has nothing to decompress
+		resp.Body = http.NoBody
+	} else if err != nil {
+		return fmt.Errorf("failed to decompress gzip response: %w", err)
+	} else {
+		resp.Body = &gzipBody{Reader: reader, body: resp.Body}
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
@@ -0,0 +1,201 @@
+// Package jiratest records and replays Jira HTTP interactions as cassettes, so the
+// client and its parsing can be exercised against real responses without a live
+// Jira. A cassette recorded with a Recorder is replayed by passing
+// Cassette.HTTPClient to jira.WithHTTPClient.
+package jiratest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Cassette is a list of recorded HTTP interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and the response it received
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request. Headers are not recorded, so cassettes
+// never contain credentials.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// RecordedResponse is a response as received, with its body decoded as text
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %w", path, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to path as indented JSON
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette %s: %w", path, err)
+	}
+	return nil
+}
+
+// HTTPClient returns an HTTP client that answers requests from the cassette
+// instead of the network
+func (c *Cassette) HTTPClient() *http.Client {
+	return &http.Client{Transport: &replayTransport{cassette: c}}
+}
+
+// find returns the interaction recorded for a request. The method and path must
+// match; an interaction with the same query is preferred, so cassettes keep
+// working when the requested fields change.
+func (c *Cassette) find(req *http.Request) (*Interaction, bool) {
+	var pathMatch *Interaction
+	for i := range c.Interactions {
+		interaction := &c.Interactions[i]
+		recorded, err := neturl.Parse(interaction.Request.URL)
+		if err != nil || interaction.Request.Method != req.Method || recorded.Path != req.URL.Path {
+			continue
+		}
+		if recorded.Query().Encode() == req.URL.Query().Encode() {
+			return interaction, true
+		}
+		if pathMatch == nil {
+			pathMatch = interaction
+		}
+	}
+	return pathMatch, pathMatch != nil
+}
+
+// replayTransport serves requests from a cassette
+type replayTransport struct {
+	cassette *Cassette
+}
+
+// RoundTrip returns the recorded response for the request, or an error if the
+// cassette has none
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	interaction, ok := t.cassette.find(req)
+	if !ok {
+		return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL)
+	}
+
+	recorded := interaction.Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewBufferString(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Recorder is an HTTP transport that forwards requests and records each
+// interaction into a cassette
+type Recorder struct {
+	// Transport sends the requests; nil uses http.DefaultTransport
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// HTTPClient returns an HTTP client recording through r
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip sends the request and records it with its response. The response
+// body is read in full and replaced, so callers still see it unchanged.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := resp.Header.Clone()
+	headers.Del("Set-Cookie")
+	recorded := body
+	if strings.EqualFold(strings.TrimSpace(headers.Get("Content-Encoding")), "gzip") {
+		// Cassettes store text bodies, so compressed responses are recorded decoded
+		if recorded, err = gunzip(body); err != nil {
+			return nil, fmt.Errorf("failed to record response body: %w", err)
+		}
+		headers.Del("Content-Encoding")
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  RecordedRequest{Method: req.Method, URL: req.URL.String()},
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Headers: headers, Body: string(recorded)},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// gunzip decompresses a gzip-encoded body
+func gunzip(body []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// Save writes the interactions recorded so far to path
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(path)
+}
@@ -0,0 +1,82 @@
+package jiratest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"os"
+	"strings"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// NewReplayClient returns a Jira client answering every request from the cassette
+// at path. The base URL is taken from the first recorded request, and opts are
+// applied after it, so they may override it.
+func NewReplayClient(path string, opts ...jira.ClientOption) (*jira.Client, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(cassette.Interactions) == 0 {
+		return nil, fmt.Errorf("cassette %s has no interactions", path)
+	}
+
+	recorded, err := neturl.Parse(cassette.Interactions[0].Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL in cassette %s: %w", path, err)
+	}
+	baseURL := recorded.Scheme + "://" + recorded.Host
+
+	clientOpts := []jira.ClientOption{jira.WithBaseURL(baseURL), jira.WithHTTPClient(cassette.HTTPClient())}
+	return jira.NewClient(append(clientOpts, opts...)...), nil
+}
+
+// CheckTicket fetches ticketID through client and compares the parsed ticket,
+// encoded as indented JSON, with the golden file at wantPath. It returns an error
+// naming the first differing line, or nil when they match.
+func CheckTicket(client *jira.Client, ticketID, wantPath string) error {
+	ticket, err := client.GetTicket(ticketID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", ticketID, err)
+	}
+	got, err := EncodeTicket(ticket)
+	if err != nil {
+		return err
+	}
+
+	want, err := os.ReadFile(wantPath)
+	if err != nil {
+		return fmt.Errorf("failed to read golden ticket %s: %w", wantPath, err)
+	}
+	if bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(want)) {
+		return nil
+	}
+
+	gotLines := strings.Split(strings.TrimSpace(string(got)), "\n")
+	wantLines := strings.Split(strings.TrimSpace(string(want)), "\n")
+	for i := 0; i < max(len(gotLines), len(wantLines)); i++ {
+		var gotLine, wantLine string
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if gotLine != wantLine {
+			return fmt.Errorf("%s differs from %s at line %d:\n  got:  %s\n  want: %s", ticketID, wantPath, i+1, strings.TrimSpace(gotLine), strings.TrimSpace(wantLine))
+		}
+	}
+	return nil
+}
+
+// EncodeTicket encodes a ticket the way golden files store it, to create or
+// update them after an intended parser change
+func EncodeTicket(ticket *jira.Ticket) ([]byte, error) {
+	data, err := json.MarshalIndent(ticket, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ticket: %w", err)
+	}
+	return append(data, '\n'), nil
+}
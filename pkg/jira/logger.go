@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// WithLogger logs each request the client makes (method, URL, status and duration)
+// at debug level. Credentials are never logged. Without this option the client is silent.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// logRequest records a completed request attempt at debug level
+func (c *Client) logRequest(req *http.Request, resp *http.Response, err error, attempt int, duration time.Duration) {
+	ctx := req.Context()
+	if !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL.String())),
+		slog.Duration("duration", duration),
+	}
+	if attempt > 1 {
+		attrs = append(attrs, slog.Int("attempt", attempt))
+	}
+	if err != nil {
+		// url.Error repeats the unredacted URL
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "jira request", attrs...)
+}
+
+// sensitiveQueryParams are query parameters whose values are redacted from logged URLs
+var sensitiveQueryParams = []string{"token", "access_token", "api_key", "apikey", "password", "jwt"}
+
+// redactURL returns a URL for logging, with any user info and sensitive query
+// parameter values replaced
+func redactURL(raw string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return "(invalid URL)"
+	}
+
+	if u.User != nil {
+		u.User = neturl.User("REDACTED")
+	}
+	query := u.Query()
+	redacted := false
+	for key := range query {
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(key, sensitive) {
+				query.Set(key, "REDACTED")
+				redacted = true
+			}
+		}
+	}
+	if redacted {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
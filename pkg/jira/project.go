@@ -0,0 +1,55 @@
+package jira
+
+import (
+	"fmt"
+	neturl "net/url"
+)
+
+// WithFetchProject looks up each ticket's project, filling in its description,
+// lead and category. Projects are fetched once per client; a project that can't
+// be read (e.g. restricted or missing) keeps only the key, ID and name from the issue.
+func WithFetchProject() ClientOption {
+	return func(c *Client) {
+		c.fetchProject = true
+	}
+}
+
+// GetProject fetches a project by key or ID
+func (c *Client) GetProject(key string) (*Project, error) {
+	url := fmt.Sprintf("%s/rest/api/2/project/%s", c.BaseURL, neturl.PathEscape(key))
+
+	var project Project
+	if err := c.getJSON(url, &project); err != nil {
+		return nil, fmt.Errorf("failed to fetch project %s: %w", key, err)
+	}
+
+	return &project, nil
+}
+
+// populateProject fills in the ticket's project details when enabled. Lookup
+// failures are cached too, so an unreadable project is only requested once, and
+// leave the project as parsed from the issue rather than failing the ticket.
+func (c *Client) populateProject(ticket *Ticket) {
+	if !c.fetchProject || ticket.Project.Key == "" {
+		return
+	}
+
+	c.projectMu.Lock()
+	defer c.projectMu.Unlock()
+
+	project, ok := c.projects[ticket.Project.Key]
+	if !ok {
+		project, _ = c.GetProject(ticket.Project.Key)
+		if c.projects == nil {
+			c.projects = make(map[string]*Project)
+		}
+		c.projects[ticket.Project.Key] = project
+	}
+	if project == nil {
+		return
+	}
+
+	ticket.Project.Description = project.Description
+	ticket.Project.Lead = project.Lead
+	ticket.Project.ProjectCategory = project.ProjectCategory
+}
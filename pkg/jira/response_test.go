@@ -0,0 +1,73 @@
+package jira
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsNonJSONResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        bool
+	}{
+		{name: "json", contentType: "application/json", body: `{"key":"A-1"}`},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", body: `{"key":"A-1"}`},
+		{name: "json with uppercase charset", contentType: "Application/JSON;charset=UTF-8", body: `{"key":"A-1"}`},
+		{name: "json mentioning html", contentType: "application/json", body: `{"description":"<p>text/html</p>"}`},
+		{name: "html", contentType: "text/html", body: "<html><body>Down for maintenance</body></html>", want: true},
+		{name: "html with charset", contentType: "text/html; charset=utf-8", body: "Down for maintenance", want: true},
+		{name: "xhtml", contentType: "application/xhtml+xml", body: "Log in", want: true},
+		{name: "html labelled as json", contentType: "application/json", body: "\n  <!DOCTYPE html>", want: true},
+		{name: "missing content type", body: `{"key":"A-1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNonJSONResponse(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("isNonJSONResponse(%q, %q) = %v, want %v", tt.contentType, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTicketHTMLResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{name: "maintenance page", contentType: "text/html; charset=utf-8"},
+		{name: "login page labelled as json", contentType: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", tt.contentType)
+				io.WriteString(w, "<!DOCTYPE html><html><body>Jira is down for maintenance</body></html>")
+			}))
+			defer server.Close()
+
+			_, err := newRetryTestClient(server).GetTicket("RHEL-1")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusOK || !strings.Contains(apiErr.Message, "non-JSON response") {
+				t.Errorf("GetTicket() error = %v, want a non-JSON APIError", err)
+			}
+			var parseErr *ResponseParseError
+			if errors.As(err, &parseErr) {
+				t.Errorf("GetTicket() error = %v, want it reported as an HTML page rather than invalid JSON", err)
+			}
+			if got := requests.Load(); got != 1 {
+				t.Errorf("requests = %d, want 1 (an HTML page is not retried)", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/backoff"
+)
+
+// DefaultMaxAttempts is the default number of attempts made for each request
+const DefaultMaxAttempts = backoff.DefaultMaxAttempts
+
+// ErrRetryBudgetExhausted is returned once a client's shared retry budget has been used up
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget caps the total number of retries made across all requests sharing it,
+// so a batch run against a struggling server fails fast instead of retrying every
+// request to its own limit
+type RetryBudget struct {
+	mu        sync.Mutex
+	remaining int
+	exhausted bool
+}
+
+// NewRetryBudget creates a retry budget allowing the given number of retries in total
+func NewRetryBudget(retries int) *RetryBudget {
+	if retries < 0 {
+		retries = 0
+	}
+	return &RetryBudget{remaining: retries}
+}
+
+// take consumes one retry, reporting false and marking the budget exhausted when none remain
+func (b *RetryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.remaining == 0 {
+		b.exhausted = true
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// Exhausted reports whether a retry has been refused because the budget ran out
+func (b *RetryBudget) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exhausted
+}
+
+// RetryNotifyFunc is called before each retry with the upcoming attempt number
+// (starting at 2) and the error that caused the previous attempt to fail
+type RetryNotifyFunc func(attempt int, err error)
+
+// WithMaxAttempts sets the maximum number of attempts made for each request.
+// A value of 1 disables retries.
+func WithMaxAttempts(attempts int) ClientOption {
+	return func(c *Client) {
+		if attempts < 1 {
+			attempts = 1
+		}
+		c.retryPolicy.MaxAttempts = attempts
+	}
+}
+
+// WithMaxRetryElapsed stops retrying a request once the next retry would start
+// more than d after its first attempt. Zero means no limit.
+func WithMaxRetryElapsed(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy.MaxElapsed = d
+	}
+}
+
+// WithRetryNotify registers a callback invoked before each retry, allowing
+// callers to report progress without coupling the client to any UI
+func WithRetryNotify(notify RetryNotifyFunc) ClientOption {
+	return func(c *Client) {
+		c.retryNotify = notify
+	}
+}
+
+// WithRetryBudget shares a retry budget with the client. Once the budget is
+// exhausted, requests fail immediately with ErrRetryBudgetExhausted.
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = budget
+	}
+}
+
+// retry runs op under the client's backoff policy and retry budget, calling
+// discard (when set) and the retry notify hook before each retry. Waits between
+// attempts end early when ctx is done. It returns the error wrapped with
+// ErrRetryBudgetExhausted when the budget refused a retry.
+func (c *Client) retry(ctx context.Context, op func(attempt int) error, discard func()) error {
+	if c.retryBudget != nil && c.retryBudget.Exhausted() {
+		return ErrRetryBudgetExhausted
+	}
+
+	policy := c.retryPolicy
+	refused := false
+	if c.retryBudget != nil {
+		policy.Allow = func() bool {
+			refused = !c.retryBudget.take()
+			return !refused
+		}
+	}
+
+	err := backoff.Retry(ctx, policy, op, func(attempt int, err error, delay time.Duration) {
+		if discard != nil {
+			discard()
+		}
+		if c.retryNotify != nil {
+			c.retryNotify(attempt, err)
+		}
+	})
+	if refused {
+		return fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err)
+	}
+	return err
+}
+
+// do executes a request under the client's backoff policy. GET and HEAD requests
+// are retried after network errors and transient server responses; other methods
+// only when the connection failed, so a request Jira may have applied (e.g. a POST
+// whose response was lost) is never sent twice. Gzip-encoded bodies are
+// decompressed. The final response is returned as-is for the caller to handle
+// once attempts are exhausted.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	discard := func() {
+		// Drain the failed response before retrying; the last one is returned open
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			resp = nil
+		}
+	}
+
+	err := c.retry(req.Context(), func(attempt int) error {
+		var err error
+		resp, err = c.send(req, attempt)
+		if err != nil {
+			if isIdempotent(req.Method) || notSent(err) {
+				return backoff.Retryable(err, 0)
+			}
+			return err
+		}
+		if isIdempotent(req.Method) && backoff.RetryableStatus(resp.StatusCode) {
+			statusErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+			return backoff.Retryable(statusErr, backoff.RetryAfter(resp.Header, time.Now()))
+		}
+		return nil
+	}, discard)
+
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return resp, nil
+	case errors.Is(err, ErrRetryBudgetExhausted):
+		discard()
+		return nil, err
+	case resp != nil && errors.As(err, &apiErr):
+		// The policy gave up on a retryable status; the caller reports the response
+		return resp, nil
+	}
+	return nil, err
+}
+
+// doOnce executes a request without retrying, for requests that must not be
+// repeated even when the connection fails, such as uploads
+func (c *Client) doOnce(req *http.Request) (*http.Response, error) {
+	return c.send(req, 1)
+}
+
+// isIdempotent reports whether a request method can safely be sent again after
+// an attempt that may have reached the server
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// notSent reports whether a request failed before reaching the server, because
+// the connection could not be established
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// send makes a single attempt at a request, with a fresh copy of the body for
+// retries, logging it and decompressing a gzip-encoded response
+func (c *Client) send(req *http.Request, attempt int) (*http.Response, error) {
+	attemptReq := req.Clone(req.Context())
+	if attempt > 1 && req.GetBody != nil {
+		// Request bodies are consumed by each attempt, so retries need a fresh copy
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		attemptReq.Body = body
+	}
+
+	started := time.Now()
+	resp, err := c.HTTPClient.Do(attemptReq)
+	c.logRequest(attemptReq, resp, err, attempt, time.Since(started))
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeContentEncoding(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,243 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/backoff"
+)
+
+// newRetryTestClient returns a client for server that retries without waiting
+func newRetryTestClient(server *httptest.Server, opts ...ClientOption) *Client {
+	client := NewClient(append([]ClientOption{WithBaseURL(server.URL)}, opts...)...)
+	client.retryPolicy.BaseDelay = time.Microsecond
+	return client
+}
+
+// statusSequence serves the given statuses in order, repeating the last one,
+// with a JSON body for 200 responses
+func statusSequence(requests *atomic.Int32, statuses ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			io.WriteString(w, `{"name":"ok"}`)
+		}
+	}
+}
+
+func TestGetJSONRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantRequests int32
+		wantNotified int
+	}{
+		{"succeeds after retry", []int{http.StatusServiceUnavailable, http.StatusOK}, false, 2, 1},
+		{"gives up after max attempts", []int{http.StatusBadGateway}, true, DefaultMaxAttempts, DefaultMaxAttempts - 1},
+		{"does not retry client errors", []int{http.StatusForbidden}, true, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(statusSequence(&requests, tt.statuses...))
+			defer server.Close()
+
+			notified := 0
+			client := newRetryTestClient(server, WithRetryNotify(func(attempt int, err error) { notified++ }))
+			var v struct{ Name string }
+			err := client.getJSON(server.URL+"/rest/api/2/myself", &v)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if notified != tt.wantNotified {
+				t.Errorf("notified %d times, want %d", notified, tt.wantNotified)
+			}
+		})
+	}
+}
+
+func TestRetryBudgetExhausted(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(statusSequence(&requests, http.StatusServiceUnavailable))
+	defer server.Close()
+
+	budget := NewRetryBudget(1)
+	client := newRetryTestClient(server, WithRetryBudget(budget))
+	var v struct{ Name string }
+
+	err := client.getJSON(server.URL+"/rest/api/2/myself", &v)
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("getJSON() error = %v, want ErrRetryBudgetExhausted", err)
+	}
+	if apiErr := (*APIError)(nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("getJSON() error = %v, want it to wrap the last 503 response", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2 (one retry from the budget)", got)
+	}
+
+	// Later requests fail immediately
+	if err := client.getJSON(server.URL+"/rest/api/2/myself", &v); !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Errorf("second getJSON() error = %v, want ErrRetryBudgetExhausted", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests after exhaustion = %d, want 2", got)
+	}
+}
+
+func TestRetryBudgetExhaustedKeepsLastError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantTarget error
+	}{
+		{name: "not found", status: http.StatusNotFound, wantTarget: ErrNotFound},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantTarget: ErrUnauthorized},
+		{name: "unavailable", status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithRetryBudget(NewRetryBudget(0)))
+			client.retryPolicy.BaseDelay = time.Microsecond
+			err := client.retry(context.Background(), func(attempt int) error {
+				return backoff.Retryable(&APIError{StatusCode: tt.status, Message: http.StatusText(tt.status)}, 0)
+			}, nil)
+
+			if !errors.Is(err, ErrRetryBudgetExhausted) {
+				t.Fatalf("retry() error = %v, want ErrRetryBudgetExhausted", err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("retry() error = %v, want it to wrap the %d APIError", err, tt.status)
+			}
+			if tt.wantTarget != nil && !errors.Is(err, tt.wantTarget) {
+				t.Errorf("retry() error = %v, want it to match %v", err, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestGetJSONParseRetriesShareAttempts(t *testing.T) {
+	// A 503 followed by truncated JSON every time: transport and parse retries
+	// together stay within one request's attempt limit
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `{"name":"trunc`)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server)
+	var v struct{ Name string }
+	err := client.getJSON(server.URL+"/rest/api/2/myself", &v)
+
+	var parseErr *ResponseParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("getJSON() error = %v, want a ResponseParseError", err)
+	}
+	if got := requests.Load(); got != DefaultMaxAttempts {
+		t.Errorf("requests = %d, want %d", got, DefaultMaxAttempts)
+	}
+}
+
+func TestDoRetriesOnlyUnsentPosts(t *testing.T) {
+	t.Run("transient status is not retried", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(statusSequence(&requests, http.StatusBadGateway, http.StatusCreated))
+		defer server.Close()
+
+		client := newRetryTestClient(server)
+		req, err := client.newRequest("POST", server.URL+"/rest/api/2/issue/A-1/comment", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.do(req)
+		if err != nil {
+			t.Fatalf("do() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadGateway || requests.Load() != 1 {
+			t.Errorf("got status %d after %d requests, want 502 after 1", resp.StatusCode, requests.Load())
+		}
+	})
+
+	t.Run("connection failure is retried", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		notified := 0
+		client := newRetryTestClient(server, WithRetryNotify(func(attempt int, err error) { notified++ }))
+		req, err := client.newRequest("POST", server.URL+"/rest/api/2/issue/A-1/comment", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.do(req); err == nil {
+			t.Fatal("do() succeeded against a closed server")
+		}
+		if notified != DefaultMaxAttempts-1 {
+			t.Errorf("notified %d times, want %d", notified, DefaultMaxAttempts-1)
+		}
+	})
+}
+
+func TestWithContextDeadline(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantErr      bool
+		wantRequests int32
+	}{
+		{name: "no deadline", wantRequests: 2},
+		{name: "deadline cuts the retry wait short", timeout: 50 * time.Millisecond, wantErr: true, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(statusSequence(&requests, http.StatusServiceUnavailable, http.StatusOK))
+			defer server.Close()
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			client := newRetryTestClient(server, WithContext(ctx))
+			client.retryPolicy.BaseDelay = 200 * time.Millisecond
+
+			started := time.Now()
+			var v struct{ Name string }
+			err := client.getJSON(server.URL+"/rest/api/2/myself", &v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if elapsed := time.Since(started); tt.timeout > 0 && elapsed >= client.retryPolicy.BaseDelay {
+				t.Errorf("getJSON() took %v, want the deadline of %v to end the retry wait", elapsed, tt.timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// searchPageSize is the number of issues requested per search page
+	searchPageSize = 50
+
+	// getTicketsChunkSize is the number of keys fetched per search by GetTickets,
+	// keeping the JQL well within URL length limits
+	getTicketsChunkSize = 50
+)
+
+// SearchTickets returns the tickets matching a JQL query, following pagination
+// until maxResults tickets have been collected. A maxResults of zero or less
+// returns every matching ticket. Issues that can't be parsed or fail validation
+// are skipped: the other tickets are returned along with an error joining an
+// InvalidIssueError for each of them.
+func (c *Client) SearchTickets(jql string, maxResults int) ([]*Ticket, error) {
+	query := neturl.Values{}
+	query.Set("jql", jql)
+
+	tickets, skipped, err := c.paginateIssues(fmt.Sprintf("%s/rest/api/2/search", c.BaseURL), query, maxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tickets: %w", err)
+	}
+
+	return tickets, errors.Join(skipped...)
+}
+
+// GetTickets fetches several tickets by ID or key using a "key in (...)" search,
+// chunked so each request stays within URL limits. Tickets are returned in the
+// requested order. Tickets that could not be fetched are omitted, and the returned
+// error joins a TicketNotFoundError, InvalidIssueError or fetch error for each of them.
+func (c *Client) GetTickets(ids []string) ([]*Ticket, error) {
+	byKey := make(map[string]*Ticket)
+	failed := make(map[string]bool)
+	var errs []error
+
+	for start := 0; start < len(ids); start += getTicketsChunkSize {
+		chunk := ids[start:min(start+getTicketsChunkSize, len(ids))]
+
+		tickets, skipped, err := c.searchKeys(chunk)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			// Jira rejects the whole query if any key doesn't exist, so fall back
+			// to fetching the chunk's tickets one by one
+			tickets, skipped, err = nil, nil, nil
+			for _, id := range chunk {
+				ticket, err := c.GetTicket(id)
+				if err != nil {
+					failed[strings.ToUpper(id)] = true
+					errs = append(errs, err)
+					continue
+				}
+				tickets = append(tickets, ticket)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tickets: %w", err)
+		}
+
+		for _, err := range skipped {
+			var invalid *InvalidIssueError
+			if errors.As(err, &invalid) {
+				failed[strings.ToUpper(invalid.TicketID)] = true
+				failed[invalid.ID] = true
+			}
+			errs = append(errs, err)
+		}
+		for _, ticket := range tickets {
+			byKey[strings.ToUpper(ticket.Key)] = ticket
+			byKey[ticket.ID] = ticket
+		}
+	}
+
+	var tickets []*Ticket
+	for _, id := range ids {
+		ticket, ok := byKey[strings.ToUpper(id)]
+		if !ok {
+			if !failed[strings.ToUpper(id)] {
+				errs = append(errs, &TicketNotFoundError{TicketID: id})
+			}
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, errors.Join(errs...)
+}
+
+// searchKeys fetches the tickets with the given keys in a single search,
+// returning the invalid issues it skipped separately
+func (c *Client) searchKeys(keys []string) ([]*Ticket, []error, error) {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = strconv.Quote(key)
+	}
+
+	query := neturl.Values{}
+	query.Set("jql", fmt.Sprintf("key in (%s)", strings.Join(quoted, ",")))
+
+	return c.paginateIssues(fmt.Sprintf("%s/rest/api/2/search", c.BaseURL), query, 0)
+}
+
+// paginateIssues fetches pages of issues from an endpoint returning a SearchResponse
+// (the search API and Agile issue listings) until maxResults tickets have been
+// collected, or every issue if maxResults is zero or less. Issues that can't be
+// parsed or fail validation are left out and returned as InvalidIssueErrors, so
+// one bad issue doesn't lose the rest of the results.
+func (c *Client) paginateIssues(endpoint string, query neturl.Values, maxResults int) ([]*Ticket, []error, error) {
+	var tickets []*Ticket
+	var skipped []error
+
+	for startAt := 0; ; {
+		pageSize := searchPageSize
+		if maxResults > 0 && maxResults-len(tickets) < pageSize {
+			pageSize = maxResults - len(tickets)
+		}
+
+		query.Set("startAt", strconv.Itoa(startAt))
+		query.Set("maxResults", strconv.Itoa(pageSize))
+		if fields := c.requestFields(); len(fields) > 0 {
+			query.Set("fields", strings.Join(fields, ","))
+		}
+
+		var searchResp SearchResponse
+		if err := c.getJSON(endpoint+"?"+query.Encode(), &searchResp); err != nil {
+			return nil, nil, err
+		}
+
+		for i := range searchResp.Issues {
+			issue := &searchResp.Issues[i]
+			ticket, err := c.parseAndValidate(issue)
+			if err != nil {
+				skipped = append(skipped, &InvalidIssueError{TicketID: issue.Key, ID: issue.ID, Err: err})
+				continue
+			}
+			tickets = append(tickets, ticket)
+		}
+
+		startAt += len(searchResp.Issues)
+		if len(searchResp.Issues) == 0 || startAt >= searchResp.Total ||
+			(maxResults > 0 && len(tickets) >= maxResults) {
+			break
+		}
+	}
+
+	return tickets, skipped, nil
+}
+
+// GetChildTickets returns the subtasks of a ticket and, when it is an epic, the
+// issues linked to it through the epic link field, ordered by key. Instances
+// without the epic link field (e.g. Jira Cloud, where epic children are matched
+// by parent) only return the parent matches. Invalid children are skipped and
+// reported as for SearchTickets.
+func (c *Client) GetChildTickets(key string) ([]*Ticket, error) {
+	clauses := []string{fmt.Sprintf("parent = %s", strconv.Quote(key))}
+	if id, ok := strings.CutPrefix(c.epicLinkField, "customfield_"); ok {
+		clauses = append(clauses, fmt.Sprintf("cf[%s] = %s", id, strconv.Quote(key)))
+	}
+
+	seen := make(map[string]bool)
+	var children []*Ticket
+	var skipped []error
+	for i, clause := range clauses {
+		query := neturl.Values{}
+		query.Set("jql", clause+" ORDER BY key ASC")
+
+		tickets, invalid, err := c.paginateIssues(fmt.Sprintf("%s/rest/api/2/search", c.BaseURL), query, 0)
+		if err != nil {
+			var apiErr *APIError
+			if i > 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch children of %s: %w", key, err)
+		}
+		for _, err := range invalid {
+			if key := err.(*InvalidIssueError).TicketID; !seen[key] {
+				seen[key] = true
+				skipped = append(skipped, err)
+			}
+		}
+		for _, ticket := range tickets {
+			if !seen[ticket.Key] {
+				seen[ticket.Key] = true
+				children = append(children, ticket)
+			}
+		}
+	}
+
+	return children, errors.Join(skipped...)
+}
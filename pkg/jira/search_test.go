@@ -0,0 +1,273 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// issueJSON returns a search result issue, with an empty summary for an invalid one
+func issueJSON(key, summary string) map[string]any {
+	return map[string]any{
+		"id":  key + "-id",
+		"key": key,
+		"fields": map[string]any{
+			"summary": summary,
+			"status":  map[string]any{"name": "New"},
+		},
+	}
+}
+
+// searchHandler serves issues as search results, one page of at most
+// searchPageSize issues per request
+func searchHandler(t *testing.T, issues []map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var startAt int
+		json.Unmarshal([]byte(r.URL.Query().Get("startAt")), &startAt)
+		page := issues[min(startAt, len(issues)):min(startAt+searchPageSize, len(issues))]
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"startAt": startAt,
+			"total":   len(issues),
+			"issues":  page,
+		}); err != nil {
+			t.Errorf("failed to encode search results: %v", err)
+		}
+	}
+}
+
+func TestSearchTicketsSkipsInvalidIssues(t *testing.T) {
+	tests := []struct {
+		name        string
+		issues      []map[string]any
+		wantKeys    []string
+		wantSkipped []string
+	}{
+		{
+			name:     "all valid",
+			issues:   []map[string]any{issueJSON("A-1", "One"), issueJSON("A-2", "Two")},
+			wantKeys: []string{"A-1", "A-2"},
+		},
+		{
+			name:        "invalid issue in the middle",
+			issues:      []map[string]any{issueJSON("A-1", "One"), issueJSON("A-2", ""), issueJSON("A-3", "Three")},
+			wantKeys:    []string{"A-1", "A-3"},
+			wantSkipped: []string{"A-2"},
+		},
+		{
+			name:        "every issue invalid",
+			issues:      []map[string]any{issueJSON("A-1", ""), issueJSON("A-2", "")},
+			wantSkipped: []string{"A-1", "A-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(searchHandler(t, tt.issues))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			tickets, err := client.SearchTickets("project = A", 0)
+
+			var keys []string
+			for _, ticket := range tickets {
+				keys = append(keys, ticket.Key)
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("SearchTickets() keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if got := invalidIssueKeys(err); !slices.Equal(got, tt.wantSkipped) {
+				t.Errorf("skipped issues = %v, want %v (error %v)", got, tt.wantSkipped, err)
+			}
+		})
+	}
+}
+
+// invalidIssueKeys returns the keys of the InvalidIssueErrors joined in err
+func invalidIssueKeys(err error) []string {
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	var keys []string
+	for _, err := range errs {
+		var invalid *InvalidIssueError
+		if errors.As(err, &invalid) {
+			keys = append(keys, invalid.TicketID)
+		}
+	}
+	return keys
+}
+
+// keySearchHandler answers "key in (...)" searches from issues by key, recording
+// the keys requested by each search
+func keySearchHandler(t *testing.T, issues map[string]map[string]any, requested *[][]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		list, ok := strings.CutPrefix(jql, "key in (")
+		if !ok {
+			t.Errorf("unexpected JQL %q", jql)
+		}
+
+		var keys []string
+		var found []map[string]any
+		for _, quoted := range strings.Split(strings.TrimSuffix(list, ")"), ",") {
+			key, err := strconv.Unquote(quoted)
+			if err != nil {
+				t.Errorf("invalid key %s in JQL: %v", quoted, err)
+			}
+			keys = append(keys, key)
+			if issue, ok := issues[key]; ok {
+				found = append(found, issue)
+			}
+		}
+		*requested = append(*requested, keys)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"total": len(found), "issues": found})
+	}
+}
+
+func TestGetTickets(t *testing.T) {
+	tests := []struct {
+		name         string
+		ids          int
+		missing      []string
+		invalid      []string
+		wantChunks   []int
+		wantErrCount int
+	}{
+		{name: "single chunk", ids: 3, wantChunks: []int{3}},
+		{name: "split into chunks", ids: 120, wantChunks: []int{50, 50, 20}},
+		{name: "exact chunk size", ids: 50, wantChunks: []int{50}},
+		{name: "missing ticket", ids: 60, missing: []string{"A-7"}, wantChunks: []int{50, 10}, wantErrCount: 1},
+		{name: "invalid ticket keeps its chunk", ids: 60, invalid: []string{"A-3", "A-55"}, wantChunks: []int{50, 10}, wantErrCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := make(map[string]map[string]any)
+			var ids []string
+			for i := tt.ids; i > 0; i-- {
+				key := fmt.Sprintf("A-%d", i)
+				ids = append(ids, key)
+				switch {
+				case slices.Contains(tt.missing, key):
+				case slices.Contains(tt.invalid, key):
+					issues[key] = issueJSON(key, "")
+				default:
+					issues[key] = issueJSON(key, "Summary of "+key)
+				}
+			}
+
+			var requested [][]string
+			server := httptest.NewServer(keySearchHandler(t, issues, &requested))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			tickets, err := client.GetTickets(ids)
+
+			var chunks []int
+			for _, keys := range requested {
+				chunks = append(chunks, len(keys))
+			}
+			if !slices.Equal(chunks, tt.wantChunks) {
+				t.Errorf("chunk sizes = %v, want %v", chunks, tt.wantChunks)
+			}
+
+			var wantKeys []string
+			for _, id := range ids {
+				if !slices.Contains(tt.missing, id) && !slices.Contains(tt.invalid, id) {
+					wantKeys = append(wantKeys, id)
+				}
+			}
+			var keys []string
+			for _, ticket := range tickets {
+				keys = append(keys, ticket.Key)
+			}
+			if !slices.Equal(keys, wantKeys) {
+				t.Errorf("GetTickets() keys = %v, want %v in the requested order", keys, wantKeys)
+			}
+
+			var errCount int
+			if err != nil {
+				errCount = len(err.(interface{ Unwrap() []error }).Unwrap())
+			}
+			if errCount != tt.wantErrCount {
+				t.Errorf("GetTickets() error = %v, want %d errors", err, tt.wantErrCount)
+			}
+			if got := invalidIssueKeys(err); len(got) != len(tt.invalid) {
+				t.Errorf("invalid issues = %v, want %v", got, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestGetChildTicketsSkipsInvalidChildren(t *testing.T) {
+	tests := []struct {
+		name          string
+		epicLinkField string
+		subtasks      []map[string]any
+		epicChildren  []map[string]any
+		wantKeys      []string
+		wantSkipped   []string
+	}{
+		{
+			name:     "subtasks",
+			subtasks: []map[string]any{issueJSON("A-2", "Two"), issueJSON("A-3", "Three")},
+			wantKeys: []string{"A-2", "A-3"},
+		},
+		{
+			name:        "invalid subtask",
+			subtasks:    []map[string]any{issueJSON("A-2", ""), issueJSON("A-3", "Three")},
+			wantKeys:    []string{"A-3"},
+			wantSkipped: []string{"A-2"},
+		},
+		{
+			name:          "invalid epic child listed once",
+			epicLinkField: "customfield_10014",
+			subtasks:      []map[string]any{issueJSON("A-2", "")},
+			epicChildren:  []map[string]any{issueJSON("A-2", ""), issueJSON("A-4", "Four")},
+			wantKeys:      []string{"A-4"},
+			wantSkipped:   []string{"A-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issues := tt.subtasks
+				if strings.HasPrefix(r.URL.Query().Get("jql"), "cf[") {
+					issues = tt.epicChildren
+				}
+				searchHandler(t, issues)(w, r)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithEpicLinkField(tt.epicLinkField))
+			children, err := client.GetChildTickets("A-1")
+
+			var keys []string
+			for _, ticket := range children {
+				keys = append(keys, ticket.Key)
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("GetChildTickets() keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if got := invalidIssueKeys(err); !slices.Equal(got, tt.wantSkipped) {
+				t.Errorf("skipped children = %v, want %v (error %v)", got, tt.wantSkipped, err)
+			}
+		})
+	}
+}
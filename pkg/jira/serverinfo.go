@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Deployment types reported by /rest/api/2/serverInfo
+const (
+	DeploymentCloud  = "Cloud"
+	DeploymentServer = "Server"
+)
+
+// ServerInfo describes a Jira instance, as returned by /rest/api/2/serverInfo
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	DeploymentType string `json:"deploymentType"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// IsCloud reports whether the instance is Jira Cloud
+func (s *ServerInfo) IsCloud() bool {
+	return strings.EqualFold(s.DeploymentType, DeploymentCloud)
+}
+
+// serverInfoCache holds server info per base URL, shared by all clients
+var serverInfoCache = struct {
+	sync.Mutex
+	byURL map[string]*ServerInfo
+}{byURL: make(map[string]*ServerInfo)}
+
+// GetServerInfo returns the server info for the client's base URL. The request is
+// made anonymously, so it works even when the configured credentials are rejected.
+// Results are cached per base URL, so the instance is only probed once per process.
+func (c *Client) GetServerInfo() (*ServerInfo, error) {
+	serverInfoCache.Lock()
+	info, ok := serverInfoCache.byURL[c.BaseURL]
+	serverInfoCache.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	req, err := c.newRequest("GET", fmt.Sprintf("%s/rest/api/2/serverInfo", c.BaseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Del("Authorization")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server info: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get server info: %w", newAPIError(resp.StatusCode, body))
+	}
+
+	info = &ServerInfo{}
+	if err := json.Unmarshal(body, info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal server info: %w", err)
+	}
+
+	serverInfoCache.Lock()
+	serverInfoCache.byURL[c.BaseURL] = info
+	serverInfoCache.Unlock()
+
+	return info, nil
+}
+
+// apiError creates an APIError for a failed response, adding an authentication
+// hint to 401 responses
+func (c *Client) apiError(statusCode int, body []byte) *APIError {
+	apiErr := newAPIError(statusCode, body)
+	if statusCode == http.StatusUnauthorized {
+		apiErr.Hint = c.authHint()
+	}
+	return apiErr
+}
+
+// authHint suggests the likely correct authentication mode after a 401, based
+// on the instance's deployment type. It returns an empty string when there is
+// nothing more specific to suggest or the instance could not be probed.
+func (c *Client) authHint() string {
+	if c.token == "" {
+		return ""
+	}
+
+	info, err := c.GetServerInfo()
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case info.IsCloud() && c.email == "":
+		return "this looks like a Jira Cloud instance, which does not accept Bearer personal access tokens; " +
+			"give your account email (--jira-email or JIRA_EMAIL) and use an Atlassian API token as the token"
+	case !info.IsCloud() && c.email != "":
+		return "this looks like a Jira Server or Data Center instance; " +
+			"drop the account email (--jira-email or JIRA_EMAIL) to send the token as a Bearer personal access token"
+	}
+	return ""
+}
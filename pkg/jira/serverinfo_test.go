@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewRequestAuthentication(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ClientOption
+		wantAuth string
+	}{
+		{name: "anonymous"},
+		{name: "personal access token", opts: []ClientOption{WithToken("pat")}, wantAuth: "Bearer pat"},
+		{name: "email and API token", opts: []ClientOption{WithBasicAuth("me@example.com", "api-token")}, wantAuth: "Basic bWVAZXhhbXBsZS5jb206YXBpLXRva2Vu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := NewClient(tt.opts...).newRequest("GET", "https://jira.example.com/rest/api/2/myself", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestAuthHint(t *testing.T) {
+	tests := []struct {
+		name           string
+		deploymentType string
+		opts           []ClientOption
+		wantHint       string
+	}{
+		{name: "cloud with a personal access token", deploymentType: DeploymentCloud, opts: []ClientOption{WithToken("pat")}, wantHint: "--jira-email"},
+		{name: "cloud with an email", deploymentType: DeploymentCloud, opts: []ClientOption{WithBasicAuth("me@example.com", "api-token")}},
+		{name: "server with a personal access token", deploymentType: DeploymentServer, opts: []ClientOption{WithToken("pat")}},
+		{name: "server with an email", deploymentType: DeploymentServer, opts: []ClientOption{WithBasicAuth("me@example.com", "pat")}, wantHint: "Bearer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/rest/api/2/serverInfo" {
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprintf(w, `{"deploymentType":%q}`, tt.deploymentType)
+					return
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+				io.WriteString(w, `{"errorMessages":["You are not authenticated"]}`)
+			}))
+			defer server.Close()
+
+			client := NewClient(append([]ClientOption{WithBaseURL(server.URL)}, tt.opts...)...)
+			_, err := client.GetTicket("A-1")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetTicket() error = %v, want an APIError", err)
+			}
+			if tt.wantHint == "" && apiErr.Hint != "" {
+				t.Errorf("Hint = %q, want none", apiErr.Hint)
+			}
+			if tt.wantHint != "" && !strings.Contains(apiErr.Hint, tt.wantHint) {
+				t.Errorf("Hint = %q, want it to mention %q", apiErr.Hint, tt.wantHint)
+			}
+		})
+	}
+}
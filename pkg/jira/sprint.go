@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSprintField is the custom field that holds sprint information on most instances
+const DefaultSprintField = "customfield_10020"
+
+// Sprint represents a Jira Agile sprint
+type Sprint struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+}
+
+// IsActive reports whether the sprint is currently active
+func (s *Sprint) IsActive() bool {
+	return strings.EqualFold(s.State, "active")
+}
+
+// ActiveSprint returns the ticket's active sprint, or nil if it is not in one
+func (t *Ticket) ActiveSprint() *Sprint {
+	for i := range t.Sprints {
+		if t.Sprints[i].IsActive() {
+			return &t.Sprints[i]
+		}
+	}
+	return nil
+}
+
+// WithSprintField sets the custom field used to read sprint information
+func WithSprintField(field string) ClientOption {
+	return func(c *Client) {
+		c.sprintField = field
+	}
+}
+
+// serverSprintKeyPattern matches the "key=" markers in Jira Server's serialized sprint strings
+var serverSprintKeyPattern = regexp.MustCompile(`(?:^|,)(\w+)=`)
+
+// parseSprints parses a sprint custom field value. Jira Cloud returns an array of
+// sprint objects, while Jira Server returns an array of serialized strings like
+// "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=123,state=ACTIVE,name=Sprint 5,...]"
+func parseSprints(value interface{}) []Sprint {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var sprints []Sprint
+	for _, item := range items {
+		switch v := item.(type) {
+		case map[string]interface{}:
+			sprints = append(sprints, parseCloudSprint(v))
+		case string:
+			if sprint, ok := parseServerSprint(v); ok {
+				sprints = append(sprints, sprint)
+			}
+		}
+	}
+
+	return sprints
+}
+
+// parseCloudSprint parses a structured sprint object as returned by Jira Cloud
+func parseCloudSprint(m map[string]interface{}) Sprint {
+	sprint := Sprint{
+		Name:      getStringFromMap(m, "name"),
+		State:     getStringFromMap(m, "state"),
+		StartDate: parseSprintDate(getStringFromMap(m, "startDate")),
+		EndDate:   parseSprintDate(getStringFromMap(m, "endDate")),
+	}
+
+	if id, ok := m["id"].(float64); ok {
+		sprint.ID = int(id)
+	}
+
+	return sprint
+}
+
+// parseServerSprint parses the serialized sprint string returned by Jira Server
+func parseServerSprint(s string) (Sprint, bool) {
+	start := strings.Index(s, "[")
+	end := strings.LastIndex(s, "]")
+	if start < 0 || end <= start {
+		return Sprint{}, false
+	}
+	body := s[start+1 : end]
+
+	values := make(map[string]string)
+	matches := serverSprintKeyPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, match := range matches {
+		valueEnd := len(body)
+		if i+1 < len(matches) {
+			valueEnd = matches[i+1][0]
+		}
+
+		value := body[match[1]:valueEnd]
+		if value == "<null>" {
+			value = ""
+		}
+		values[body[match[2]:match[3]]] = value
+	}
+
+	sprint := Sprint{
+		Name:      values["name"],
+		State:     values["state"],
+		StartDate: parseSprintDate(values["startDate"]),
+		EndDate:   parseSprintDate(values["endDate"]),
+	}
+
+	if id, err := strconv.Atoi(values["id"]); err == nil {
+		sprint.ID = id
+	}
+
+	return sprint, sprint.Name != "" || sprint.ID != 0
+}
+
+// parseSprintDate parses sprint dates, which are ISO 8601 timestamps in both formats
+func parseSprintDate(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
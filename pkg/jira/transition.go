@@ -0,0 +1,121 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Transition is a workflow transition available from a ticket's current status
+type Transition struct {
+	ID   string
+	Name string
+
+	// To is the status the ticket moves to
+	To Status
+}
+
+// transitionsResponse is the issue transitions API response
+type transitionsResponse struct {
+	Transitions []map[string]interface{} `json:"transitions"`
+}
+
+// transitionRequest is the body of a request making a transition
+type transitionRequest struct {
+	Transition struct {
+		ID string `json:"id"`
+	} `json:"transition"`
+}
+
+// GetTransitions fetches the transitions available from a ticket's current status
+func (c *Client) GetTransitions(ticketID string) ([]Transition, error) {
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", c.BaseURL, ticketID)
+	var resp transitionsResponse
+	if err := c.getJSON(url, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch transitions for %s: %w", ticketID, err)
+	}
+
+	transitions := make([]Transition, 0, len(resp.Transitions))
+	for _, raw := range resp.Transitions {
+		transition := Transition{
+			ID:   getStringFromMap(raw, "id"),
+			Name: getStringFromMap(raw, "name"),
+		}
+		if to, ok := raw["to"].(map[string]interface{}); ok {
+			transition.To = parseStatus(to)
+		}
+		transitions = append(transitions, transition)
+	}
+	return transitions, nil
+}
+
+// findTransition returns the transition named name, ignoring case, falling back
+// to one leading to a status of that name, since workflows often name the
+// transition to "In Progress" something like "Start Progress"
+func findTransition(transitions []Transition, name string) (Transition, bool) {
+	name = strings.TrimSpace(name)
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.Name, name) {
+			return transition, true
+		}
+	}
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.To.Name, name) {
+			return transition, true
+		}
+	}
+	return Transition{}, false
+}
+
+// TransitionTicket moves a ticket through the transition named transitionName
+// (or leading to the status of that name). It returns a
+// TransitionUnavailableError if no such transition is available from the
+// ticket's current status. Transitions require an authentication token.
+func (c *Client) TransitionTicket(ticketID, transitionName string) error {
+	if c.token == "" {
+		return fmt.Errorf("transitioning tickets requires an authentication token")
+	}
+
+	transitions, err := c.GetTransitions(ticketID)
+	if err != nil {
+		return err
+	}
+	transition, ok := findTransition(transitions, transitionName)
+	if !ok {
+		available := make([]string, 0, len(transitions))
+		for _, t := range transitions {
+			available = append(available, t.Name)
+		}
+		return &TransitionUnavailableError{TicketID: ticketID, Transition: transitionName, Available: available}
+	}
+
+	var body transitionRequest
+	body.Transition.ID = transition.ID
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode transition: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", c.BaseURL, ticketID)
+	req, err := c.newRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	// Resending a transition that was applied would fail as unavailable from the
+	// new status, so it is never retried
+	resp, err := c.doOnce(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return c.apiError(resp.StatusCode, respBody)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// transitionsJSON lists the transitions available from "To Do"
+const transitionsJSON = `{"transitions":[
+	{"id":"11","name":"Start Progress","to":{"id":"3","name":"In Progress","statusCategory":{"key":"indeterminate"}}},
+	{"id":"21","name":"Close Issue","to":{"id":"6","name":"Closed","statusCategory":{"key":"done"}}}
+]}`
+
+func TestTransitionTicket(t *testing.T) {
+	tests := []struct {
+		name       string
+		transition string
+		postStatus int
+		wantID     string
+		wantErr    bool
+	}{
+		{name: "by transition name", transition: "Start Progress", postStatus: http.StatusNoContent, wantID: "11"},
+		{name: "by target status ignoring case", transition: "in progress", postStatus: http.StatusNoContent, wantID: "11"},
+		{name: "unavailable", transition: "Reopen", wantErr: true},
+		{name: "rejected", transition: "Close Issue", postStatus: http.StatusBadGateway, wantID: "21", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/rest/api/2/issue/A-1/transitions" {
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+				if r.Method == http.MethodGet {
+					w.Header().Set("Content-Type", "application/json")
+					io.WriteString(w, transitionsJSON)
+					return
+				}
+
+				var body transitionRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("invalid transition body: %v", err)
+				}
+				posted = append(posted, body.Transition.ID)
+				w.WriteHeader(tt.postStatus)
+			}))
+			defer server.Close()
+
+			client := newRetryTestClient(server, WithToken("token"))
+			err := client.TransitionTicket("A-1", tt.transition)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TransitionTicket() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantID == "" {
+				var unavailable *TransitionUnavailableError
+				if !errors.As(err, &unavailable) || len(unavailable.Available) != 2 {
+					t.Errorf("error = %v, want a TransitionUnavailableError listing 2 transitions", err)
+				}
+				if len(posted) != 0 {
+					t.Errorf("posted %v for an unavailable transition", posted)
+				}
+				return
+			}
+			if len(posted) != 1 || posted[0] != tt.wantID {
+				t.Errorf("posted transitions %v, want exactly [%s]", posted, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestTransitionTicketRequiresToken(t *testing.T) {
+	client := NewClient(WithBaseURL("http://jira.invalid"))
+	if err := client.TransitionTicket("A-1", "In Progress"); err == nil {
+		t.Error("TransitionTicket() succeeded without a token")
+	}
+}
@@ -0,0 +1,54 @@
+package jira
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConns is the number of idle keep-alive connections kept per host,
+	// so batch runs reuse connections instead of reconnecting for each request
+	DefaultMaxIdleConns = 10
+
+	// DefaultIdleConnTimeout is how long an idle keep-alive connection is kept open
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// WithMaxIdleConns sets how many idle keep-alive connections to the Jira host are
+// kept for reuse. It has no effect on a client passed to WithHTTPClient.
+func WithMaxIdleConns(conns int) ClientOption {
+	return func(c *Client) {
+		c.maxIdleConns = conns
+	}
+}
+
+// WithIdleConnTimeout sets how long idle keep-alive connections are kept open.
+// It has no effect on a client passed to WithHTTPClient.
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.idleConnTimeout = timeout
+	}
+}
+
+// newTransport creates the client's own transport, based on the default transport
+// so proxy settings from the environment are still honored
+func newTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// configureTransport applies the connection pool settings to the client's own
+// transport, leaving transports from WithHTTPClient untouched
+func (c *Client) configureTransport() {
+	if c.transport == nil || c.HTTPClient.Transport != c.transport {
+		return
+	}
+	c.transport.MaxIdleConns = c.maxIdleConns
+	c.transport.MaxIdleConnsPerHost = c.maxIdleConns
+	c.transport.IdleConnTimeout = c.idleConnTimeout
+}
+
+// Close releases the client's idle keep-alive connections. The client remains
+// usable afterwards; new requests open new connections.
+func (c *Client) Close() {
+	c.HTTPClient.CloseIdleConnections()
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConnectionReuse(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(issueJSON("RHEL-1", "Summary"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	for range 2 {
+		if _, err := client.GetTicket("RHEL-1"); err != nil {
+			t.Fatalf("GetTicket() error = %v", err)
+		}
+	}
+
+	if got := conns.Load(); got != 1 {
+		t.Errorf("connections = %d, want 1 (the second request reuses the first connection)", got)
+	}
+}
+
+// roundTripFunc adapts a function to an http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var requests []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.URL.String())
+		body, _ := json.Marshal(issueJSON("RHEL-1", "From the custom transport"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	httpClient := &http.Client{Transport: transport}
+
+	client := NewClient(WithBaseURL("https://jira.example.com"), WithHTTPClient(httpClient), WithTimeout(5*time.Second), WithMaxIdleConns(1))
+	ticket, err := client.GetTicket("RHEL-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if ticket.Summary != "From the custom transport" {
+		t.Errorf("Summary = %q, want the custom transport's response", ticket.Summary)
+	}
+	if len(requests) != 1 || !strings.HasPrefix(requests[0], "https://jira.example.com/rest/api/2/issue/RHEL-1") {
+		t.Errorf("requests = %q, want one GetTicket request through the custom transport", requests)
+	}
+
+	// The caller's client is left untouched
+	if httpClient.Timeout != 0 {
+		t.Errorf("caller's client Timeout = %s, want it unchanged", httpClient.Timeout)
+	}
+}
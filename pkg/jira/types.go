@@ -1,31 +1,121 @@
 package jira
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Ticket represents a Jira ticket with essential fields
 type Ticket struct {
 	ID          string    `json:"id"`
 	Key         string    `json:"key"`
+	URL         string    `json:"url,omitempty"`
 	Summary     string    `json:"summary"`
 	Description string    `json:"description"`
+	Environment string    `json:"environment"`
 	Status      Status    `json:"status"`
 	IssueType   IssueType `json:"issuetype"`
 	Priority    Priority  `json:"priority"`
 	Assignee    *User     `json:"assignee"`
-	Reporter    User      `json:"reporter"`
+	Reporter    *User     `json:"reporter"`
 	Created     time.Time `json:"created"`
 	Updated     time.Time `json:"updated"`
+	DueDate     time.Time `json:"duedate"`
 	Labels      []string  `json:"labels"`
 	Components  []Component `json:"components"`
 	Project     Project   `json:"project"`
+	Sprints     []Sprint  `json:"sprints"`
+	Epic        string    `json:"epic"`
+
+	// AffectsVersions are the versions a problem was found in, and FixVersions the
+	// versions it is planned to be fixed in
+	AffectsVersions []Version `json:"versions,omitempty"`
+	FixVersions     []Version `json:"fixVersions,omitempty"`
+
+	// Votes is the number of users who voted for the ticket, and HasVoted whether
+	// the authenticated user is one of them. Both are zero when voting is disabled.
+	Votes    int  `json:"votes"`
+	HasVoted bool `json:"hasVoted"`
+
+	// SecurityLevel restricts who can see the ticket; nil for unrestricted tickets
+	SecurityLevel *SecurityLevel `json:"security,omitempty"`
+
+	// CustomFields holds the values of fields requested with WithCustomFields,
+	// flattened to text and keyed by field ID, and CustomFieldNames their display
+	// names when the instance's field list could be read
+	CustomFields     map[string]string `json:"customFields,omitempty"`
+	CustomFieldNames map[string]string `json:"customFieldNames,omitempty"`
 }
 
-// Status represents the status of a Jira ticket
-type Status struct {
+// CustomFieldName returns the display name of a custom field, or its ID when
+// the name is unknown
+func (t *Ticket) CustomFieldName(id string) string {
+	if name := t.CustomFieldNames[id]; name != "" {
+		return name
+	}
+	return id
+}
+
+// Validate checks that the ticket has the fields required to generate a plan
+func (t *Ticket) Validate() error {
+	if t.Summary == "" {
+		return &ValidationError{TicketID: t.Key, Field: "summary"}
+	}
+	return nil
+}
+
+// IsRegression reports whether the ticket looks like a regression: it has a
+// "regression" label (ignoring case), or both affects and fix versions, meaning
+// a problem found in a released version is being fixed in a later one
+func (t *Ticket) IsRegression() bool {
+	for _, label := range t.Labels {
+		if strings.EqualFold(label, "regression") {
+			return true
+		}
+	}
+	return len(t.AffectsVersions) > 0 && len(t.FixVersions) > 0
+}
+
+// Version represents a project version, as listed in affects and fix versions
+type Version struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Released bool   `json:"released"`
+}
+
+// SecurityLevel represents an issue security level restricting a ticket's visibility
+type SecurityLevel struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
+// Status category keys, which group workflow statuses regardless of their names
+const (
+	StatusCategoryNew        = "new"
+	StatusCategoryInProgress = "indeterminate"
+	StatusCategoryDone       = "done"
+)
+
+// Status represents the status of a Jira ticket. Category is the key of the
+// status category (new, indeterminate or done), which is reliable across workflows
+// where names such as "Closed", "Resolved" or "Verified" vary.
+type Status struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+}
+
+// IsClosed reports whether the ticket's status is in the done category
+func (t *Ticket) IsClosed() bool {
+	return strings.EqualFold(t.Status.Category, StatusCategoryDone)
+}
+
+// IsOpen reports whether the ticket's status is not in the done category. Tickets
+// whose category wasn't returned are treated as open.
+func (t *Ticket) IsOpen() bool {
+	return !t.IsClosed()
+}
+
 // IssueType represents the type of a Jira issue
 type IssueType struct {
 	ID   string `json:"id"`
@@ -41,8 +131,10 @@ type Priority struct {
 // User represents a Jira user
 type User struct {
 	AccountID   string `json:"accountId"`
+	Name        string `json:"name"` // Username on Server and Data Center
 	DisplayName string `json:"displayName"`
 	EmailAddress string `json:"emailAddress"`
+	TimeZone    string `json:"timeZone"`
 }
 
 // Component represents a Jira project component
@@ -54,11 +146,22 @@ type Component struct {
 	Self        string `json:"self"`
 }
 
-// Project represents a Jira project
+// Project represents a Jira project. Description, Lead and ProjectCategory are
+// only populated with WithFetchProject.
 type Project struct {
-	ID   string `json:"id"`
-	Key  string `json:"key"`
-	Name string `json:"name"`
+	ID              string           `json:"id"`
+	Key             string           `json:"key"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Lead            *User            `json:"lead"`
+	ProjectCategory *ProjectCategory `json:"projectCategory"`
+}
+
+// ProjectCategory represents the category a Jira project is grouped under
+type ProjectCategory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // JiraResponse wraps the API response from Jira
@@ -66,4 +169,12 @@ type JiraResponse struct {
 	ID     string                 `json:"id"`
 	Key    string                 `json:"key"`
 	Fields map[string]interface{} `json:"fields"`
-}
\ No newline at end of file
+}
+
+// SearchResponse wraps the search API response from Jira
+type SearchResponse struct {
+	StartAt    int            `json:"startAt"`
+	MaxResults int            `json:"maxResults"`
+	Total      int            `json:"total"`
+	Issues     []JiraResponse `json:"issues"`
+}
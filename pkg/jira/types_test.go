@@ -0,0 +1,400 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       map[string]interface{}
+		wantCategory string
+		wantClosed   bool
+	}{
+		{
+			name:         "to do",
+			status:       map[string]interface{}{"id": "1", "name": "To Do", "statusCategory": map[string]interface{}{"key": "new"}},
+			wantCategory: StatusCategoryNew,
+		},
+		{
+			name:         "in progress",
+			status:       map[string]interface{}{"id": "3", "name": "In Progress", "statusCategory": map[string]interface{}{"key": "indeterminate"}},
+			wantCategory: StatusCategoryInProgress,
+		},
+		{
+			name:         "done named verified",
+			status:       map[string]interface{}{"id": "10", "name": "Verified", "statusCategory": map[string]interface{}{"key": "done"}},
+			wantCategory: StatusCategoryDone,
+			wantClosed:   true,
+		},
+		{
+			name:         "done named closed",
+			status:       map[string]interface{}{"id": "6", "name": "Closed", "statusCategory": map[string]interface{}{"key": "done"}},
+			wantCategory: StatusCategoryDone,
+			wantClosed:   true,
+		},
+		{
+			name:   "closed name without a category",
+			status: map[string]interface{}{"id": "6", "name": "Closed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := &Ticket{Status: parseStatus(tt.status)}
+			if ticket.Status.Category != tt.wantCategory {
+				t.Errorf("Category = %q, want %q", ticket.Status.Category, tt.wantCategory)
+			}
+			if got := ticket.IsClosed(); got != tt.wantClosed {
+				t.Errorf("IsClosed() = %v, want %v", got, tt.wantClosed)
+			}
+			if got := ticket.IsOpen(); got == tt.wantClosed {
+				t.Errorf("IsOpen() = %v, want %v", got, !tt.wantClosed)
+			}
+		})
+	}
+}
+
+func TestIsRegression(t *testing.T) {
+	released := []Version{{ID: "1", Name: "9.4", Released: true}}
+	upcoming := []Version{{ID: "2", Name: "9.5"}}
+
+	tests := []struct {
+		name   string
+		ticket Ticket
+		want   bool
+	}{
+		{name: "affects and fix versions", ticket: Ticket{AffectsVersions: released, FixVersions: upcoming}, want: true},
+		{name: "regression label", ticket: Ticket{Labels: []string{"export", "Regression"}}, want: true},
+		{name: "affects version only", ticket: Ticket{AffectsVersions: released}},
+		{name: "fix version only", ticket: Ticket{FixVersions: upcoming}},
+		{name: "label containing regression", ticket: Ticket{Labels: []string{"regression-tests"}}},
+		{name: "no versions or labels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ticket.IsRegression(); got != tt.want {
+				t.Errorf("IsRegression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		reporter any
+		omit     bool
+		wantNil  bool
+		wantName string
+	}{
+		{name: "reporter", reporter: map[string]any{"name": "rreporter", "displayName": "Riley Reporter"}, wantName: "Riley Reporter"},
+		{name: "null reporter", reporter: nil, wantNil: true, wantName: "Unknown"},
+		{name: "absent reporter", omit: true, wantNil: true, wantName: "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				if !tt.omit {
+					issue["fields"].(map[string]any)["reporter"] = tt.reporter
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if (ticket.Reporter == nil) != tt.wantNil {
+				t.Errorf("Reporter = %+v, want nil %v", ticket.Reporter, tt.wantNil)
+			}
+			if got := ticket.ReporterName(); got != tt.wantName {
+				t.Errorf("ReporterName() = %q, want %q", got, tt.wantName)
+			}
+			if want := "Reporter: " + tt.wantName + "\n"; !strings.Contains(ticket.MarshalForPrompt(), want) {
+				t.Errorf("MarshalForPrompt() doesn't contain %q", want)
+			}
+		})
+	}
+}
+
+func TestTicketValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		ticket    Ticket
+		wantField string
+	}{
+		{name: "valid", ticket: Ticket{Key: "A-1", Summary: "Summary"}},
+		{name: "empty summary", ticket: Ticket{Key: "A-1"}, wantField: "summary"},
+		{name: "empty key and summary", ticket: Ticket{}, wantField: "summary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ticket.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want ValidationError", err)
+			}
+			if validationErr.Field != tt.wantField || validationErr.TicketID != tt.ticket.Key {
+				t.Errorf("ValidationError = %+v, want field %q of %q", validationErr, tt.wantField, tt.ticket.Key)
+			}
+		})
+	}
+}
+
+func TestGetTicketValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(issueJSON("A-1", ""))
+	}))
+	defer server.Close()
+
+	var validationErr *ValidationError
+	if _, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1"); !errors.As(err, &validationErr) {
+		t.Errorf("GetTicket() error = %v, want ValidationError for an empty summary", err)
+	}
+	if _, err := NewClient(WithBaseURL(server.URL), WithoutValidation()).GetTicket("A-1"); err != nil {
+		t.Errorf("GetTicket() with WithoutValidation error = %v", err)
+	}
+}
+
+func TestParseSprintFormats(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		sprint any
+		want   *Sprint
+	}{
+		{
+			name: "cloud object",
+			sprint: map[string]any{
+				"id": 123, "name": "Sprint 5", "state": "active",
+				"startDate": "2025-01-06T09:00:00.000Z", "endDate": "2025-01-20T09:00:00.000Z",
+			},
+			want: &Sprint{ID: 123, Name: "Sprint 5", State: "active", StartDate: start, EndDate: start.AddDate(0, 0, 14)},
+		},
+		{
+			name:   "server legacy string",
+			sprint: "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=123,rapidViewId=7,state=ACTIVE,name=Sprint 5, Team A,startDate=2025-01-06T09:00:00.000Z,endDate=<null>,sequence=123]",
+			want:   &Sprint{ID: 123, Name: "Sprint 5, Team A", State: "ACTIVE", StartDate: start},
+		},
+		{
+			name:   "closed sprint",
+			sprint: "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=99,state=CLOSED,name=Sprint 4]",
+		},
+		{
+			name:   "unparseable string",
+			sprint: "not a sprint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				issue["fields"].(map[string]any)[DefaultSprintField] = []any{tt.sprint}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+
+			got := ticket.ActiveSprint()
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("ActiveSprint() = %+v, want %+v", got, tt.want)
+			}
+			if got == nil {
+				return
+			}
+			if got.ID != tt.want.ID || got.Name != tt.want.Name || got.State != tt.want.State ||
+				!got.StartDate.Equal(tt.want.StartDate) || !got.EndDate.Equal(tt.want.EndDate) {
+				t.Errorf("ActiveSprint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels any
+		want   []string
+	}{
+		{name: "array", labels: []any{"regression", "export"}, want: []string{"regression", "export"}},
+		{name: "comma string", labels: "regression, export,,  ", want: []string{"regression", "export"}},
+		{name: "empty string", labels: ""},
+		{name: "null", labels: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				issue["fields"].(map[string]any)["labels"] = tt.labels
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if !slices.Equal(ticket.Labels, tt.want) {
+				t.Errorf("Labels = %q, want %q", ticket.Labels, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseServerUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issue := issueJSON("A-1", "Summary")
+		fields := issue["fields"].(map[string]any)
+		// Server and Data Center identify users by name and omit accountId
+		fields["assignee"] = map[string]any{"name": "aassignee", "displayName": "Alex Assignee", "emailAddress": "alex@example.com"}
+		fields["reporter"] = map[string]any{"name": "rreporter", "displayName": "Riley Reporter"}
+		fields["components"] = []any{
+			map[string]any{"name": "kernel", "lead": map[string]any{"name": "llead", "displayName": "Lee Lead"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(issue)
+	}))
+	defer server.Close()
+
+	ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if len(ticket.Components) != 1 || ticket.Components[0].Lead == nil {
+		t.Fatalf("Components = %+v, want one component with a lead", ticket.Components)
+	}
+
+	tests := []struct {
+		name        string
+		user        *User
+		wantName    string
+		wantMention string
+	}{
+		{name: "assignee", user: ticket.Assignee, wantName: "aassignee", wantMention: "[~aassignee]"},
+		{name: "reporter", user: ticket.Reporter, wantName: "rreporter", wantMention: "[~rreporter]"},
+		{name: "component lead", user: ticket.Components[0].Lead, wantName: "llead", wantMention: "[~llead]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.user == nil {
+				t.Fatal("user = nil")
+			}
+			if tt.user.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", tt.user.Name, tt.wantName)
+			}
+			if tt.user.AccountID != "" {
+				t.Errorf("AccountID = %q, want empty", tt.user.AccountID)
+			}
+			if got := tt.user.Mention(); got != tt.wantMention {
+				t.Errorf("Mention() = %q, want %q", got, tt.wantMention)
+			}
+		})
+	}
+}
+
+func TestParseVotes(t *testing.T) {
+	tests := []struct {
+		name         string
+		votes        any
+		omit         bool
+		wantVotes    int
+		wantHasVoted bool
+	}{
+		{name: "voted", votes: map[string]any{"self": "https://issues.example.com/rest/api/2/issue/A-1/votes", "votes": 3, "hasVoted": true}, wantVotes: 3, wantHasVoted: true},
+		{name: "not voted", votes: map[string]any{"votes": 1, "hasVoted": false}, wantVotes: 1},
+		{name: "no votes", votes: map[string]any{"votes": 0, "hasVoted": false}},
+		{name: "null", votes: nil},
+		{name: "absent", omit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				if !tt.omit {
+					issue["fields"].(map[string]any)["votes"] = tt.votes
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if ticket.Votes != tt.wantVotes || ticket.HasVoted != tt.wantHasVoted {
+				t.Errorf("Votes, HasVoted = %d, %v; want %d, %v", ticket.Votes, ticket.HasVoted, tt.wantVotes, tt.wantHasVoted)
+			}
+			if got := strings.Contains(ticket.MarshalForPrompt(), "Votes: "); got != (tt.wantVotes > 0) {
+				t.Errorf("MarshalForPrompt() has a Votes line = %v, want %v", got, tt.wantVotes > 0)
+			}
+		})
+	}
+}
+
+func TestParseSecurityLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		security any
+		omit     bool
+		want     *SecurityLevel
+	}{
+		{name: "restricted", security: map[string]any{"id": "10100", "name": "Red Hat Employee", "description": "Only Red Hat employees"}, want: &SecurityLevel{ID: "10100", Name: "Red Hat Employee"}},
+		{name: "null", security: nil},
+		{name: "absent", omit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				if !tt.omit {
+					issue["fields"].(map[string]any)["security"] = tt.security
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL)).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if (ticket.SecurityLevel == nil) != (tt.want == nil) || (tt.want != nil && *ticket.SecurityLevel != *tt.want) {
+				t.Errorf("SecurityLevel = %+v, want %+v", ticket.SecurityLevel, tt.want)
+			}
+		})
+	}
+}
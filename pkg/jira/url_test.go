@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBrowseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		key     string
+		want    string
+	}{
+		{name: "default", baseURL: RedHatJiraBaseURL, key: "RHEL-12345", want: "https://issues.redhat.com/browse/RHEL-12345"},
+		{name: "trailing slash", baseURL: "https://jira.example.com/", key: "A-1", want: "https://jira.example.com/browse/A-1"},
+		{name: "context path", baseURL: "https://example.com/jira", key: "A-1", want: "https://example.com/jira/browse/A-1"},
+		{name: "no key", baseURL: "https://jira.example.com", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClient(WithBaseURL(tt.baseURL)).BrowseURL(tt.key); got != tt.want {
+				t.Errorf("BrowseURL(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTicketURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(issueJSON("A-7", "Summary"))
+	}))
+	defer server.Close()
+
+	ticket, err := NewClient(WithBaseURL(server.URL + "/")).GetTicket("A-7")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if want := server.URL + "/browse/A-7"; ticket.URL != want {
+		t.Errorf("URL = %q, want %q", ticket.URL, want)
+	}
+}
+
+func TestGetTicketContextPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		context string
+	}{
+		{name: "context path", context: "/jira"},
+		{name: "context path with trailing slash", context: "/jira/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issueJSON("X-1", "Summary"))
+			}))
+			defer server.Close()
+
+			ticket, err := NewClient(WithBaseURL(server.URL + tt.context)).GetTicket("X-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if want := "/jira/rest/api/2/issue/X-1"; gotPath != want {
+				t.Errorf("request path = %q, want %q", gotPath, want)
+			}
+			if want := server.URL + "/jira/browse/X-1"; ticket.URL != want {
+				t.Errorf("URL = %q, want %q", ticket.URL, want)
+			}
+		})
+	}
+}
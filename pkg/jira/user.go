@@ -0,0 +1,46 @@
+package jira
+
+import (
+	"fmt"
+	neturl "net/url"
+)
+
+// WithFetchUserTimezone looks up the assignee's time zone when the issue response
+// doesn't include it, exposing it as User.TimeZone. The lookup is skipped for
+// anonymous clients, which cannot read user profiles.
+func WithFetchUserTimezone() ClientOption {
+	return func(c *Client) {
+		c.fetchUserTimezone = true
+	}
+}
+
+// GetUser fetches a user by account ID
+func (c *Client) GetUser(accountID string) (*User, error) {
+	query := neturl.Values{}
+	query.Set("accountId", accountID)
+	url := fmt.Sprintf("%s/rest/api/2/user?%s", c.BaseURL, query.Encode())
+
+	var user User
+	if err := c.getJSON(url, &user); err != nil {
+		return nil, fmt.Errorf("failed to fetch user %s: %w", accountID, err)
+	}
+
+	return &user, nil
+}
+
+// populateAssigneeTimezone fills in the assignee's time zone when enabled and
+// missing. Lookup failures leave the time zone empty rather than failing the ticket.
+func (c *Client) populateAssigneeTimezone(ticket *Ticket) {
+	if !c.fetchUserTimezone || c.token == "" {
+		return
+	}
+
+	assignee := ticket.Assignee
+	if assignee == nil || assignee.TimeZone != "" || assignee.AccountID == "" {
+		return
+	}
+
+	if user, err := c.GetUser(assignee.AccountID); err == nil {
+		assignee.TimeZone = user.TimeZone
+	}
+}
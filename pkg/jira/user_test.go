@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchUserTimezone(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		assignee     map[string]any
+		userStatus   int
+		wantTimeZone string
+		wantLookup   bool
+	}{
+		{
+			name:         "looked up",
+			opts:         []ClientOption{WithToken("token"), WithFetchUserTimezone()},
+			assignee:     map[string]any{"accountId": "5b10ac8d", "displayName": "Ada"},
+			userStatus:   http.StatusOK,
+			wantTimeZone: "Europe/Prague",
+			wantLookup:   true,
+		},
+		{
+			name:         "already in the issue",
+			opts:         []ClientOption{WithToken("token"), WithFetchUserTimezone()},
+			assignee:     map[string]any{"accountId": "5b10ac8d", "displayName": "Ada", "timeZone": "America/New_York"},
+			wantTimeZone: "America/New_York",
+		},
+		{
+			name:     "option not set",
+			opts:     []ClientOption{WithToken("token")},
+			assignee: map[string]any{"accountId": "5b10ac8d", "displayName": "Ada"},
+		},
+		{
+			name:     "anonymous",
+			opts:     []ClientOption{WithFetchUserTimezone()},
+			assignee: map[string]any{"accountId": "5b10ac8d", "displayName": "Ada"},
+		},
+		{
+			name:       "lookup fails",
+			opts:       []ClientOption{WithToken("token"), WithFetchUserTimezone(), WithMaxAttempts(1)},
+			assignee:   map[string]any{"accountId": "5b10ac8d", "displayName": "Ada"},
+			userStatus: http.StatusForbidden,
+			wantLookup: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookedUp bool
+			mux := http.NewServeMux()
+			mux.HandleFunc("/rest/api/2/issue/A-1", func(w http.ResponseWriter, r *http.Request) {
+				issue := issueJSON("A-1", "Summary")
+				issue["fields"].(map[string]any)["assignee"] = tt.assignee
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(issue)
+			})
+			mux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+				lookedUp = true
+				if got := r.URL.Query().Get("accountId"); got != "5b10ac8d" {
+					t.Errorf("accountId = %q, want 5b10ac8d", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.userStatus)
+				if tt.userStatus == http.StatusOK {
+					json.NewEncoder(w).Encode(map[string]any{"accountId": "5b10ac8d", "timeZone": "Europe/Prague"})
+				}
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			ticket, err := NewClient(append([]ClientOption{WithBaseURL(server.URL)}, tt.opts...)...).GetTicket("A-1")
+			if err != nil {
+				t.Fatalf("GetTicket() error = %v", err)
+			}
+			if ticket.Assignee.TimeZone != tt.wantTimeZone {
+				t.Errorf("TimeZone = %q, want %q", ticket.Assignee.TimeZone, tt.wantTimeZone)
+			}
+			if lookedUp != tt.wantLookup {
+				t.Errorf("user looked up = %v, want %v", lookedUp, tt.wantLookup)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlBoldPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	htmlItalicPattern = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	htmlLinkPattern   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	tableRowPattern   = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	tableDelimPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// htmlDocumentStyle is the minimal stylesheet embedded in documents from HTMLDocument
+const htmlDocumentStyle = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; }
+code { font-family: SFMono-Regular, Consolas, monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.8em; }
+blockquote { border-left: 4px solid #d0d7de; margin-left: 0; padding-left: 1em; color: #57606a; }`
+
+// HTMLDocument wraps rendered HTML in a standalone document with the given title
+func HTMLDocument(title, body string) string {
+	return fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n%s</body>\n</html>\n",
+		html.EscapeString(title), htmlDocumentStyle, body)
+}
+
+// RenderHTML converts markdown text to an HTML fragment. It supports headings,
+// paragraphs, nested lists, task lists, blockquotes, horizontal rules, fenced
+// code blocks, tables, and inline bold, italic, code and links. Line breaks
+// within a paragraph are kept, as in GitHub comments, and all text is HTML-escaped.
+func RenderHTML(text string) string {
+	r := &htmlRenderer{lines: strings.Split(text, "\n")}
+	r.render()
+	return r.out.String()
+}
+
+// htmlRenderer converts markdown to HTML one block at a time
+type htmlRenderer struct {
+	lines     []string
+	pos       int
+	out       strings.Builder
+	paragraph []string
+	lists     []htmlList
+}
+
+// htmlList is an open list, with the indentation of its items
+type htmlList struct {
+	tag    string
+	indent int
+}
+
+// render converts all lines
+func (r *htmlRenderer) render() {
+	for r.pos < len(r.lines) {
+		line := r.lines[r.pos]
+
+		switch {
+		case fenceOpenPattern.MatchString(line):
+			r.closeBlocks()
+			r.renderCodeBlock()
+			continue
+		case strings.TrimSpace(line) == "":
+			r.closeBlocks()
+		case headingPattern.MatchString(line):
+			r.closeBlocks()
+			m := headingPattern.FindStringSubmatch(line)
+			level := len(m[1])
+			r.out.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, renderHTMLInline(m[2]), level))
+		case ruleLinePattern.MatchString(line):
+			r.closeBlocks()
+			r.out.WriteString("<hr>\n")
+		case r.isTableStart():
+			r.closeBlocks()
+			r.renderTable()
+			continue
+		case blockquotePattern.MatchString(line):
+			r.closeBlocks()
+			r.renderBlockquote()
+			continue
+		case bulletPattern.MatchString(line):
+			m := bulletPattern.FindStringSubmatch(line)
+			r.renderListItem("ul", len(m[1]), m[2])
+		case orderedPattern.MatchString(line):
+			m := orderedPattern.FindStringSubmatch(line)
+			r.renderListItem("ol", len(m[1]), m[3])
+		default:
+			if len(r.lists) > 0 && leadingSpaces(line) > 0 {
+				// An indented continuation line belongs to the current list item
+				r.out.WriteString(" " + renderHTMLInline(strings.TrimSpace(line)))
+			} else {
+				r.closeLists()
+				r.paragraph = append(r.paragraph, strings.TrimSpace(line))
+			}
+		}
+		r.pos++
+	}
+	r.closeBlocks()
+}
+
+// renderCodeBlock renders a fenced code block starting at the current line
+func (r *htmlRenderer) renderCodeBlock() {
+	fence := fenceOpenPattern.FindStringSubmatch(r.lines[r.pos])[1]
+	lang := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(r.lines[r.pos]), fence))
+	r.pos++
+
+	var code []string
+	for r.pos < len(r.lines) {
+		if m := fenceOpenPattern.FindStringSubmatch(r.lines[r.pos]); m != nil && m[1] == fence {
+			r.pos++
+			break
+		}
+		code = append(code, r.lines[r.pos])
+		r.pos++
+	}
+
+	if lang != "" {
+		r.out.WriteString(fmt.Sprintf("<pre><code class=\"language-%s\">", html.EscapeString(lang)))
+	} else {
+		r.out.WriteString("<pre><code>")
+	}
+	r.out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	r.out.WriteString("</code></pre>\n")
+}
+
+// isTableStart reports whether a table header and delimiter row start at the current line
+func (r *htmlRenderer) isTableStart() bool {
+	return r.pos+1 < len(r.lines) &&
+		tableRowPattern.MatchString(r.lines[r.pos]) &&
+		tableDelimPattern.MatchString(r.lines[r.pos+1])
+}
+
+// renderTable renders a table starting at the current header line
+func (r *htmlRenderer) renderTable() {
+	r.out.WriteString("<table>\n<thead>\n<tr>")
+	for _, cell := range splitTableRow(r.lines[r.pos]) {
+		r.out.WriteString("<th>" + renderHTMLInline(cell) + "</th>")
+	}
+	r.out.WriteString("</tr>\n</thead>\n<tbody>\n")
+	r.pos += 2
+
+	for r.pos < len(r.lines) && tableRowPattern.MatchString(r.lines[r.pos]) {
+		r.out.WriteString("<tr>")
+		for _, cell := range splitTableRow(r.lines[r.pos]) {
+			r.out.WriteString("<td>" + renderHTMLInline(cell) + "</td>")
+		}
+		r.out.WriteString("</tr>\n")
+		r.pos++
+	}
+	r.out.WriteString("</tbody>\n</table>\n")
+}
+
+// renderBlockquote renders consecutive blockquote lines as a single blockquote
+func (r *htmlRenderer) renderBlockquote() {
+	var quoted []string
+	for r.pos < len(r.lines) {
+		m := blockquotePattern.FindStringSubmatch(r.lines[r.pos])
+		if m == nil {
+			break
+		}
+		quoted = append(quoted, m[1])
+		r.pos++
+	}
+	r.out.WriteString("<blockquote>\n")
+	r.out.WriteString(RenderHTML(strings.Join(quoted, "\n")))
+	r.out.WriteString("</blockquote>\n")
+}
+
+// renderListItem opens, closes or continues lists so the item nests by indentation
+func (r *htmlRenderer) renderListItem(tag string, indent int, item string) {
+	r.flushParagraph()
+
+	for len(r.lists) > 0 && indent < r.lists[len(r.lists)-1].indent {
+		r.closeList()
+	}
+
+	top := len(r.lists) - 1
+	switch {
+	case top >= 0 && indent > r.lists[top].indent:
+		r.out.WriteString("\n<" + tag + ">\n")
+		r.lists = append(r.lists, htmlList{tag: tag, indent: indent})
+	case top >= 0 && r.lists[top].tag == tag:
+		r.out.WriteString("</li>\n")
+	default:
+		if top >= 0 {
+			r.closeList()
+		}
+		r.out.WriteString("<" + tag + ">\n")
+		r.lists = append(r.lists, htmlList{tag: tag, indent: indent})
+	}
+
+	if c := checkboxItemPattern.FindStringSubmatch(item); c != nil {
+		checked := ""
+		if c[1] != " " {
+			checked = " checked"
+		}
+		r.out.WriteString(fmt.Sprintf("<li><input type=\"checkbox\" disabled%s> %s", checked, renderHTMLInline(c[2])))
+		return
+	}
+	r.out.WriteString("<li>" + renderHTMLInline(item))
+}
+
+// closeList closes the innermost open list
+func (r *htmlRenderer) closeList() {
+	list := r.lists[len(r.lists)-1]
+	r.lists = r.lists[:len(r.lists)-1]
+	r.out.WriteString("</li>\n</" + list.tag + ">\n")
+}
+
+// closeLists closes all open lists
+func (r *htmlRenderer) closeLists() {
+	for len(r.lists) > 0 {
+		r.closeList()
+	}
+}
+
+// flushParagraph writes any pending paragraph text
+func (r *htmlRenderer) flushParagraph() {
+	if len(r.paragraph) == 0 {
+		return
+	}
+	lines := make([]string, len(r.paragraph))
+	for i, line := range r.paragraph {
+		lines[i] = renderHTMLInline(line)
+	}
+	r.out.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	r.paragraph = nil
+}
+
+// closeBlocks ends the current paragraph and any open lists
+func (r *htmlRenderer) closeBlocks() {
+	r.flushParagraph()
+	r.closeLists()
+}
+
+// splitTableRow splits a markdown table row into its trimmed cells, honoring escaped pipes
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// leadingSpaces counts the spaces and tabs at the start of a line
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// renderHTMLInline escapes text and converts inline code, links, bold and italics.
+// Code spans are replaced first so their content is not styled.
+func renderHTMLInline(text string) string {
+	var codeSpans []string
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(match string) string {
+		codeSpans = append(codeSpans, "<code>"+html.EscapeString(match[1:len(match)-1])+"</code>")
+		return fmt.Sprintf("\x00%d\x00", len(codeSpans)-1)
+	})
+
+	text = html.EscapeString(text)
+	text = htmlLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := htmlLinkPattern.FindStringSubmatch(match)
+		if !safeLinkURL(html.UnescapeString(m[2])) {
+			return m[1]
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, m[2], m[1])
+	})
+	text = htmlBoldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return "<strong>" + match[2:len(match)-2] + "</strong>"
+	})
+	text = htmlItalicPattern.ReplaceAllString(text, "<em>$1</em>")
+
+	for i, span := range codeSpans {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return text
+}
+
+// safeLinkURL reports whether a link target may be used as an href: a relative URL
+// or an http, https or mailto one. Other schemes, such as javascript: and data:,
+// could run script when the saved plan is opened, so those links become plain text.
+func safeLinkURL(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
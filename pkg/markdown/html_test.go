@@ -0,0 +1,55 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHTMLLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		want     string
+		wantNone string
+	}{
+		{name: "https", text: "[docs](https://example.com/a?b=1&c=2)", want: `<a href="https://example.com/a?b=1&amp;c=2">docs</a>`},
+		{name: "http", text: "[docs](http://example.com)", want: `<a href="http://example.com">docs</a>`},
+		{name: "mailto", text: "[mail](mailto:team@example.com)", want: `<a href="mailto:team@example.com">mail</a>`},
+		{name: "relative", text: "[plan](plans/RHEL-1.html#steps)", want: `<a href="plans/RHEL-1.html#steps">plan</a>`},
+		{name: "javascript", text: "[click](javascript:alert(document.cookie))", want: "<p>click", wantNone: "<a"},
+		{name: "javascript uppercase", text: "[click](JavaScript:alert(1))", want: "<p>click", wantNone: "<a"},
+		{name: "data", text: "[img](data:text/html;base64,PHNjcmlwdD4=)", want: "<p>img</p>", wantNone: "<a"},
+		{name: "vbscript", text: "[run](vbscript:msgbox)", want: "<p>run</p>", wantNone: "<a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderHTML(tt.text)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("RenderHTML(%q) = %q, want it to contain %q", tt.text, got, tt.want)
+			}
+			if tt.wantNone != "" && strings.Contains(got, tt.wantNone) {
+				t.Errorf("RenderHTML(%q) = %q, want no %q", tt.text, got, tt.wantNone)
+			}
+		})
+	}
+}
+
+func TestHTMLDocument(t *testing.T) {
+	plan := "# Implementation Plan: Retry <uploads>\n\n**Ticket ID:** RHEL-1\n\n## Steps\n\n1. Add `retry` to the **exporter**\n2. Log retries\n\n```go\nif err != nil && n < 3 {}\n```\n"
+	doc := HTMLDocument("RHEL-1: Retry <uploads>", RenderHTML(plan))
+
+	for _, want := range []string{
+		"<title>RHEL-1: Retry &lt;uploads&gt;</title>",
+		"<h1>Implementation Plan: Retry &lt;uploads&gt;</h1>",
+		"<p><strong>Ticket ID:</strong> RHEL-1</p>",
+		"<h2>Steps</h2>",
+		"<li>Add <code>retry</code> to the <strong>exporter</strong></li>",
+		"<li>Log retries</li>",
+		"if err != nil &amp;&amp; n &lt; 3 {}",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document doesn't contain %q:\n%s", want, doc)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ANSI escape sequences used for terminal rendering
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+	ansiBlue      = "\x1b[34m"
+	ansiMagenta   = "\x1b[35m"
+	ansiCyan      = "\x1b[36m"
+)
+
+var (
+	headingPattern      = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletPattern       = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedPattern      = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	ruleLinePattern     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	boldPattern         = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	inlineCodePattern   = regexp.MustCompile("`([^`]+)`")
+	fenceOpenPattern    = regexp.MustCompile("^\\s*(```|~~~)")
+	blockquotePattern   = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	checkboxItemPattern = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+)
+
+// RenderTerminal renders markdown text with ANSI styling for display in a terminal.
+// Headings, bold text, inline code, lists, blockquotes and fenced code blocks are
+// styled; everything else is passed through unchanged.
+func RenderTerminal(text string) string {
+	var out strings.Builder
+	inCodeBlock := false
+	fence := ""
+
+	for _, line := range strings.Split(text, "\n") {
+		// Fenced code blocks are rendered verbatim with indentation
+		if m := fenceOpenPattern.FindStringSubmatch(line); m != nil {
+			if !inCodeBlock {
+				inCodeBlock, fence = true, m[1]
+				continue
+			}
+			if m[1] == fence {
+				inCodeBlock, fence = false, ""
+				continue
+			}
+		}
+		if inCodeBlock {
+			out.WriteString("    " + ansiCyan + line + ansiReset + "\n")
+			continue
+		}
+
+		out.WriteString(renderTerminalLine(line))
+		out.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// renderTerminalLine renders a single non-code markdown line
+func renderTerminalLine(line string) string {
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		title := renderInline(m[2])
+		switch len(m[1]) {
+		case 1:
+			return ansiBold + ansiUnderline + ansiMagenta + title + ansiReset
+		case 2:
+			return ansiBold + ansiBlue + title + ansiReset
+		default:
+			return ansiBold + title + ansiReset
+		}
+	}
+
+	if ruleLinePattern.MatchString(line) {
+		return ansiDim + strings.Repeat("─", 40) + ansiReset
+	}
+
+	if m := blockquotePattern.FindStringSubmatch(line); m != nil {
+		return ansiDim + "│ " + ansiReset + ansiItalic + renderInline(m[1]) + ansiReset
+	}
+
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		item := m[2]
+		if c := checkboxItemPattern.FindStringSubmatch(item); c != nil {
+			box := "☐"
+			if c[1] != " " {
+				box = "☑"
+			}
+			return m[1] + "  " + box + " " + renderInline(c[2])
+		}
+		return m[1] + "  • " + renderInline(item)
+	}
+
+	if m := orderedPattern.FindStringSubmatch(line); m != nil {
+		return m[1] + "  " + ansiBold + m[2] + "." + ansiReset + " " + renderInline(m[3])
+	}
+
+	return renderInline(line)
+}
+
+// renderInline applies inline styling for bold text and code spans
+func renderInline(text string) string {
+	text = inlineCodePattern.ReplaceAllString(text, ansiCyan+"$1"+ansiReset)
+	return boldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return ansiBold + match[2:len(match)-2] + ansiReset
+	})
+}
@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tocHeading is the heading of the table of contents inserted by AddTableOfContents
+const tocHeading = "Table of Contents"
+
+// Slug returns the GitHub-compatible anchor for a heading: inline formatting is
+// dropped, the text is lowercased, spaces become hyphens and punctuation other
+// than hyphens and underscores is removed, e.g. "Step 1: Add `retry`" becomes
+// "step-1-add-retry".
+func Slug(heading string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(plainHeading(heading)) {
+		switch {
+		case r == ' ':
+			slug.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r):
+			slug.WriteRune(r)
+		}
+	}
+	return slug.String()
+}
+
+// plainHeading strips links, bold, italics and code spans from heading text
+func plainHeading(heading string) string {
+	text := htmlLinkPattern.ReplaceAllString(heading, "$1")
+	text = boldPattern.ReplaceAllString(text, "$1$2")
+	text = strings.NewReplacer("`", "", "*", "").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+// TableOfContents returns a nested bullet list linking to each heading in text,
+// indented by heading level relative to the shallowest heading. Headings inside
+// fenced code blocks are ignored, and repeated headings get -1, -2, ... anchors
+// like on GitHub. It returns an empty string for text without headings.
+func TableOfContents(text string) string {
+	return tableOfContents(text, map[string]int{})
+}
+
+// tableOfContents builds the table of contents, counting anchors already used in seen
+func tableOfContents(text string, seen map[string]int) string {
+	type entry struct {
+		level int
+		title string
+		slug  string
+	}
+
+	var entries []entry
+	minLevel := 7
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		if fenceOpenPattern.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		m := headingPattern.FindStringSubmatch(line)
+		if inFence || m == nil {
+			continue
+		}
+
+		title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(m[2]), "#"))
+		if title == "" {
+			continue
+		}
+		slug := Slug(title)
+		if n := seen[slug]; n > 0 {
+			seen[slug] = n + 1
+			slug = fmt.Sprintf("%s-%d", slug, n)
+		} else {
+			seen[slug] = 1
+		}
+
+		level := len(m[1])
+		minLevel = min(minLevel, level)
+		entries = append(entries, entry{level: level, title: htmlLinkPattern.ReplaceAllString(title, "$1"), slug: slug})
+	}
+
+	var toc strings.Builder
+	for _, e := range entries {
+		toc.WriteString(fmt.Sprintf("%s- [%s](#%s)\n", strings.Repeat("  ", e.level-minLevel), e.title, e.slug))
+	}
+	return toc.String()
+}
+
+// AddTableOfContents inserts a "Table of Contents" section linking to the headings
+// of text before it. Text without headings is returned unchanged.
+func AddTableOfContents(text string) string {
+	toc := tableOfContents(text, map[string]int{Slug(tocHeading): 1})
+	if toc == "" {
+		return text
+	}
+	return fmt.Sprintf("## %s\n\n%s\n%s", tocHeading, toc, text)
+}
@@ -0,0 +1,90 @@
+package markdown
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{"Implementation Plan", "implementation-plan"},
+		{"Step 1: Add `retry`", "step-1-add-retry"},
+		{"**Risks** & Mitigations", "risks--mitigations"},
+		{"snake_case-and-hyphens", "snake_case-and-hyphens"},
+		{"Überprüfung", "überprüfung"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.heading); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.heading, got, tt.want)
+		}
+	}
+}
+
+func TestTableOfContents(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "nested headings",
+			text: "## Overview\n\nText\n\n### Scope\n\n#### Out of Scope\n\n## Implementation Steps\n\n### Step 1: Add `retry`\n",
+			want: "- [Overview](#overview)\n" +
+				"  - [Scope](#scope)\n" +
+				"    - [Out of Scope](#out-of-scope)\n" +
+				"- [Implementation Steps](#implementation-steps)\n" +
+				"  - [Step 1: Add `retry`](#step-1-add-retry)\n",
+		},
+		{
+			name: "duplicate headings",
+			text: "## Testing\n\n### Notes\n\n## Rollout\n\n### Notes\n\n### Notes\n",
+			want: "- [Testing](#testing)\n" +
+				"  - [Notes](#notes)\n" +
+				"- [Rollout](#rollout)\n" +
+				"  - [Notes](#notes-1)\n" +
+				"  - [Notes](#notes-2)\n",
+		},
+		{
+			name: "headings in code blocks ignored",
+			text: "## Changes\n\n```bash\n# not a heading\n```\n\n## Closing ##\n",
+			want: "- [Changes](#changes)\n- [Closing](#closing)\n",
+		},
+		{name: "no headings", text: "Just a paragraph.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TableOfContents(tt.text); got != tt.want {
+				t.Errorf("TableOfContents() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddTableOfContents(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "inserted before the plan",
+			text: "## Overview\n\n### Scope\n",
+			want: "## Table of Contents\n\n- [Overview](#overview)\n  - [Scope](#scope)\n\n## Overview\n\n### Scope\n",
+		},
+		{
+			name: "plan heading named like the contents",
+			text: "## Table of Contents\n",
+			want: "## Table of Contents\n\n- [Table of Contents](#table-of-contents-1)\n\n## Table of Contents\n",
+		},
+		{name: "no headings", text: "Just a paragraph.\n", want: "Just a paragraph.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddTableOfContents(tt.text); got != tt.want {
+				t.Errorf("AddTableOfContents() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
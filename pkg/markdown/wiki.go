@@ -0,0 +1,181 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderWiki converts markdown text to Jira wiki markup, as rendered by Jira Server
+// and Data Center comments. It converts headings (h1. to h6.), fenced code blocks
+// ({code}), nested bullet and numbered lists (* and #), task lists, blockquotes
+// (bq.), horizontal rules, tables, and inline bold, italic, code and links.
+func RenderWiki(text string) string {
+	r := &wikiRenderer{lines: strings.Split(text, "\n")}
+	r.render()
+	return strings.Join(r.out, "\n")
+}
+
+// wikiRenderer converts markdown to wiki markup one line at a time
+type wikiRenderer struct {
+	lines []string
+	pos   int
+	out   []string
+	lists []wikiList
+}
+
+// wikiList is an open list level, with the indentation of its items and its marker
+type wikiList struct {
+	indent int
+	marker byte
+}
+
+// render converts all lines
+func (r *wikiRenderer) render() {
+	for r.pos < len(r.lines) {
+		line := r.lines[r.pos]
+
+		switch {
+		case fenceOpenPattern.MatchString(line):
+			r.lists = nil
+			r.renderCodeBlock()
+			continue
+		case strings.TrimSpace(line) == "":
+			r.lists = nil
+			r.out = append(r.out, "")
+		case headingPattern.MatchString(line):
+			r.lists = nil
+			m := headingPattern.FindStringSubmatch(line)
+			r.out = append(r.out, fmt.Sprintf("h%d. %s", len(m[1]), renderWikiInline(m[2])))
+		case ruleLinePattern.MatchString(line):
+			r.lists = nil
+			r.out = append(r.out, "----")
+		case r.isTableStart():
+			r.lists = nil
+			r.renderTable()
+			continue
+		case blockquotePattern.MatchString(line):
+			r.lists = nil
+			r.out = append(r.out, "bq. "+renderWikiInline(blockquotePattern.FindStringSubmatch(line)[1]))
+		case bulletPattern.MatchString(line):
+			m := bulletPattern.FindStringSubmatch(line)
+			r.renderListItem('*', len(m[1]), m[2])
+		case orderedPattern.MatchString(line):
+			m := orderedPattern.FindStringSubmatch(line)
+			r.renderListItem('#', len(m[1]), m[3])
+		default:
+			if len(r.lists) > 0 && leadingSpaces(line) > 0 && len(r.out) > 0 {
+				// Wiki list items are single lines, so join indented continuations
+				r.out[len(r.out)-1] += " " + renderWikiInline(strings.TrimSpace(line))
+			} else {
+				r.lists = nil
+				r.out = append(r.out, renderWikiInline(strings.TrimSpace(line)))
+			}
+		}
+		r.pos++
+	}
+}
+
+// renderCodeBlock renders a fenced code block starting at the current line
+func (r *wikiRenderer) renderCodeBlock() {
+	fence := fenceOpenPattern.FindStringSubmatch(r.lines[r.pos])[1]
+	lang := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(r.lines[r.pos]), fence))
+	r.pos++
+
+	if lang != "" {
+		r.out = append(r.out, "{code:"+lang+"}")
+	} else {
+		r.out = append(r.out, "{code}")
+	}
+	for r.pos < len(r.lines) {
+		if m := fenceOpenPattern.FindStringSubmatch(r.lines[r.pos]); m != nil && m[1] == fence {
+			r.pos++
+			break
+		}
+		r.out = append(r.out, r.lines[r.pos])
+		r.pos++
+	}
+	r.out = append(r.out, "{code}")
+}
+
+// isTableStart reports whether a table header and delimiter row start at the current line
+func (r *wikiRenderer) isTableStart() bool {
+	return r.pos+1 < len(r.lines) &&
+		tableRowPattern.MatchString(r.lines[r.pos]) &&
+		tableDelimPattern.MatchString(r.lines[r.pos+1])
+}
+
+// renderTable renders a table starting at the current header line
+func (r *wikiRenderer) renderTable() {
+	r.out = append(r.out, wikiTableRow(r.lines[r.pos], "||"))
+	r.pos += 2
+
+	for r.pos < len(r.lines) && tableRowPattern.MatchString(r.lines[r.pos]) {
+		r.out = append(r.out, wikiTableRow(r.lines[r.pos], "|"))
+		r.pos++
+	}
+}
+
+// wikiTableRow renders a markdown table row with the given cell separator
+func wikiTableRow(line, sep string) string {
+	cells := splitTableRow(line)
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(renderWikiInline(cell), "|", `\|`)
+		if cells[i] == "" {
+			cells[i] = " "
+		}
+	}
+	return sep + strings.Join(cells, sep) + sep
+}
+
+// renderListItem renders a list item, nesting it by indentation. Wiki markup
+// writes nesting as a marker per level, e.g. "*#" for a numbered item in a bullet list.
+func (r *wikiRenderer) renderListItem(marker byte, indent int, item string) {
+	for len(r.lists) > 0 && indent < r.lists[len(r.lists)-1].indent {
+		r.lists = r.lists[:len(r.lists)-1]
+	}
+
+	top := len(r.lists) - 1
+	switch {
+	case top < 0 || indent > r.lists[top].indent:
+		r.lists = append(r.lists, wikiList{indent: indent, marker: marker})
+	default:
+		r.lists[top].marker = marker
+	}
+
+	prefix := make([]byte, len(r.lists))
+	for i, list := range r.lists {
+		prefix[i] = list.marker
+	}
+
+	if c := checkboxItemPattern.FindStringSubmatch(item); c != nil {
+		box := "☐"
+		if c[1] != " " {
+			box = "☑"
+		}
+		item = box + " " + c[2]
+	}
+	r.out = append(r.out, string(prefix)+" "+renderWikiInline(item))
+}
+
+// renderWikiInline converts inline code, links, bold and italics to wiki markup.
+// Code spans are replaced first so their content is not styled.
+func renderWikiInline(text string) string {
+	var codeSpans []string
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(match string) string {
+		codeSpans = append(codeSpans, "{{"+match[1:len(match)-1]+"}}")
+		return fmt.Sprintf("\x00%d\x00", len(codeSpans)-1)
+	})
+
+	text = htmlLinkPattern.ReplaceAllString(text, "[$1|$2]")
+	// Bold becomes a placeholder so the italic pass doesn't match its asterisks
+	text = htmlBoldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return "\x01" + match[2:len(match)-2] + "\x01"
+	})
+	text = htmlItalicPattern.ReplaceAllString(text, "_${1}_")
+	text = strings.ReplaceAll(text, "\x01", "*")
+
+	for i, span := range codeSpans {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return text
+}
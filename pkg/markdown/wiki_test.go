@@ -0,0 +1,31 @@
+package markdown
+
+import "testing"
+
+func TestRenderWiki(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "headings", text: "# Plan\n### Steps", want: "h1. Plan\nh3. Steps"},
+		{name: "bold and italic", text: "Use **care** and *speed*", want: "Use *care* and _speed_"},
+		{name: "inline code is not styled", text: "Call `a**b**c` now", want: "Call {{a**b**c}} now"},
+		{name: "link", text: "See [docs](https://example.com)", want: "See [docs|https://example.com]"},
+		{name: "bullet list", text: "- one\n- two", want: "* one\n* two"},
+		{name: "numbered list nested in bullets", text: "- steps\n  1. first\n  2. second", want: "* steps\n*# first\n*# second"},
+		{name: "task list", text: "- [ ] todo\n- [x] done", want: "* ☐ todo\n* ☑ done"},
+		{name: "code fence with language", text: "```go\nx := **y**\n```", want: "{code:go}\nx := **y**\n{code}"},
+		{name: "code fence without language", text: "```\n# not a heading\n```", want: "{code}\n# not a heading\n{code}"},
+		{name: "blockquote and rule", text: "> note\n\n---", want: "bq. note\n\n----"},
+		{name: "table", text: "| A | B |\n|---|---|\n| 1 | |", want: "||A||B||\n|1| |"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderWiki(tt.text); got != tt.want {
+				t.Errorf("RenderWiki(%q) =\n%q\nwant\n%q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
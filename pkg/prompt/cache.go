@@ -0,0 +1,29 @@
+package prompt
+
+import "strings"
+
+// WithContextLast places the ticket context of POML templates after the
+// instructions, output format and style guidance, so everything shared by the
+// tickets of a batch forms a prompt prefix that can be cached. Markdown
+// templates are rendered as written.
+func WithContextLast() RenderOption {
+	return func(o *renderOptions) {
+		o.contextLast = true
+	}
+}
+
+// CacheablePrefix returns the part of a rendered prompt that doesn't depend on
+// the ticket, found by comparing it with probe, the same template rendered for a
+// different ticket such as SampleTicket. The prefix ends at a line boundary, and
+// is empty when the prompts differ within their first line.
+func CacheablePrefix(rendered, probe string) string {
+	n := 0
+	for n < len(rendered) && n < len(probe) && rendered[n] == probe[n] {
+		n++
+	}
+	if n == len(rendered) {
+		// The prompt doesn't depend on the ticket at all; keep something to send after the prefix
+		n--
+	}
+	return rendered[:strings.LastIndexByte(rendered[:max(n, 0)], '\n')+1]
+}
@@ -0,0 +1,66 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCacheablePrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		rendered string
+		probe    string
+		want     string
+	}{
+		{name: "shared lines", rendered: "Role\nTask\nTicket A-1\n", probe: "Role\nTask\nTicket B-2\n", want: "Role\nTask\n"},
+		{name: "differs within a line", rendered: "Role\nTask for A-1\n", probe: "Role\nTask for B-2\n", want: "Role\n"},
+		{name: "differs in the first line", rendered: "Plan A-1\n", probe: "Plan B-2\n", want: ""},
+		{name: "identical", rendered: "Role\nTask\n", probe: "Role\nTask\n", want: "Role\n"},
+		{name: "empty", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CacheablePrefix(tt.rendered, tt.probe); got != tt.want {
+				t.Errorf("CacheablePrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderDefaultTemplateContextLast(t *testing.T) {
+	ticket := SampleTicket()
+	other := SampleTicket()
+	other.Key, other.Summary = "OTHER-9", "Rotate the signing keys"
+
+	tests := []struct {
+		name       string
+		opts       []RenderOption
+		wantPrefix bool
+	}{
+		{name: "context first"},
+		{name: "context last", opts: []RenderOption{WithContextLast()}, wantPrefix: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			probe, err := LoadAndRenderTemplate(EmbeddedTemplatePath, other, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+
+			prefix := CacheablePrefix(rendered, probe)
+			if strings.Contains(prefix, ticket.Summary) {
+				t.Errorf("cacheable prefix contains the ticket:\n%s", prefix)
+			}
+			// With the context last, the instructions are shared and make up most of the prompt
+			if got := len(prefix) > len(rendered)/2; got != tt.wantPrefix {
+				t.Errorf("prefix is %d of %d bytes, want most of the prompt %v", len(prefix), len(rendered), tt.wantPrefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templateSetEntry is the base name (without extension) of the template executed
+// when rendering a directory or glob of templates
+const templateSetEntry = "main"
+
+// isTemplateSet reports whether a template path names several files to compose
+// (a local directory or a glob pattern) rather than a single template
+func isTemplateSet(templatePath string) bool {
+	if IsRemoteTemplate(templatePath) || strings.HasPrefix(templatePath, embeddedPrefix) {
+		return false
+	}
+	if strings.ContainsAny(templatePath, "*?[") {
+		return true
+	}
+	info, err := os.Stat(templatePath)
+	return err == nil && info.IsDir()
+}
+
+// templateSetFiles returns the regular files in a template directory or matching
+// a glob, sorted by name
+func templateSetFiles(templatePath string) ([]string, error) {
+	pattern := templatePath
+	if info, err := os.Stat(templatePath); err == nil && info.IsDir() {
+		pattern = filepath.Join(templatePath, "*")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template pattern %s: %w", templatePath, err)
+	}
+
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no template files found in %s", templatePath)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// templateName returns the name a composed template file is defined under: its
+// base name without the extension, e.g. "base" for prompts/base.md
+func templateName(file string) string {
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// entryTemplateFile returns the file executed when rendering a template set, the
+// one named main (e.g. main.md or main.poml)
+func entryTemplateFile(files []string) (string, error) {
+	for _, file := range files {
+		if templateName(file) == templateSetEntry {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("no %s template among %s", templateSetEntry, strings.Join(files, ", "))
+}
+
+// MergeTemplates parses several template files into one set, so templates defined
+// with {{define}} in one file can be included with {{template "name" .}} in
+// another. Each file is also defined under its base name without the extension,
+// so prompts/base.md can be included as {{template "base" .}}. The returned
+// template is the file named main, which is executed to render the set.
+func MergeTemplates(files ...string) (*template.Template, error) {
+	entry, err := entryTemplateFile(files)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := template.New(templateName(entry)).Funcs(templateFuncs)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file %s: %w", file, err)
+		}
+
+		t := tmpl
+		if file != entry {
+			t = tmpl.New(templateName(file))
+		}
+		if _, err := t.Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", file, err)
+		}
+	}
+
+	return tmpl, nil
+}
+
+// loadTemplateSet parses every file in a template directory or glob together
+func loadTemplateSet(templatePath string) (*template.Template, error) {
+	files, err := templateSetFiles(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	return MergeTemplates(files...)
+}
+
+// renderedExtension returns the extension deciding how a template path is
+// rendered: that of the main file for a template set, otherwise the path's own
+func renderedExtension(templatePath string) string {
+	if !isTemplateSet(templatePath) {
+		return templateExtension(templatePath)
+	}
+
+	files, err := templateSetFiles(templatePath)
+	if err != nil {
+		return ""
+	}
+	entry, err := entryTemplateFile(files)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(filepath.Ext(entry))
+}
@@ -0,0 +1,52 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemplates writes the named template files to a new directory
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMergeTemplates(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"base.md":     "Ticket {{.Ticket.Key}}",
+		"partials.md": `{{define "steps"}}Steps for {{.Summary}}{{end}}`,
+		"main.md":     `{{template "base" .}} | {{template "steps" .}}`,
+	})
+
+	tmpl, err := MergeTemplates(filepath.Join(dir, "base.md"), filepath.Join(dir, "partials.md"), filepath.Join(dir, "main.md"))
+	if err != nil {
+		t.Fatalf("MergeTemplates() error = %v", err)
+	}
+	if got := tmpl.Name(); got != "main" {
+		t.Errorf("Name() = %q, want main", got)
+	}
+
+	rendered, err := LoadAndRenderTemplate(dir, SampleTicket())
+	if err != nil {
+		t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+	}
+	if want := "Ticket DEMO-123 | Steps for Add retry support to the export job"; strings.TrimSpace(rendered) != want {
+		t.Errorf("rendered = %q, want %q", rendered, want)
+	}
+}
+
+func TestMergeTemplatesWithoutMain(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{"base.md": "Ticket {{.Ticket.Key}}"})
+
+	if _, err := MergeTemplates(filepath.Join(dir, "base.md")); err == nil || !strings.Contains(err.Error(), "no main template") {
+		t.Errorf("MergeTemplates() error = %v, want a missing main template error", err)
+	}
+}
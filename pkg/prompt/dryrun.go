@@ -0,0 +1,139 @@
+package prompt
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// withTemplateInspection makes executeTemplate fail on missing map keys (e.g. an
+// unset {{.Vars.name}}) and pass the parsed template to inspect before executing it
+func withTemplateInspection(inspect func(*template.Template)) RenderOption {
+	return func(o *renderOptions) {
+		o.missingKeyError = true
+		o.inspect = inspect
+	}
+}
+
+// DryRunRender renders a template like LoadAndRenderTemplate and also returns a
+// warning for each TemplateData field the template never uses, to help template
+// authors spot data they forgot to include (e.g. {{.Components}}). Missing map
+// keys such as an unset {{.Vars.name}} are errors rather than "<no value>".
+func DryRunRender(templatePath string, ticket *jira.Ticket, opts ...RenderOption) (string, []string, error) {
+	var parsed *template.Template
+	opts = append(opts, withTemplateInspection(func(t *template.Template) { parsed = t }))
+
+	rendered, err := NewRendererForPath(templatePath, opts...).Render(ticket)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var warnings []string
+	for _, field := range unusedTemplateFields(parsed) {
+		warnings = append(warnings, fmt.Sprintf("template does not use {{.%s}}", field))
+	}
+	return rendered, warnings, nil
+}
+
+// unusedTemplateFields returns the TemplateData fields, in declaration order, that
+// are not referenced by the template or the templates it defines
+func unusedTemplateFields(tmpl *template.Template) []string {
+	used := make(map[string]bool)
+	if tmpl != nil {
+		w := &fieldWalker{tmpl: tmpl, used: used, visited: make(map[string]bool)}
+		w.walkTemplate(tmpl.Name(), true)
+	}
+
+	var unused []string
+	dataType := reflect.TypeOf(TemplateData{})
+	for i := 0; i < dataType.NumField(); i++ {
+		if name := dataType.Field(i).Name; !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	return unused
+}
+
+// fieldWalker records the TemplateData fields referenced in a template's parse
+// tree. Fields are only attributed to TemplateData where dot is the template data,
+// i.e. not inside the body of a range or with, unless they are accessed through $.
+type fieldWalker struct {
+	tmpl    *template.Template
+	used    map[string]bool
+	visited map[string]bool
+}
+
+// walkTemplate walks the named template, once per template and dot kind
+func (w *fieldWalker) walkTemplate(name string, atRoot bool) {
+	key := fmt.Sprintf("%s/%t", name, atRoot)
+	t := w.tmpl.Lookup(name)
+	if w.visited[key] || t == nil || t.Tree == nil {
+		return
+	}
+	w.visited[key] = true
+	w.walk(t.Tree.Root, atRoot)
+}
+
+// walk records the fields referenced by node; atRoot reports whether dot is the template data
+func (w *fieldWalker) walk(node parse.Node, atRoot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, atRoot)
+		}
+	case *parse.ActionNode:
+		w.walk(n.Pipe, atRoot)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			w.walk(cmd, atRoot)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			w.walk(arg, atRoot)
+		}
+	case *parse.FieldNode:
+		if atRoot {
+			w.used[n.Ident[0]] = true
+		}
+	case *parse.ChainNode:
+		w.walk(n.Node, atRoot)
+	case *parse.VariableNode:
+		// $ is always the template data
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			w.used[n.Ident[1]] = true
+		}
+	case *parse.IfNode:
+		w.walk(n.Pipe, atRoot)
+		w.walk(n.List, atRoot)
+		w.walk(n.ElseList, atRoot)
+	case *parse.RangeNode:
+		w.walk(n.Pipe, atRoot)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, atRoot)
+	case *parse.WithNode:
+		w.walk(n.Pipe, atRoot)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, atRoot)
+	case *parse.TemplateNode:
+		w.walk(n.Pipe, atRoot)
+		w.walkTemplate(n.Name, atRoot && isDotPipe(n.Pipe))
+	}
+}
+
+// isDotPipe reports whether a pipeline is just dot, as in {{template "name" .}}
+func isDotPipe(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	_, ok := pipe.Cmds[0].Args[0].(*parse.DotNode)
+	return ok
+}
@@ -0,0 +1,39 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDryRunRenderUnusedFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		wantUnused bool
+	}{
+		{name: "labels unused", template: "{{.Summary}}\n{{.Description}}", wantUnused: true},
+		{name: "labels used", template: "{{.Summary}}\nLabels: {{.Labels}}"},
+		{name: "labels through $ inside with", template: "{{with .Ticket}}{{$.Labels}}{{end}}"},
+		{name: "ticket labels inside with", template: "{{with .Ticket}}{{.Labels}}{{end}}", wantUnused: true},
+	}
+
+	const warning = "template does not use {{.Labels}}"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.tmpl")
+			if err := os.WriteFile(path, []byte(tt.template), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, warnings, err := DryRunRender(path, SampleTicket())
+			if err != nil {
+				t.Fatalf("DryRunRender() error = %v", err)
+			}
+			if got := slices.Contains(warnings, warning); got != tt.wantUnused {
+				t.Errorf("warnings = %q, want %q reported: %v", warnings, warning, tt.wantUnused)
+			}
+		})
+	}
+}
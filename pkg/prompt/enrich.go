@@ -0,0 +1,84 @@
+package prompt
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// Enricher adds custom context to a ticket's prompt, e.g. from an internal
+// service or a code search. The values it returns are exposed to templates as
+// {{.Vars.name}}.
+type Enricher interface {
+	Enrich(ctx context.Context, ticket *jira.Ticket) (map[string]string, error)
+}
+
+// NopEnricher is an Enricher that adds nothing; it is the default
+type NopEnricher struct{}
+
+// Enrich implements Enricher
+func (NopEnricher) Enrich(ctx context.Context, ticket *jira.Ticket) (map[string]string, error) {
+	return nil, nil
+}
+
+var (
+	enrichersMu sync.Mutex
+	enrichers   []Enricher
+)
+
+// RegisterEnricher adds an Enricher to those returned by RegisteredEnricher,
+// typically from an init function in a file or package built into jig. Enrichers
+// run in registration order, and later ones override earlier values.
+func RegisterEnricher(enricher Enricher) {
+	enrichersMu.Lock()
+	defer enrichersMu.Unlock()
+	enrichers = append(enrichers, enricher)
+}
+
+// RegisteredEnricher returns the registered enrichers combined into one, or a
+// NopEnricher when none are registered
+func RegisteredEnricher() Enricher {
+	enrichersMu.Lock()
+	defer enrichersMu.Unlock()
+	if len(enrichers) == 0 {
+		return NopEnricher{}
+	}
+	return enricherChain(slices.Clone(enrichers))
+}
+
+// enricherChain merges the values of several enrichers, failing on the first error
+type enricherChain []Enricher
+
+// Enrich implements Enricher
+func (c enricherChain) Enrich(ctx context.Context, ticket *jira.Ticket) (map[string]string, error) {
+	var merged map[string]string
+	for _, enricher := range c {
+		values, err := enricher.Enrich(ctx, ticket)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 && merged == nil {
+			merged = make(map[string]string, len(values))
+		}
+		maps.Copy(merged, values)
+	}
+	return merged, nil
+}
+
+// EnrichVars returns vars merged with the enricher's values for ticket. Values in
+// vars, such as those given with --var, take precedence over enriched ones. vars
+// is not modified.
+func EnrichVars(ctx context.Context, enricher Enricher, ticket *jira.Ticket, vars map[string]string) (map[string]string, error) {
+	enriched, err := enricher.Enrich(ctx, ticket)
+	if err != nil || len(enriched) == 0 {
+		return vars, err
+	}
+
+	merged := make(map[string]string, len(enriched)+len(vars))
+	maps.Copy(merged, enriched)
+	maps.Copy(merged, vars)
+	return merged, nil
+}
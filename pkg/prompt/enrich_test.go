@@ -0,0 +1,78 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// fakeEnricher returns fixed values, or an error
+type fakeEnricher struct {
+	values map[string]string
+	err    error
+}
+
+// Enrich implements Enricher
+func (e fakeEnricher) Enrich(ctx context.Context, ticket *jira.Ticket) (map[string]string, error) {
+	return e.values, e.err
+}
+
+func TestRegisteredEnricher(t *testing.T) {
+	tests := []struct {
+		name      string
+		enrichers []Enricher
+		vars      map[string]string
+		want      string
+		wantErr   bool
+	}{
+		{name: "none registered", want: "Team: none"},
+		{name: "enriched value", enrichers: []Enricher{fakeEnricher{values: map[string]string{"team": "SRE"}}}, want: "Team: SRE"},
+		{
+			name:      "later enricher overrides",
+			enrichers: []Enricher{fakeEnricher{values: map[string]string{"team": "SRE"}}, fakeEnricher{values: map[string]string{"team": "Storage"}}},
+			want:      "Team: Storage",
+		},
+		{
+			name:      "--var takes precedence",
+			enrichers: []Enricher{fakeEnricher{values: map[string]string{"team": "SRE"}}},
+			vars:      map[string]string{"team": "Networking"},
+			want:      "Team: Networking",
+		},
+		{
+			name:      "failing enricher",
+			enrichers: []Enricher{fakeEnricher{values: map[string]string{"team": "SRE"}}, fakeEnricher{err: errors.New("service unavailable")}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := enrichers
+			enrichers = nil
+			t.Cleanup(func() { enrichers = original })
+			for _, enricher := range tt.enrichers {
+				RegisterEnricher(enricher)
+			}
+
+			ticket := SampleTicket()
+			vars, err := EnrichVars(context.Background(), RegisteredEnricher(), ticket, tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EnrichVars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			rendered, err := RenderString("enriched", `Team: {{or .Vars.team "none"}}`, ticket, WithVars(vars))
+			if err != nil {
+				t.Fatalf("RenderString() error = %v", err)
+			}
+			if !strings.Contains(rendered, tt.want) {
+				t.Errorf("rendered prompt = %q, want it to contain %q", rendered, tt.want)
+			}
+		})
+	}
+}
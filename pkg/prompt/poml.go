@@ -3,9 +3,7 @@ package prompt
 import (
 	"encoding/xml"
 	"fmt"
-	"os"
 	"strings"
-	"text/template"
 
 	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
 )
@@ -29,9 +27,30 @@ type POMLContext struct {
 // POMLSection represents a context section
 type POMLSection struct {
 	Name        string      `xml:"name,attr"`
+	Ticket      string      `xml:"ticket"`
 	Title       string      `xml:"title"`
 	Description string      `xml:"description"`
+	Environment string      `xml:"environment"`
+	Diff        string      `xml:"diff"`
+	Comments    string      `xml:"comments"`
+	Project     string      `xml:"project"`
 	Metadata    POMLMetadata `xml:"metadata"`
+
+	RelatedTickets []POMLRelatedTicket `xml:"related-tickets>ticket"`
+	CustomFields   []POMLCustomField   `xml:"custom-fields>field"`
+}
+
+// POMLCustomField represents a custom field value, labelled with its display name
+type POMLCustomField struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// POMLRelatedTicket represents a related ticket included for context
+type POMLRelatedTicket struct {
+	Key     string `xml:"key,attr"`
+	Status  string `xml:"status,attr"`
+	Summary string `xml:",chardata"`
 }
 
 // POMLMetadata represents ticket metadata
@@ -40,9 +59,11 @@ type POMLMetadata struct {
 	Type       string `xml:"type"`
 	Priority   string `xml:"priority"`
 	Assignee   string `xml:"assignee"`
+	TimeZone   string `xml:"assignee-timezone"`
 	Reporter   string `xml:"reporter"`
 	Components string `xml:"components"`
 	Labels     string `xml:"labels"`
+	Sprint     string `xml:"sprint"`
 }
 
 // POMLRequirement represents an instruction requirement
@@ -68,40 +89,15 @@ type POMLStyle struct {
 }
 
 // LoadAndRenderPOMLTemplate loads a POML template and renders it with ticket data
-func LoadAndRenderPOMLTemplate(templatePath string, ticket *jira.Ticket) (string, error) {
-	// Read the POML template file
-	templateContent, err := os.ReadFile(templatePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read POML template file %s: %w", templatePath, err)
-	}
-
-	// Create template data from ticket
-	data := createTemplateData(ticket)
-
-	// Parse template with Go templating first (for variable substitution)
-	tmpl, err := template.New("poml").Parse(string(templateContent))
-	if err != nil {
-		return "", fmt.Errorf("failed to parse POML template: %w", err)
-	}
-
-	var buf strings.Builder
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute POML template: %w", err)
-	}
-
-	// Parse the rendered POML XML
-	var pomlDoc POMLDocument
-	if err := xml.Unmarshal([]byte(buf.String()), &pomlDoc); err != nil {
-		return "", fmt.Errorf("failed to parse POML XML: %w", err)
-	}
-
-	// Convert POML to plain text prompt
-	prompt := convertPOMLToPrompt(&pomlDoc)
-	return prompt, nil
+func LoadAndRenderPOMLTemplate(templatePath string, ticket *jira.Ticket, opts ...RenderOption) (string, error) {
+	return (&POMLRenderer{Path: templatePath, Options: opts}).Render(ticket)
 }
 
-// convertPOMLToPrompt converts a POML document to a plain text prompt
-func convertPOMLToPrompt(doc *POMLDocument) string {
+// convertPOMLToPrompt converts a POML document to a plain text prompt. With
+// contextLast, the ticket context follows the instructions, output format and
+// style guidance instead of preceding them, so the parts shared by every ticket
+// form a prefix of the prompt that can be cached.
+func convertPOMLToPrompt(doc *POMLDocument, contextLast bool) string {
 	var prompt strings.Builder
 
 	// Add role
@@ -114,41 +110,8 @@ func convertPOMLToPrompt(doc *POMLDocument) string {
 		prompt.WriteString(fmt.Sprintf("Task: %s\n\n", strings.TrimSpace(doc.Task)))
 	}
 
-	// Add context
-	if len(doc.Context.Sections) > 0 {
-		prompt.WriteString("Context:\n")
-		for _, section := range doc.Context.Sections {
-			if section.Title != "" {
-				prompt.WriteString(fmt.Sprintf("\nTicket: %s\n", section.Title))
-			}
-			if section.Description != "" {
-				prompt.WriteString(fmt.Sprintf("Description: %s\n", section.Description))
-			}
-
-			// Add metadata
-			if section.Metadata.Status != "" {
-				prompt.WriteString(fmt.Sprintf("Status: %s\n", section.Metadata.Status))
-			}
-			if section.Metadata.Type != "" {
-				prompt.WriteString(fmt.Sprintf("Type: %s\n", section.Metadata.Type))
-			}
-			if section.Metadata.Priority != "" {
-				prompt.WriteString(fmt.Sprintf("Priority: %s\n", section.Metadata.Priority))
-			}
-			if section.Metadata.Assignee != "" {
-				prompt.WriteString(fmt.Sprintf("Assignee: %s\n", section.Metadata.Assignee))
-			}
-			if section.Metadata.Reporter != "" {
-				prompt.WriteString(fmt.Sprintf("Reporter: %s\n", section.Metadata.Reporter))
-			}
-			if section.Metadata.Components != "" {
-				prompt.WriteString(fmt.Sprintf("Components: %s\n", section.Metadata.Components))
-			}
-			if section.Metadata.Labels != "" {
-				prompt.WriteString(fmt.Sprintf("Labels: %s\n", section.Metadata.Labels))
-			}
-		}
-		prompt.WriteString("\n")
+	if !contextLast {
+		writePOMLContext(&prompt, doc)
 	}
 
 	// Add instructions
@@ -181,5 +144,81 @@ func convertPOMLToPrompt(doc *POMLDocument) string {
 		prompt.WriteString(fmt.Sprintf("%s\n", strings.TrimSpace(doc.Style.Formatting)))
 	}
 
+	if contextLast {
+		prompt.WriteString("\n")
+		writePOMLContext(&prompt, doc)
+	}
+
 	return prompt.String()
-}
\ No newline at end of file
+}
+
+// writePOMLContext writes the ticket context sections of a POML document
+func writePOMLContext(prompt *strings.Builder, doc *POMLDocument) {
+	if len(doc.Context.Sections) == 0 {
+		return
+	}
+
+	prompt.WriteString("Context:\n")
+	for _, section := range doc.Context.Sections {
+		if ticket := strings.TrimSpace(section.Ticket); ticket != "" {
+			prompt.WriteString(fmt.Sprintf("\n%s\n", ticket))
+		}
+		if section.Title != "" {
+			prompt.WriteString(fmt.Sprintf("\nTicket: %s\n", section.Title))
+		}
+		if section.Description != "" {
+			prompt.WriteString(fmt.Sprintf("Description: %s\n", section.Description))
+		}
+		if section.Environment != "" {
+			prompt.WriteString(fmt.Sprintf("Environment: %s\n", strings.TrimSpace(section.Environment)))
+		}
+
+		// Add metadata
+		if section.Metadata.Status != "" {
+			prompt.WriteString(fmt.Sprintf("Status: %s\n", section.Metadata.Status))
+		}
+		if section.Metadata.Type != "" {
+			prompt.WriteString(fmt.Sprintf("Type: %s\n", section.Metadata.Type))
+		}
+		if section.Metadata.Priority != "" {
+			prompt.WriteString(fmt.Sprintf("Priority: %s\n", section.Metadata.Priority))
+		}
+		if section.Metadata.Assignee != "" {
+			prompt.WriteString(fmt.Sprintf("Assignee: %s\n", section.Metadata.Assignee))
+		}
+		if section.Metadata.TimeZone != "" {
+			prompt.WriteString(fmt.Sprintf("Assignee Time Zone: %s\n", section.Metadata.TimeZone))
+		}
+		if section.Metadata.Reporter != "" {
+			prompt.WriteString(fmt.Sprintf("Reporter: %s\n", section.Metadata.Reporter))
+		}
+		if section.Metadata.Components != "" {
+			prompt.WriteString(fmt.Sprintf("Components: %s\n", section.Metadata.Components))
+		}
+		if section.Metadata.Labels != "" {
+			prompt.WriteString(fmt.Sprintf("Labels: %s\n", section.Metadata.Labels))
+		}
+		if section.Metadata.Sprint != "" {
+			prompt.WriteString(fmt.Sprintf("Sprint: %s\n", section.Metadata.Sprint))
+		}
+		for _, field := range section.CustomFields {
+			prompt.WriteString(fmt.Sprintf("%s: %s\n", field.Name, strings.TrimSpace(field.Value)))
+		}
+		if project := strings.TrimSpace(section.Project); project != "" {
+			prompt.WriteString(fmt.Sprintf("Project Context:\n%s\n", project))
+		}
+		if len(section.RelatedTickets) > 0 {
+			prompt.WriteString("Related Tickets:\n")
+			for _, related := range section.RelatedTickets {
+				prompt.WriteString(fmt.Sprintf("- %s [%s]: %s\n", related.Key, related.Status, strings.TrimSpace(related.Summary)))
+			}
+		}
+		if comments := strings.TrimSpace(section.Comments); comments != "" {
+			prompt.WriteString(fmt.Sprintf("Comment Discussion:\n%s\n", comments))
+		}
+		if diff := strings.Trim(section.Diff, "\n"); diff != "" {
+			prompt.WriteString(fmt.Sprintf("Code Changes:\n```diff\n%s\n```\n", diff))
+		}
+	}
+	prompt.WriteString("\n")
+}
@@ -0,0 +1,134 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// WithStrictXML makes POML rendering fail on elements and attributes that are not
+// part of the POMLDocument schema, such as a misspelled <instuctions>, instead of
+// silently ignoring them
+func WithStrictXML() RenderOption {
+	return func(o *renderOptions) {
+		o.strictXML = true
+	}
+}
+
+// pomlSchemaNode describes the child elements and attributes allowed on an element
+type pomlSchemaNode struct {
+	children map[string]*pomlSchemaNode
+	attrs    map[string]bool
+}
+
+// pomlSchema is the element tree accepted by POMLDocument, derived from its XML tags
+var pomlSchema = buildPOMLSchema(reflect.TypeOf(POMLDocument{}))
+
+// buildPOMLSchema builds the schema node for a struct type from its xml struct tags.
+// Nested paths such as "instructions>requirement" become intermediate elements.
+func buildPOMLSchema(t reflect.Type) *pomlSchemaNode {
+	node := &pomlSchemaNode{children: map[string]*pomlSchemaNode{}, attrs: map[string]bool{}}
+	if t.Kind() != reflect.Struct {
+		return node
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("xml")
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "-" || t.Field(i).Name == "XMLName" {
+			continue
+		}
+		if flags == "attr" {
+			node.attrs[name] = true
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		fieldType := t.Field(i).Type
+		for fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		parent := node
+		path := strings.Split(name, ">")
+		for _, elem := range path[:len(path)-1] {
+			if parent.children[elem] == nil {
+				parent.children[elem] = &pomlSchemaNode{children: map[string]*pomlSchemaNode{}, attrs: map[string]bool{}}
+			}
+			parent = parent.children[elem]
+		}
+		parent.children[path[len(path)-1]] = buildPOMLSchema(fieldType)
+	}
+
+	return node
+}
+
+// UnknownPOMLElementsError lists the elements and attributes of a POML document
+// that are not recognized by the POMLDocument schema
+type UnknownPOMLElementsError struct {
+	Unknown []string
+}
+
+func (e *UnknownPOMLElementsError) Error() string {
+	return fmt.Sprintf("POML template has unknown elements or attributes: %s", strings.Join(e.Unknown, ", "))
+}
+
+// checkPOMLElements walks a rendered POML document and returns an
+// UnknownPOMLElementsError if it contains elements or attributes outside the schema.
+// Each unknown element is reported by its path, e.g. "poml/instuctions".
+func checkPOMLElements(document []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+
+	type frame struct {
+		node *pomlSchemaNode
+		path string
+	}
+	root := &pomlSchemaNode{children: map[string]*pomlSchemaNode{"poml": pomlSchema}}
+	stack := []frame{{node: root}}
+	var unknown []string
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse POML XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			path := strings.TrimPrefix(parent.path+"/"+t.Name.Local, "/")
+
+			node, ok := parent.node.children[t.Name.Local]
+			if !ok {
+				unknown = append(unknown, path)
+				if err := decoder.Skip(); err != nil {
+					return fmt.Errorf("failed to parse POML XML: %w", err)
+				}
+				continue
+			}
+
+			for _, attr := range t.Attr {
+				if !node.attrs[attr.Name.Local] {
+					unknown = append(unknown, fmt.Sprintf("%s@%s", path, attr.Name.Local))
+				}
+			}
+			stack = append(stack, frame{node: node, path: path})
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(unknown) > 0 {
+		return &UnknownPOMLElementsError{Unknown: unknown}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStrictXML(t *testing.T) {
+	tests := []struct {
+		name        string
+		poml        string
+		wantUnknown []string
+	}{
+		{
+			name: "known elements",
+			poml: `<poml><role>Engineer</role><task>Plan {{.Ticket.Key}}</task><instructions><requirement>Be brief</requirement></instructions></poml>`,
+		},
+		{
+			name:        "misspelled element",
+			poml:        `<poml><role>Engineer</role><instuctions><requirement>Be brief</requirement></instuctions></poml>`,
+			wantUnknown: []string{"poml/instuctions"},
+		},
+		{
+			name:        "misspelled attribute",
+			poml:        `<poml><context><section nme="ticket"><title>{{.Summary}}</title></section></context></poml>`,
+			wantUnknown: []string{"poml/context/section@nme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.poml")
+			if err := os.WriteFile(path, []byte(tt.poml), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Without strict mode unknown elements are ignored
+			if _, err := LoadAndRenderTemplate(path, SampleTicket()); err != nil {
+				t.Errorf("LoadAndRenderTemplate() error = %v", err)
+			}
+
+			_, err := LoadAndRenderTemplate(path, SampleTicket(), WithStrictXML())
+			if len(tt.wantUnknown) == 0 {
+				if err != nil {
+					t.Errorf("strict LoadAndRenderTemplate() error = %v", err)
+				}
+				return
+			}
+
+			var unknownErr *UnknownPOMLElementsError
+			if !errors.As(err, &unknownErr) {
+				t.Fatalf("strict LoadAndRenderTemplate() error = %v, want UnknownPOMLElementsError", err)
+			}
+			if !slices.Equal(unknownErr.Unknown, tt.wantUnknown) {
+				t.Errorf("Unknown = %q, want %q", unknownErr.Unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// remoteTemplateTTL is how long a fetched remote template is reused before refetching
+const remoteTemplateTTL = 5 * time.Minute
+
+// remoteHTTPClient fetches remote templates. It defaults to the Jira client's
+// default timeout and is replaced through SetHTTPClient.
+var remoteHTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// SetHTTPClient sets the client remote templates are fetched with, so they share
+// the transport (and so the proxy settings) and timeout configured for Jira
+func SetHTTPClient(client *http.Client) {
+	remoteCacheMu.Lock()
+	defer remoteCacheMu.Unlock()
+	remoteHTTPClient = client
+}
+
+// cachedTemplate holds a fetched remote template and when it was fetched
+type cachedTemplate struct {
+	content   []byte
+	fetchedAt time.Time
+}
+
+var (
+	remoteCacheMu sync.Mutex
+	remoteCache   = make(map[string]cachedTemplate)
+)
+
+// IsRemoteTemplate reports whether the template path is an http(s) URL
+func IsRemoteTemplate(templatePath string) bool {
+	lower := strings.ToLower(templatePath)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// readTemplate reads template content from a local file, a remote URL or the
+// templates embedded in the binary
+func readTemplate(templatePath string) ([]byte, error) {
+	if IsRemoteTemplate(templatePath) {
+		return fetchRemoteTemplate(templatePath)
+	}
+	if content, ok := readEmbeddedTemplate(templatePath); ok {
+		return content, nil
+	}
+
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file %s: %w", templatePath, err)
+	}
+
+	return content, nil
+}
+
+// fetchRemoteTemplate downloads a template over HTTP, reusing a cached copy
+// if it was fetched within remoteTemplateTTL. The cache isn't locked during the
+// download, so a slow server doesn't hold up other templates.
+func fetchRemoteTemplate(templateURL string) ([]byte, error) {
+	remoteCacheMu.Lock()
+	cached, ok := remoteCache[templateURL]
+	client := remoteHTTPClient
+	remoteCacheMu.Unlock()
+
+	if ok && time.Since(cached.fetchedAt) < remoteTemplateTTL {
+		return cached.content, nil
+	}
+
+	req, err := http.NewRequest("GET", templateURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for template %s: %w", templateURL, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template %s: %w", templateURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch template %s: unexpected status %d", templateURL, resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", templateURL, err)
+	}
+
+	if strings.TrimSpace(string(content)) == "" {
+		return nil, fmt.Errorf("template %s is empty", templateURL)
+	}
+
+	remoteCacheMu.Lock()
+	remoteCache[templateURL] = cachedTemplate{content: content, fetchedAt: time.Now()}
+	remoteCacheMu.Unlock()
+	return content, nil
+}
+
+// templateExtension returns the lowercased file extension of a template path,
+// ignoring any query string or fragment on remote URLs
+func templateExtension(templatePath string) string {
+	if IsRemoteTemplate(templatePath) {
+		if u, err := url.Parse(templatePath); err == nil {
+			return strings.ToLower(path.Ext(u.Path))
+		}
+	}
+
+	return strings.ToLower(filepath.Ext(templatePath))
+}
@@ -0,0 +1,75 @@
+package prompt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchRemoteTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "fetched", status: http.StatusOK, body: "Plan {{.Key}}"},
+		{name: "not found", status: http.StatusNotFound, wantErr: true},
+		{name: "empty", status: http.StatusOK, body: "  \n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+			original := remoteHTTPClient
+			SetHTTPClient(server.Client())
+			t.Cleanup(func() { SetHTTPClient(original) })
+
+			url := server.URL + "/prompts/plan.md"
+			for range 2 {
+				content, err := readTemplate(url)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("readTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && string(content) != tt.body {
+					t.Errorf("readTemplate() = %q, want %q", content, tt.body)
+				}
+			}
+
+			// Successful fetches are cached; failures are retried
+			wantRequests := int32(2)
+			if !tt.wantErr {
+				wantRequests = 1
+			}
+			if got := requests.Load(); got != wantRequests {
+				t.Errorf("requests = %d, want %d", got, wantRequests)
+			}
+		})
+	}
+}
+
+func TestTemplateExtension(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"prompts/plan.poml", ".poml"},
+		{"prompts/PLAN.MD", ".md"},
+		{"https://git.example.com/raw/plan.poml?ref=main", ".poml"},
+		{"https://git.example.com/raw/plan.md#section", ".md"},
+		{"https://git.example.com/raw/plan", ""},
+	}
+	for _, tt := range tests {
+		if got := templateExtension(tt.path); got != tt.want {
+			t.Errorf("templateExtension(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
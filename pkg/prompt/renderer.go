@@ -0,0 +1,143 @@
+package prompt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// templateFuncs are the functions available to every prompt template, in
+// addition to text/template's built-ins
+var templateFuncs = template.FuncMap{
+	"cdata": cdata,
+}
+
+// cdata makes text safe to place inside a CDATA section by splitting any "]]>"
+// across two sections, which XML parsers join back into the original text
+func cdata(text string) string {
+	return strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>")
+}
+
+// Renderer renders a prompt for a ticket
+type Renderer interface {
+	Render(ticket *jira.Ticket) (string, error)
+}
+
+// GoTemplateRenderer renders a markdown or plain text template with Go's text/template
+type GoTemplateRenderer struct {
+	Path    string
+	Options []RenderOption
+}
+
+// POMLRenderer renders a POML template and converts the result to a plain text prompt
+type POMLRenderer struct {
+	Path    string
+	Options []RenderOption
+}
+
+// NewRendererForPath returns the renderer for a template based on its extension,
+// defaulting to a GoTemplateRenderer for anything other than .poml. A directory or
+// glob of templates is rendered according to the extension of its main file.
+func NewRendererForPath(templatePath string, opts ...RenderOption) Renderer {
+	if renderedExtension(templatePath) == ".poml" {
+		return &POMLRenderer{Path: templatePath, Options: opts}
+	}
+	return &GoTemplateRenderer{Path: templatePath, Options: opts}
+}
+
+// Render loads the template and executes it with the ticket's data
+func (r *GoTemplateRenderer) Render(ticket *jira.Ticket) (string, error) {
+	options := newRenderOptions(r.Options)
+
+	rendered, err := executeTemplate(filepath.Base(r.Path), r.Path, ticket, options)
+	if err != nil {
+		return "", err
+	}
+
+	return wrapPrompt(rendered, options), nil
+}
+
+// Render loads the template, executes it with the ticket's data, and converts the
+// resulting POML document to a plain text prompt
+func (r *POMLRenderer) Render(ticket *jira.Ticket) (string, error) {
+	options := newRenderOptions(r.Options)
+
+	rendered, err := executeTemplate("poml", r.Path, ticket, options)
+	if err != nil {
+		return "", err
+	}
+
+	if options.strictXML {
+		if err := checkPOMLElements([]byte(rendered)); err != nil {
+			return "", err
+		}
+	}
+
+	// Parse the rendered POML XML
+	var pomlDoc POMLDocument
+	if err := xml.Unmarshal([]byte(rendered), &pomlDoc); err != nil {
+		return "", fmt.Errorf("failed to parse POML XML: %w", err)
+	}
+
+	return wrapPrompt(convertPOMLToPrompt(&pomlDoc, options.contextLast), options), nil
+}
+
+// executeTemplate reads a template, or composes a directory or glob of templates,
+// and executes it with the ticket's template data
+func executeTemplate(name, templatePath string, ticket *jira.Ticket, options renderOptions) (string, error) {
+	tmpl, err := parseTemplate(name, templatePath)
+	if err != nil {
+		return "", err
+	}
+	if options.missingKeyError {
+		tmpl.Option("missingkey=error")
+	}
+	if options.inspect != nil {
+		options.inspect(tmpl)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, createTemplateData(ticket, options)); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// parseTemplate parses a single template file or URL, or a template set
+func parseTemplate(name, templatePath string) (*template.Template, error) {
+	if isTemplateSet(templatePath) {
+		return loadTemplateSet(templatePath)
+	}
+
+	templateContent, err := readTemplate(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(templateContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderString executes text as a Go template with the ticket's template data, for
+// small templated snippets outside the prompt such as saved file headers
+func RenderString(name, text string, ticket *jira.Ticket, opts ...RenderOption) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, createTemplateData(ticket, newRenderOptions(opts))); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,193 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCDATA(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"]]>", "]]]]><![CDATA[>"},
+		{"a]]>b]]>c", "a]]]]><![CDATA[>b]]]]><![CDATA[>c"},
+		{"]] >", "]] >"},
+	}
+	for _, tt := range tests {
+		if got := cdata(tt.text); got != tt.want {
+			t.Errorf("cdata(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRenderDefaultTemplateWithCDATAEnd(t *testing.T) {
+	text := "+  <![CDATA[ if (a[b[0]]> 1) ]]>\n+  return x"
+	tests := []struct {
+		name    string
+		opts    []RenderOption
+		project string
+	}{
+		{name: "diff", opts: []RenderOption{WithDiff(text, 0)}},
+		{name: "comments", opts: []RenderOption{WithCommentSummary(text)}},
+		{name: "project", project: text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := SampleTicket()
+			ticket.Project.Description = tt.project
+			rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			if !strings.Contains(rendered, text) {
+				t.Errorf("rendered prompt doesn't contain the %s unchanged:\n%s", tt.name, rendered)
+			}
+		})
+	}
+}
+
+func TestRenderDefaultTemplateCustomFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]string
+		names  map[string]string
+		want   []string
+	}{
+		{name: "none"},
+		{
+			name:   "by display name",
+			fields: map[string]string{"customfield_10001": "Platform / Networking", "customfield_10002": "5"},
+			names:  map[string]string{"customfield_10001": "Team", "customfield_10002": "Story Points"},
+			want:   []string{"Team: Platform / Networking\n", "Story Points: 5\n"},
+		},
+		{
+			name:   "unknown name falls back to the ID",
+			fields: map[string]string{"customfield_10003": "a < b"},
+			want:   []string{"customfield_10003: a < b\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := SampleTicket()
+			ticket.CustomFields = tt.fields
+			ticket.CustomFieldNames = tt.names
+			rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(rendered, want) {
+					t.Errorf("rendered prompt doesn't contain %q:\n%s", want, rendered)
+				}
+			}
+			if len(tt.fields) == 0 && strings.Contains(rendered, "customfield_") {
+				t.Errorf("rendered prompt mentions custom fields:\n%s", rendered)
+			}
+		})
+	}
+}
+
+func TestRenderDefaultTemplateUsesTicketText(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []RenderOption
+	}{
+		{name: "full description"},
+		{name: "truncated description", opts: []RenderOption{WithMaxDescriptionChars(20)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := SampleTicket()
+			rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+
+			var options renderOptions
+			for _, opt := range tt.opts {
+				opt(&options)
+			}
+			want := strings.TrimSpace(createTemplateData(ticket, options).TicketText)
+			if !strings.Contains(rendered, want) {
+				t.Errorf("rendered prompt doesn't contain the ticket text\n%s\n\nprompt:\n%s", want, rendered)
+			}
+		})
+	}
+}
+
+func TestRenderDefaultTemplateRegressionRisk(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   bool
+	}{
+		{name: "regression", labels: []string{"regression"}, want: true},
+		{name: "not a regression", labels: []string{"reliability"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := SampleTicket()
+			ticket.Labels = tt.labels
+			if got := createTemplateData(ticket, renderOptions{}).IsRegression; got != tt.want {
+				t.Errorf("IsRegression = %v, want %v", got, tt.want)
+			}
+
+			rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			if got := strings.Contains(rendered, "Regression Risk section"); got != tt.want {
+				t.Errorf("prompt asks for a regression risk analysis = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRendererForPath(t *testing.T) {
+	const poml = `<poml><role>Engineer</role><task>Plan {{.Ticket.Key}}</task></poml>`
+	dir := t.TempDir()
+	for name, content := range map[string]string{"plan.tmpl": poml, "plan.md": poml, "plan.poml": poml, "PLAN.POML": poml} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		file     string
+		wantPOML bool
+	}{
+		{file: "plan.tmpl"},
+		{file: "plan.md"},
+		{file: "plan.poml", wantPOML: true},
+		{file: "PLAN.POML", wantPOML: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			renderer := NewRendererForPath(filepath.Join(dir, tt.file))
+			if _, isPOML := renderer.(*POMLRenderer); isPOML != tt.wantPOML {
+				t.Errorf("NewRendererForPath() = %T, want POML %v", renderer, tt.wantPOML)
+			}
+
+			// POML is converted to a plain prompt; other templates are passed through
+			rendered, err := renderer.Render(SampleTicket())
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := strings.Contains(rendered, "<poml>"); got == tt.wantPOML {
+				t.Errorf("rendered = %q, want POML markup kept %v", rendered, !tt.wantPOML)
+			}
+			if !strings.Contains(rendered, "Plan DEMO-123") {
+				t.Errorf("rendered = %q, want the task rendered", rendered)
+			}
+		})
+	}
+}
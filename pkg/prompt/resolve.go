@@ -0,0 +1,87 @@
+package prompt
+
+import (
+	"os"
+	"strings"
+
+	"github.com/joshbranham/jira-implementation-generator/prompts"
+)
+
+// EmbeddedTemplatePath is the path of the default template compiled into the binary,
+// used when no template is configured and none exists in the working directory
+const EmbeddedTemplatePath = "embedded:implementation-plan.poml"
+
+// EmbeddedStructuredTemplatePath is the embedded template asking for the plan as JSON,
+// used with --structured when no template is given
+const EmbeddedStructuredTemplatePath = "embedded:structured-plan.md"
+
+// EmbeddedExplainTemplatePath is the embedded template used by the explain
+// subcommand to summarize a ticket without planning it
+const EmbeddedExplainTemplatePath = "embedded:explain.md"
+
+// embeddedPrefix marks template paths that refer to templates compiled into the binary
+const embeddedPrefix = "embedded:"
+
+// embeddedTemplates maps embedded template names to their content
+var embeddedTemplates = map[string]string{
+	"implementation-plan.poml": prompts.ImplementationPlanPOML,
+	"structured-plan.md":       prompts.StructuredPlanMarkdown,
+	"explain.md":               prompts.ExplainMarkdown,
+}
+
+// TemplateEnvVar is the environment variable consulted for a template path when --template isn't set
+const TemplateEnvVar = "JIG_TEMPLATE"
+
+// Template sources reported by ResolveTemplatePath
+const (
+	SourceFlag     = "--template flag"
+	SourceEnv      = "$" + TemplateEnvVar
+	SourceLocal    = "working directory"
+	SourceEmbedded = "embedded default"
+)
+
+// localTemplatePaths are the default templates looked for in the working directory, in order
+var localTemplatePaths = []string{
+	"prompts/implementation-plan.poml",
+	"prompts/implementation-plan.md",
+}
+
+// ResolveTemplatePath picks the template to render, in order: the given flag value,
+// the JIG_TEMPLATE environment variable, a default template in the working
+// directory's prompts/ folder, and finally the default template embedded in the
+// binary. It returns the path and a description of the source it came from.
+func ResolveTemplatePath(flag string) (path, source string) {
+	return resolveTemplatePath(flag, os.Getenv, fileExists)
+}
+
+// resolveTemplatePath implements ResolveTemplatePath with injectable lookups for tests
+func resolveTemplatePath(flag string, getenv func(string) string, exists func(string) bool) (string, string) {
+	if flag != "" {
+		return flag, SourceFlag
+	}
+	if env := strings.TrimSpace(getenv(TemplateEnvVar)); env != "" {
+		return env, SourceEnv
+	}
+	for _, path := range localTemplatePaths {
+		if exists(path) {
+			return path, SourceLocal
+		}
+	}
+	return EmbeddedTemplatePath, SourceEmbedded
+}
+
+// fileExists reports whether path is an existing regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// readEmbeddedTemplate returns the content of an embedded template path
+func readEmbeddedTemplate(templatePath string) ([]byte, bool) {
+	name, ok := strings.CutPrefix(templatePath, embeddedPrefix)
+	if !ok {
+		return nil, false
+	}
+	content, ok := embeddedTemplates[name]
+	return []byte(content), ok
+}
@@ -0,0 +1,41 @@
+package prompt
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolveTemplatePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       string
+		env        string
+		local      []string
+		wantPath   string
+		wantSource string
+	}{
+		{name: "flag wins", flag: "custom.md", env: "env.md", local: localTemplatePaths, wantPath: "custom.md", wantSource: SourceFlag},
+		{name: "env without flag", env: " env.md ", local: localTemplatePaths, wantPath: "env.md", wantSource: SourceEnv},
+		{name: "local POML template", local: localTemplatePaths, wantPath: "prompts/implementation-plan.poml", wantSource: SourceLocal},
+		{name: "local markdown template", local: []string{"prompts/implementation-plan.md"}, wantPath: "prompts/implementation-plan.md", wantSource: SourceLocal},
+		{name: "blank env ignored", env: "  ", wantPath: EmbeddedTemplatePath, wantSource: SourceEmbedded},
+		{name: "embedded when nothing else is set", wantPath: EmbeddedTemplatePath, wantSource: SourceEmbedded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string {
+				if key != TemplateEnvVar {
+					t.Errorf("getenv(%q), want %s", key, TemplateEnvVar)
+				}
+				return tt.env
+			}
+			exists := func(path string) bool { return slices.Contains(tt.local, path) }
+
+			path, source := resolveTemplatePath(tt.flag, getenv, exists)
+			if path != tt.wantPath || source != tt.wantSource {
+				t.Errorf("resolveTemplatePath() = %q, %q; want %q, %q", path, source, tt.wantPath, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package prompt
+
+import (
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// SampleTicket returns a ticket fixture for testing templates without a live Jira,
+// e.g. by rendering it with LoadAndRenderTemplate in a go test. Every optional
+// field is populated, including components with and without leads, labels, a
+// sprint and an epic, except the assignee, which is nil to exercise the
+// unassigned case. Each call returns a new ticket that may be freely modified.
+func SampleTicket() *jira.Ticket {
+	return &jira.Ticket{
+		ID:          "10001",
+		Key:         "DEMO-123",
+		Summary:     "Add retry support to the export job",
+		Description: "The nightly export job fails permanently on transient network errors.\n\nAcceptance criteria:\n* Failed uploads are retried with backoff\n* Retries are logged",
+		Environment: "RHEL 9.4, x86_64",
+		Status:      jira.Status{ID: "1", Name: "New", Category: jira.StatusCategoryNew},
+		IssueType:   jira.IssueType{ID: "3", Name: "Story"},
+		Priority:    jira.Priority{ID: "3", Name: "Major"},
+		Reporter: &jira.User{
+			AccountID:    "557058:reporter",
+			Name:         "rreporter",
+			DisplayName:  "Riley Reporter",
+			EmailAddress: "rreporter@example.com",
+			TimeZone:     "America/New_York",
+		},
+		Created: time.Date(2025, time.January, 6, 9, 30, 0, 0, time.UTC),
+		Updated: time.Date(2025, time.January, 8, 14, 0, 0, 0, time.UTC),
+		DueDate: time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC),
+		Labels:  []string{"reliability", "export"},
+		Votes:   7,
+		Components: []jira.Component{
+			{
+				ID:          "200",
+				Name:        "Export",
+				Description: "Scheduled data exports",
+				Lead: &jira.User{
+					AccountID:   "557058:lead",
+					Name:        "llead",
+					DisplayName: "Lee Lead",
+				},
+			},
+			{ID: "201", Name: "Storage"},
+		},
+		Project: jira.Project{ID: "100", Key: "DEMO", Name: "Demo Project"},
+		Sprints: []jira.Sprint{
+			{
+				ID:        42,
+				Name:      "Demo Sprint 7",
+				State:     "active",
+				StartDate: time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		Epic: "DEMO-100",
+	}
+}
@@ -0,0 +1,59 @@
+package prompt_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+// TestRenderCustomTemplateWithSampleTicket renders a user template against the
+// sample fixture the way a template author would, from outside the package
+func TestRenderCustomTemplateWithSampleTicket(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "summary", template: "{{.Ticket.Key}}: {{.Summary}}", want: "DEMO-123: Add retry support to the export job"},
+		{name: "nil assignee", template: "Assignee: {{.Assignee}}", want: "Assignee: Unassigned"},
+		{name: "labels", template: "Labels: {{.Labels}}", want: "Labels: reliability, export"},
+		{name: "components", template: "Components: {{.Components}}", want: "Components: Export (Lead: Lee Lead), Storage"},
+		{
+			name:     "component leads",
+			template: "{{range .Ticket.Components}}{{.Name}}={{if .Lead}}{{.Lead.DisplayName}}{{else}}none{{end}};{{end}}",
+			want:     "Export=Lee Lead;Storage=none;",
+		},
+		{name: "sprint and epic", template: "{{.Sprint}} / {{.Ticket.Epic}}", want: "Demo Sprint 7 (2025-01-06 to 2025-01-20) / DEMO-100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.md")
+			if err := os.WriteFile(path, []byte(tt.template), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rendered, err := prompt.LoadAndRenderTemplate(path, prompt.SampleTicket())
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			if !strings.Contains(rendered, tt.want) {
+				t.Errorf("rendered = %q, want it to contain %q", rendered, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleTicketIsIndependent(t *testing.T) {
+	first := prompt.SampleTicket()
+	first.Labels[0] = "changed"
+	first.Components[0].Lead.DisplayName = "changed"
+
+	second := prompt.SampleTicket()
+	if second.Labels[0] != "reliability" || second.Components[0].Lead.DisplayName != "Lee Lead" {
+		t.Errorf("SampleTicket() shares state between calls: %+v", second)
+	}
+}
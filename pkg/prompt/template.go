@@ -1,95 +1,268 @@
 package prompt
 
 import (
-	"bytes"
-	"fmt"
-	"os"
-	"path/filepath"
+	"cmp"
+	"slices"
 	"strings"
 	"text/template"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
 )
 
 // TemplateData holds the data for prompt template rendering
 type TemplateData struct {
-	Summary     string
-	Description string
-	Status      string
-	IssueType   string
-	Priority    string
-	Components  string
-	Labels      string
-	Assignee    string
-	Reporter    string
+	Summary          string
+	Description      string
+	Environment      string
+	Status           string
+	IssueType        string
+	Priority         string
+	Components       string
+	Labels           string
+	Assignee         string
+	AssigneeTimeZone string
+	Reporter         string
+	Sprint           string
+	Votes            int
+	Language         string
+	Diff             string
+
+	// AffectsVersions and FixVersions are the comma-separated version names, and
+	// IsRegression reports whether the ticket looks like a regression (see
+	// jira.Ticket.IsRegression)
+	AffectsVersions string
+	FixVersions     string
+	IsRegression    bool
+
+	// ProjectDescription and ProjectLead describe the ticket's project (with --fetch-project)
+	ProjectDescription string
+	ProjectLead        string
+
+	// Vars holds ad-hoc variables from --var, e.g. {{.Vars.team}}
+	Vars map[string]string
+
+	// CommentSummary condenses the ticket's comment thread (with --summarize-comments)
+	CommentSummary string
+
+	// RelatedTickets are other tickets included for context, e.g. siblings in the same cluster
+	RelatedTickets []RelatedTicket
+
+	// CustomFields holds the flattened custom field values requested with
+	// --custom-fields, keyed by field ID, e.g. {{index .CustomFields "customfield_10001"}}
+	CustomFields map[string]string
+
+	// NamedCustomFields lists the same values with their display names, sorted by name
+	NamedCustomFields []CustomField
+
+	// TicketText is the ticket's canonical text representation (see jira.Ticket.MarshalForPrompt)
+	TicketText string
+
+	// Ticket is the whole parsed ticket, for fields without a flat equivalent above,
+	// e.g. {{.Ticket.Project.Key}} or {{.Ticket.Created}}. Its description is
+	// truncated like {{.Description}}.
+	Ticket *jira.Ticket
 }
 
-// LoadAndRenderTemplate loads a prompt template and renders it with ticket data
-// Supports both markdown (.md) and POML (.poml) formats
-func LoadAndRenderTemplate(templatePath string, ticket *jira.Ticket) (string, error) {
-	// Determine format based on file extension
-	if strings.HasSuffix(strings.ToLower(templatePath), ".poml") {
-		return LoadAndRenderPOMLTemplate(templatePath, ticket)
+// RelatedTicket is the key, summary and status of a ticket included for context
+type RelatedTicket struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+// CustomField is a custom field's display name (its ID when the name is unknown) and value
+type CustomField struct {
+	ID    string
+	Name  string
+	Value string
+}
+
+// truncatedMarker is appended to text that was shortened before being sent to the LLM
+const truncatedMarker = "[truncated]"
+
+// RenderOption configures how ticket data is prepared for template rendering
+type RenderOption func(*renderOptions)
+
+// renderOptions holds the settings applied by RenderOptions
+type renderOptions struct {
+	maxDescriptionChars int
+	language            string
+	prefix              string
+	suffix              string
+	diff                string
+	maxDiffChars        int
+	strictXML           bool
+	relatedTickets      []RelatedTicket
+	vars                map[string]string
+	commentSummary      string
+	missingKeyError     bool
+	contextLast         bool
+	inspect             func(*template.Template)
+}
+
+// WithMaxDescriptionChars limits the ticket description passed to the template to
+// the given number of characters, marking it as truncated. Zero means unlimited.
+func WithMaxDescriptionChars(maxChars int) RenderOption {
+	return func(o *renderOptions) {
+		o.maxDescriptionChars = maxChars
+	}
+}
+
+// WithLanguage sets the language the plan should be written in, exposed to
+// templates as {{.Language}}. An empty language leaves the template's default.
+func WithLanguage(language string) RenderOption {
+	return func(o *renderOptions) {
+		o.language = language
+	}
+}
+
+// WithPromptPrefix adds text before the rendered prompt, separated by a blank line
+func WithPromptPrefix(prefix string) RenderOption {
+	return func(o *renderOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithPromptSuffix adds text after the rendered prompt, separated by a blank line
+func WithPromptSuffix(suffix string) RenderOption {
+	return func(o *renderOptions) {
+		o.suffix = suffix
+	}
+}
+
+// WithDiff provides a unified diff of related code changes, exposed to templates
+// as {{.Diff}} and truncated to maxChars characters (zero means unlimited)
+func WithDiff(diff string, maxChars int) RenderOption {
+	return func(o *renderOptions) {
+		o.diff = diff
+		o.maxDiffChars = maxChars
 	}
+}
 
-	// Default to markdown format
-	return loadAndRenderMarkdownTemplate(templatePath, ticket)
+// WithRelatedTickets provides other tickets for context, exposed to templates as
+// {{.RelatedTickets}}. Tickets are included in order until their keys, summaries
+// and statuses would exceed maxChars characters in total (zero means unlimited).
+// The ticket being rendered is always left out of its own related tickets.
+func WithRelatedTickets(tickets []*jira.Ticket, maxChars int) RenderOption {
+	return func(o *renderOptions) {
+		o.relatedTickets = nil
+		total := 0
+		for _, ticket := range tickets {
+			related := RelatedTicket{Key: ticket.Key, Summary: ticket.Summary, Status: ticket.Status.Name}
+			total += utf8.RuneCountInString(related.Key + related.Summary + related.Status)
+			if maxChars > 0 && total > maxChars {
+				break
+			}
+			o.relatedTickets = append(o.relatedTickets, related)
+		}
+	}
 }
 
-// loadAndRenderMarkdownTemplate loads a markdown template and renders it with ticket data
-func loadAndRenderMarkdownTemplate(templatePath string, ticket *jira.Ticket) (string, error) {
-	// Read the template file
-	templateContent, err := os.ReadFile(templatePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read template file %s: %w", templatePath, err)
+// WithVars provides ad-hoc template variables, exposed to templates as {{.Vars.name}}
+func WithVars(vars map[string]string) RenderOption {
+	return func(o *renderOptions) {
+		o.vars = vars
 	}
+}
 
-	// Create template data from ticket
-	data := createTemplateData(ticket)
+// WithCommentSummary provides a condensed comment thread, exposed to templates as {{.CommentSummary}}
+func WithCommentSummary(summary string) RenderOption {
+	return func(o *renderOptions) {
+		o.commentSummary = summary
+	}
+}
 
-	// Parse and execute template
-	tmpl, err := template.New(filepath.Base(templatePath)).Parse(string(templateContent))
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+// newRenderOptions applies the given options over the defaults
+func newRenderOptions(opts []RenderOption) renderOptions {
+	var options renderOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
+	return options
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+// LoadAndRenderTemplate loads a prompt template and renders it with ticket data
+// Supports both markdown (.md) and POML (.poml) formats, from a local file or an http(s) URL.
+// A directory or glob of templates is composed with MergeTemplates and its main file rendered.
+func LoadAndRenderTemplate(templatePath string, ticket *jira.Ticket, opts ...RenderOption) (string, error) {
+	return NewRendererForPath(templatePath, opts...).Render(ticket)
+}
+
+// wrapPrompt adds the configured prefix and suffix around a rendered prompt,
+// trimming each part and separating them with blank lines
+func wrapPrompt(rendered string, options renderOptions) string {
+	prefix := strings.TrimSpace(options.prefix)
+	suffix := strings.TrimSpace(options.suffix)
+	if prefix == "" && suffix == "" {
+		return rendered
 	}
 
-	return buf.String(), nil
+	parts := []string{strings.TrimSpace(rendered)}
+	if prefix != "" {
+		parts = append([]string{prefix}, parts...)
+	}
+	if suffix != "" {
+		parts = append(parts, suffix)
+	}
+	return strings.Join(parts, "\n\n")
 }
 
 // createTemplateData converts a Jira ticket to template data
-func createTemplateData(ticket *jira.Ticket) TemplateData {
+func createTemplateData(ticket *jira.Ticket, options renderOptions) TemplateData {
 	data := TemplateData{
 		Summary:     ticket.Summary,
-		Description: ticket.Description,
+		Description: truncateRunes(ticket.Description, options.maxDescriptionChars),
+		Environment: ticket.Environment,
 		Status:      ticket.Status.Name,
 		IssueType:   ticket.IssueType.Name,
 		Priority:    ticket.Priority.Name,
-		Reporter:    ticket.Reporter.DisplayName,
+		Assignee:    ticket.AssigneeName(),
+		Reporter:    ticket.ReporterName(),
+		Votes:       ticket.Votes,
+		Language:    options.language,
+		Diff:        truncateRunes(options.diff, options.maxDiffChars),
+
+		CustomFields:   ticket.CustomFields,
+		Vars:           options.vars,
+		CommentSummary: strings.TrimSpace(options.commentSummary),
+	}
+
+	// Handle related tickets, leaving out the ticket itself
+	for _, related := range options.relatedTickets {
+		if related.Key != ticket.Key {
+			data.RelatedTickets = append(data.RelatedTickets, related)
+		}
+	}
+
+	// Handle custom fields, by display name
+	for id, value := range ticket.CustomFields {
+		data.NamedCustomFields = append(data.NamedCustomFields, CustomField{ID: id, Name: ticket.CustomFieldName(id), Value: value})
 	}
+	slices.SortFunc(data.NamedCustomFields, func(a, b CustomField) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
 
-	// Handle assignee (may be nil)
+	// Handle assignee time zone (only known with WithFetchUserTimezone or when Jira includes it)
 	if ticket.Assignee != nil {
-		data.Assignee = ticket.Assignee.DisplayName
-	} else {
-		data.Assignee = "Unassigned"
+		data.AssigneeTimeZone = ticket.Assignee.TimeZone
+	}
+
+	// Handle versions and the regression heuristic
+	data.AffectsVersions = versionNames(ticket.AffectsVersions)
+	data.FixVersions = versionNames(ticket.FixVersions)
+	data.IsRegression = ticket.IsRegression()
+
+	// Handle project details (only known with WithFetchProject)
+	data.ProjectDescription = strings.TrimSpace(ticket.Project.Description)
+	if ticket.Project.Lead != nil {
+		data.ProjectLead = ticket.Project.Lead.DisplayName
 	}
 
 	// Handle components
 	if len(ticket.Components) > 0 {
-		var compNames []string
-		for _, comp := range ticket.Components {
-			if comp.Lead != nil {
-				compNames = append(compNames, fmt.Sprintf("%s (Lead: %s)", comp.Name, comp.Lead.DisplayName))
-			} else {
-				compNames = append(compNames, comp.Name)
-			}
-		}
-		data.Components = strings.Join(compNames, ", ")
+		data.Components = ticket.ComponentNames()
 	}
 
 	// Handle labels
@@ -97,9 +270,44 @@ func createTemplateData(ticket *jira.Ticket) TemplateData {
 		data.Labels = strings.Join(ticket.Labels, ", ")
 	}
 
+	// Handle active sprint
+	if sprint := ticket.ActiveSprint(); sprint != nil {
+		data.Sprint = sprint.Describe()
+	}
+
+	// The canonical representation uses the same (possibly truncated) description
+	canonical := *ticket
+	canonical.Description = data.Description
+	data.TicketText = canonical.MarshalForPrompt()
+	data.Ticket = &canonical
+
 	return data
 }
 
+// versionNames joins the names of versions with commas
+func versionNames(versions []jira.Version) string {
+	names := make([]string, len(versions))
+	for i, version := range versions {
+		names[i] = version.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// truncateRunes shortens text to at most maxChars runes followed by a truncation
+// marker. A maxChars of zero or less leaves the text unchanged.
+func truncateRunes(text string, maxChars int) string {
+	if maxChars <= 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+
+	return strings.TrimRightFunc(string(runes[:maxChars]), unicode.IsSpace) + "\n\n" + truncatedMarker
+}
+
 // GetDefaultTemplatePath returns the default template path
 func GetDefaultTemplatePath() string {
 	return "prompts/implementation-plan.poml"
@@ -0,0 +1,196 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestRenderTruncatedDescription(t *testing.T) {
+	description := strings.Repeat("word ", 50) + "TAIL"
+	tests := []struct {
+		name          string
+		maxChars      int
+		wantTruncated bool
+	}{
+		{name: "unlimited", maxChars: 0},
+		{name: "under the limit", maxChars: len(description)},
+		{name: "over the limit", maxChars: 40, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := SampleTicket()
+			ticket.Description = description
+			rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket, WithMaxDescriptionChars(tt.maxChars))
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+
+			if got := strings.Contains(rendered, truncatedMarker); got != tt.wantTruncated {
+				t.Errorf("rendered prompt contains %s = %v, want %v", truncatedMarker, got, tt.wantTruncated)
+			}
+			if got := strings.Contains(rendered, "TAIL"); got == tt.wantTruncated {
+				t.Errorf("rendered prompt contains the end of the description = %v, want %v", got, !tt.wantTruncated)
+			}
+			if want := strings.Repeat("word ", 7) + "word"; !strings.Contains(rendered, want) {
+				t.Errorf("rendered prompt doesn't contain the start of the description %q", want)
+			}
+		})
+	}
+}
+
+func TestRenderLanguage(t *testing.T) {
+	templates := []string{EmbeddedTemplatePath, EmbeddedExplainTemplatePath, EmbeddedStructuredTemplatePath}
+	tests := []struct {
+		name     string
+		language string
+		want     bool
+	}{
+		{name: "language set", language: "German", want: true},
+		{name: "default language"},
+	}
+
+	for _, tt := range tests {
+		for _, templatePath := range templates {
+			t.Run(tt.name+" "+templatePath, func(t *testing.T) {
+				rendered, err := LoadAndRenderTemplate(templatePath, SampleTicket(), WithLanguage(tt.language))
+				if err != nil {
+					t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+				}
+				if got := strings.Contains(rendered, " in German"); got != tt.want {
+					t.Errorf("rendered prompt has the German instruction = %v, want %v", got, tt.want)
+				}
+				if tt.language == "" && strings.Contains(rendered, "entirely in") {
+					t.Error("rendered prompt has a language instruction without a language")
+				}
+			})
+		}
+	}
+}
+
+func TestRenderPromptPrefixSuffix(t *testing.T) {
+	const body = "Plan {{.Ticket.Key}}"
+	tests := []struct {
+		name   string
+		prefix string
+		suffix string
+		want   string
+	}{
+		{name: "none", want: "Plan DEMO-123\n"},
+		{name: "prefix", prefix: "You work on the storage team.\n", want: "You work on the storage team.\n\nPlan DEMO-123"},
+		{name: "suffix", suffix: "  Keep it short.", want: "Plan DEMO-123\n\nKeep it short."},
+		{name: "both", prefix: "Before", suffix: "After", want: "Before\n\nPlan DEMO-123\n\nAfter"},
+		{name: "blank prefix ignored", prefix: " \n", want: "Plan DEMO-123\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.md")
+			if err := os.WriteFile(path, []byte(body+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rendered, err := LoadAndRenderTemplate(path, SampleTicket(), WithPromptPrefix(tt.prefix), WithPromptSuffix(tt.suffix))
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			if rendered != tt.want {
+				t.Errorf("rendered = %q, want %q", rendered, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPOMLPromptPrefixSuffix(t *testing.T) {
+	rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, SampleTicket(), WithPromptPrefix("PREFIX"), WithPromptSuffix("SUFFIX"))
+	if err != nil {
+		t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+	}
+	if !strings.HasPrefix(rendered, "PREFIX\n\n") || !strings.HasSuffix(rendered, "\n\nSUFFIX") {
+		t.Errorf("rendered = %q, want it wrapped in the prefix and suffix", rendered)
+	}
+}
+
+func TestRenderRelatedTickets(t *testing.T) {
+	ticket := SampleTicket()
+	related := func(key, summary string) *jira.Ticket {
+		return &jira.Ticket{Key: key, Summary: summary, Status: jira.Status{Name: "In Progress"}}
+	}
+	// The ticket being rendered is left out of its own related tickets
+	tickets := []*jira.Ticket{related("DEMO-124", "Rotate the signing keys"), ticket, related("DEMO-125", "Expire old sessions")}
+
+	tests := []struct {
+		name     string
+		maxChars int
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:    "unlimited",
+			want:    []string{"- DEMO-124 [In Progress]: Rotate the signing keys", "- DEMO-125 [In Progress]: Expire old sessions"},
+			notWant: []string{"- DEMO-123 ["},
+		},
+		{
+			name:     "capped",
+			maxChars: 50,
+			want:     []string{"- DEMO-124 [In Progress]: Rotate the signing keys"},
+			notWant:  []string{"DEMO-125", "Expire old sessions"},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, templatePath := range []string{EmbeddedTemplatePath, EmbeddedStructuredTemplatePath} {
+			t.Run(tt.name+" "+templatePath, func(t *testing.T) {
+				rendered, err := LoadAndRenderTemplate(templatePath, ticket, WithRelatedTickets(tickets, tt.maxChars))
+				if err != nil {
+					t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+				}
+				for _, want := range tt.want {
+					if !strings.Contains(rendered, want) {
+						t.Errorf("rendered prompt doesn't contain %q", want)
+					}
+				}
+				for _, notWant := range tt.notWant {
+					if strings.Contains(rendered, notWant) {
+						t.Errorf("rendered prompt contains %q", notWant)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRenderTicketPassthrough(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		opts []RenderOption
+		want string
+	}{
+		{name: "project key", body: "{{ .Ticket.Project.Key }}", want: "DEMO"},
+		{name: "project name", body: "{{ .Ticket.Project.Name }}", want: "Demo Project"},
+		{name: "flat fields kept", body: "{{ eq .Summary .Ticket.Summary }}", want: "true"},
+		{name: "description truncated", body: "{{ .Ticket.Description }}", opts: []RenderOption{WithMaxDescriptionChars(5)}, want: truncateRunes(SampleTicket().Description, 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.md")
+			if err := os.WriteFile(path, []byte(tt.body), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rendered, err := LoadAndRenderTemplate(path, SampleTicket(), tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+			}
+			if rendered != tt.want {
+				t.Errorf("rendered = %q, want %q", rendered, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ContextWindowTokens is the context limit assumed for models missing from modelContextWindows
+	ContextWindowTokens = 200000
+
+	// charsPerToken is the average number of characters per token in English prose
+	charsPerToken = 4
+
+	// contextWarningRatio is the fraction of the context window at which NearContextLimit reports true
+	contextWarningRatio = 0.8
+)
+
+// EstimateTokens returns a rough estimate of the number of tokens in text, using
+// the common heuristic of four characters per token. It is intended for cost and
+// context-limit sanity checks, not exact accounting.
+func EstimateTokens(text string) int {
+	chars := utf8.RuneCountInString(text)
+	return (chars + charsPerToken - 1) / charsPerToken
+}
+
+// NearContextLimit reports whether an estimated token count is close to the model's context window
+func NearContextLimit(tokens int) bool {
+	return float64(tokens) >= contextWarningRatio*ContextWindowTokens
+}
+
+// modelContextWindows holds the context limits of known models, keyed by model
+// name without the Vertex AI version suffix (e.g. "claude-sonnet-4" for
+// "claude-sonnet-4@20250514")
+var modelContextWindows = map[string]int{
+	"claude-opus-4-1":      200000,
+	"claude-opus-4":        200000,
+	"claude-sonnet-4":      200000,
+	"claude-3-7-sonnet":    200000,
+	"claude-3-5-sonnet-v2": 200000,
+	"claude-3-5-sonnet":    200000,
+	"claude-3-5-haiku":     200000,
+	"claude-3-opus":        200000,
+	"claude-3-haiku":       200000,
+}
+
+// ContextWindow returns the context limit of a model in tokens, falling back to
+// ContextWindowTokens for unknown models
+func ContextWindow(model string) int {
+	name, _, _ := strings.Cut(model, "@")
+	if limit, ok := modelContextWindows[name]; ok {
+		return limit
+	}
+	return ContextWindowTokens
+}
+
+// PromptTooLargeError reports a prompt whose estimated size, plus the tokens
+// reserved for the response, exceeds the model's context window
+type PromptTooLargeError struct {
+	Model        string
+	PromptTokens int
+	MaxTokens    int
+	Limit        int
+}
+
+func (e *PromptTooLargeError) Error() string {
+	return fmt.Sprintf("prompt is ~%d tokens, more than the %d tokens %s can accept with %d reserved for the response",
+		e.PromptTokens, e.Limit-e.MaxTokens, e.Model, e.MaxTokens)
+}
+
+// CheckPromptSize returns a PromptTooLargeError if a prompt of the estimated size
+// would not fit in the model's context window alongside maxTokens of output
+func CheckPromptSize(model string, promptTokens, maxTokens int) error {
+	limit := ContextWindow(model)
+	if promptTokens+maxTokens > limit {
+		return &PromptTooLargeError{Model: model, PromptTokens: promptTokens, MaxTokens: maxTokens, Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckPromptSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		promptTokens int
+		maxTokens    int
+		wantErr      bool
+	}{
+		{name: "fits", model: "claude-sonnet-4@20250514", promptTokens: 150000, maxTokens: 16000},
+		{name: "exactly the window", model: "claude-sonnet-4@20250514", promptTokens: 184000, maxTokens: 16000},
+		{name: "oversized for a known model", model: "claude-sonnet-4@20250514", promptTokens: 190000, maxTokens: 16000, wantErr: true},
+		{name: "oversized for an unknown model", model: "claude-future", promptTokens: ContextWindowTokens, maxTokens: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPromptSize(tt.model, tt.promptTokens, tt.maxTokens)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckPromptSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			var tooLarge *PromptTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("CheckPromptSize() error = %T, want *PromptTooLargeError", err)
+			}
+			if tooLarge.Limit != ContextWindow(tt.model) || tooLarge.PromptTokens != tt.promptTokens {
+				t.Errorf("PromptTooLargeError = %+v, want limit %d and %d prompt tokens", tooLarge, ContextWindow(tt.model), tt.promptTokens)
+			}
+			if !strings.Contains(err.Error(), tt.model) {
+				t.Errorf("error %q doesn't name the model", err)
+			}
+		})
+	}
+}
+
+func TestCheckPromptSizeRenderedPrompt(t *testing.T) {
+	ticket := SampleTicket()
+	ticket.Description = strings.Repeat("A very long description. ", 40000)
+	rendered, err := LoadAndRenderTemplate(EmbeddedTemplatePath, ticket)
+	if err != nil {
+		t.Fatalf("LoadAndRenderTemplate() error = %v", err)
+	}
+
+	if err := CheckPromptSize("claude-sonnet-4@20250514", EstimateTokens(rendered), 16000); err == nil {
+		t.Errorf("CheckPromptSize() accepted a ~%d token prompt", EstimateTokens(rendered))
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "abcd", want: 1},
+		{text: "abcde", want: 2},
+		{text: "héllo wörld", want: 3}, // counted in runes, not bytes
+		{text: strings.Repeat("a", 4000), want: 1000},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%.20q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateTokensProse(t *testing.T) {
+	// English prose averages about 0.75 words per token; the estimate should be
+	// within a third of that
+	prose := strings.Repeat("The export job retries failed uploads with exponential backoff and logs each attempt. ", 50)
+	words := len(strings.Fields(prose))
+	expected := float64(words) / 0.75
+	got := float64(EstimateTokens(prose))
+	if got < expected*2/3 || got > expected*4/3 {
+		t.Errorf("EstimateTokens() = %.0f for %d words, want about %.0f", got, words, expected)
+	}
+}
+
+func TestNearContextLimit(t *testing.T) {
+	tests := []struct {
+		tokens int
+		want   bool
+	}{
+		{tokens: 1000},
+		{tokens: ContextWindowTokens*8/10 - 1},
+		{tokens: ContextWindowTokens * 8 / 10, want: true},
+		{tokens: ContextWindowTokens, want: true},
+	}
+	for _, tt := range tests {
+		if got := NearContextLimit(tt.tokens); got != tt.want {
+			t.Errorf("NearContextLimit(%d) = %v, want %v", tt.tokens, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Package telemetry defines hooks for recording metrics about plan generation,
+// such as plans generated, token usage and failures, without tying the
+// generator to any particular metrics library
+package telemetry
+
+import (
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// Usage describes the model usage of a single generated plan
+type Usage struct {
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	Duration     time.Duration
+}
+
+// Observer receives events at key points of a run. Implementations can forward
+// them to Prometheus, statsd or any other metrics system, and must be safe to
+// call from the goroutine running the generator.
+type Observer interface {
+	// OnTicketFetched is called after a ticket has been fetched and validated
+	OnTicketFetched(ticket *jira.Ticket)
+
+	// OnPlanGenerated is called after the model returns a plan for a ticket
+	OnPlanGenerated(ticketKey string, usage Usage)
+
+	// OnError is called when fetching a ticket or generating its plan fails.
+	// The ticket key may be empty for errors not tied to a single ticket.
+	OnError(ticketKey string, err error)
+}
+
+// NopObserver is an Observer that ignores all events; it is the default
+type NopObserver struct{}
+
+// OnTicketFetched implements Observer
+func (NopObserver) OnTicketFetched(ticket *jira.Ticket) {}
+
+// OnPlanGenerated implements Observer
+func (NopObserver) OnPlanGenerated(ticketKey string, usage Usage) {}
+
+// OnError implements Observer
+func (NopObserver) OnError(ticketKey string, err error) {}
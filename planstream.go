@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// incompleteFooter ends an --output file whose streamed plan failed part way through
+const incompleteFooter = "\n\n---\n\n*[incomplete] Generation failed before the plan was finished.*\n"
+
+// planWriter writes a markdown plan file incrementally: the file branding header and
+// metadata header first, then the plan as it arrives, then the branding footer. The
+// result matches brandPlanContent, and the file is synced once, when it is ended.
+type planWriter struct {
+	header string
+	footer string
+
+	path string
+	file *os.File
+	err  error
+
+	// newlines counts the plan's trailing newlines, held back because a branding
+	// footer replaces them with its own separator
+	newlines int
+}
+
+// newPlanWriter renders the headers and footer of a ticket's plan file, so that
+// invalid branding is reported before any file is touched
+func newPlanWriter(ticket *jira.Ticket, gen *generationInfo, headerFields []string) (*planWriter, error) {
+	brandHeader, err := renderBranding("file-header", fileHeaderText, ticket)
+	if err != nil {
+		return nil, err
+	}
+	footer, err := renderBranding("file-footer", fileFooterText, ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &planWriter{header: brandHeader, footer: footer}
+	if !noHeader {
+		w.header += formatPlanHeader(ticket, gen, headerFields)
+	}
+	return w, nil
+}
+
+// start writes the headers to a newly created or truncated file
+func (w *planWriter) start(file *os.File, path string) error {
+	w.file, w.path, w.err, w.newlines = file, path, nil, 0
+	_, w.err = file.WriteString(w.header)
+	return w.err
+}
+
+// Write appends a chunk of the plan. Failures are recorded and returned by end
+// rather than interrupting a stream.
+func (w *planWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return len(p), nil
+	}
+
+	text := bytes.TrimRight(p, "\n")
+	if len(text) > 0 {
+		if _, w.err = w.file.WriteString(strings.Repeat("\n", w.newlines)); w.err == nil {
+			_, w.err = w.file.Write(text)
+		}
+		w.newlines = 0
+	}
+	w.newlines += len(p) - len(text)
+	return len(p), nil
+}
+
+// end writes the footer, marking a plan that didn't finish as incomplete, then
+// syncs and closes the file
+func (w *planWriter) end(complete bool) error {
+	trailer := strings.Repeat("\n", w.newlines)
+	switch {
+	case !complete:
+		trailer += incompleteFooter
+	case w.footer != "":
+		trailer = "\n\n" + strings.TrimRight(w.footer, "\n") + "\n"
+	}
+
+	if w.err == nil {
+		_, w.err = w.file.WriteString(trailer)
+	}
+	if w.err == nil {
+		w.err = w.file.Sync()
+	}
+	if err := w.file.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.err != nil {
+		return fmt.Errorf("failed to write file %s: %w", w.path, w.err)
+	}
+	return nil
+}
+
+// writePlanFile writes a whole plan to the file returned by create, which is only
+// called once the branding has rendered, and returns the file's path. HTML plans
+// are converted as a single document; markdown goes through a planWriter.
+func writePlanFile(create func() (*os.File, string, error), ticket *jira.Ticket, gen *generationInfo, plan string, headerFields []string) (string, error) {
+	if outputFormat == FormatHTML {
+		content := plan
+		if !noHeader {
+			content = formatPlanHeader(ticket, gen, headerFields) + plan
+		}
+		branded, err := brandPlanContent(ticket, content)
+		if err != nil {
+			return "", err
+		}
+
+		file, path, err := create()
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		if _, err := file.WriteString(formatPlanFile(ticket, branded)); err != nil {
+			return "", fmt.Errorf("failed to write file %s: %w", path, err)
+		}
+		return path, nil
+	}
+
+	w, err := newPlanWriter(ticket, gen, headerFields)
+	if err != nil {
+		return "", err
+	}
+	file, path, err := create()
+	if err != nil {
+		return "", err
+	}
+	if err := w.start(file, path); err == nil {
+		w.Write([]byte(plan))
+	}
+	return path, w.end(true)
+}
+
+// planFileStream writes a plan to the --output file as it streams in, so a long
+// plan isn't only held in memory and survives the process dying part way through.
+// A completed stream is the saved plan unless the plan is changed afterwards, e.g.
+// by --self-review or --toc.
+type planFileStream struct {
+	path     string
+	writer   *planWriter
+	complete bool
+}
+
+// newPlanFileStream returns the incremental writer for a ticket's plan, or nil
+// unless --stream is writing a markdown plan to a new --output file
+func newPlanFileStream(ticket *jira.Ticket) *planFileStream {
+	if !streamOutput || outputPath == "" || appendPlan || structuredOutput || outputFormat != FormatMarkdown {
+		return nil
+	}
+
+	// The model and token counts aren't known until the plan is complete
+	writer, err := newPlanWriter(ticket, nil, headerFields)
+	if err != nil {
+		return nil
+	}
+	return &planFileStream{path: outputPath, writer: writer}
+}
+
+// begin creates or truncates the file and writes the plan header, so each
+// attempt (e.g. a retry after an empty response) starts from a clean file
+func (s *planFileStream) begin() error {
+	s.complete = false
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	file, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", s.path, err)
+	}
+	if err := s.writer.start(file, s.path); err != nil {
+		s.writer.end(false)
+		return fmt.Errorf("failed to write file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Write appends a streamed chunk of the plan
+func (s *planFileStream) Write(p []byte) (int, error) {
+	return s.writer.Write(p)
+}
+
+// end closes the file, marking it incomplete when the stream didn't finish
+func (s *planFileStream) end(complete bool) {
+	if err := s.writer.end(complete); err != nil {
+		color.Yellow("⚠️  Warning: Failed to write the streamed plan to %s: %v", s.path, err)
+		return
+	}
+	s.complete = complete
+}
+
+// holds reports whether the file already contains the final plan: the stream
+// completed and the plan is the streamed text, unchanged
+func (s *planFileStream) holds(plan, streamed string) bool {
+	return s != nil && s.complete && plan == streamed
+}
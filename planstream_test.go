@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestPlanFileStream(t *testing.T) {
+	chunks := []string{"## Analysis\n\n", "- Retry failed ", "uploads\n", "- Log retries\n\n"}
+
+	tests := []struct {
+		name       string
+		footer     string
+		complete   bool
+		wantSuffix string
+	}{
+		{name: "complete", complete: true, wantSuffix: "- Log retries\n\n"},
+		{name: "complete with footer", footer: "Generated for {{.Ticket.Key}}", complete: true, wantSuffix: "- Log retries\n\nGenerated for DEMO-1\n"},
+		{name: "failed part way", complete: false, wantSuffix: "- Log retries\n\n" + incompleteFooter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plans", "DEMO-1.md")
+			setGlobals(t, path, tt.footer)
+			ticket := &jira.Ticket{Key: "DEMO-1", Summary: "Retry uploads"}
+
+			stream := newPlanFileStream(ticket)
+			if stream == nil {
+				t.Fatal("newPlanFileStream() = nil for a streamed --output file")
+			}
+			if err := stream.begin(); err != nil {
+				t.Fatalf("begin() error = %v", err)
+			}
+
+			// The header is written before any of the plan, and each chunk grows the file
+			header := formatPlanHeader(ticket, nil, nil)
+			if got := fileContent(t, path); got != header {
+				t.Fatalf("file after begin() = %q, want the header %q", got, header)
+			}
+			size := len(header)
+			for _, chunk := range chunks {
+				stream.Write([]byte(chunk))
+				if grown := len(fileContent(t, path)); grown <= size {
+					t.Errorf("file size after %q = %d, want more than %d", chunk, grown, size)
+				} else {
+					size = grown
+				}
+			}
+			stream.end(tt.complete)
+
+			content := fileContent(t, path)
+			if !strings.HasPrefix(content, header+"## Analysis") || !strings.HasSuffix(content, tt.wantSuffix) {
+				t.Errorf("streamed file = %q, want the header, plan and suffix %q", content, tt.wantSuffix)
+			}
+
+			// A completed stream holds the same file writePlanToFile would save
+			plan := strings.Join(chunks, "")
+			if got := stream.holds(plan, plan); got != tt.complete {
+				t.Errorf("holds() = %v, want %v", got, tt.complete)
+			}
+			if tt.complete {
+				saved := filepath.Join(filepath.Dir(path), "saved.md")
+				if _, err := writePlanToFile(saved, ticket, nil, plan, nil, false); err != nil {
+					t.Fatal(err)
+				}
+				if want := fileContent(t, saved); content != want {
+					t.Errorf("streamed file = %q, want %q as written in one go", content, want)
+				}
+			}
+		})
+	}
+}
+
+// setGlobals configures a streamed --output plan with an optional file footer
+// for the duration of a test
+func setGlobals(t *testing.T, path, footer string) {
+	oldStream, oldOutput, oldFormat, oldFooter, oldHeaderFields := streamOutput, outputPath, outputFormat, fileFooterText, headerFields
+	t.Cleanup(func() {
+		streamOutput, outputPath, outputFormat, fileFooterText, headerFields = oldStream, oldOutput, oldFormat, oldFooter, oldHeaderFields
+	})
+	streamOutput, outputPath, outputFormat, fileFooterText, headerFields = true, path, FormatMarkdown, footer, nil
+}
+
+// fileContent returns the content of the file at path
+func fileContent(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
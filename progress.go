@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// progressBarWidth is the number of cells in the batch progress bar
+const progressBarWidth = 30
+
+// showSpinners controls the per-ticket generation spinners, which are suppressed
+// in favor of the overall progress bar in batch runs on a terminal
+var showSpinners = true
+
+// progressSink displays batch progress after each ticket completes
+type progressSink interface {
+	update(done, total int, eta time.Duration, label string)
+}
+
+// batchProgress tracks how many tickets of a batch run have completed and estimates
+// the time remaining from the average time per ticket so far. The clock is injectable
+// so estimates are deterministic in tests.
+type batchProgress struct {
+	sink    progressSink
+	total   int
+	done    int
+	now     func() time.Time
+	started time.Time
+}
+
+// newBatchProgress creates a progress tracker for total tickets, starting now
+func newBatchProgress(sink progressSink, total int, now func() time.Time) *batchProgress {
+	return &batchProgress{sink: sink, total: total, now: now, started: now()}
+}
+
+// advance records a completed ticket and reports the new progress
+func (p *batchProgress) advance(label string) {
+	p.done++
+	p.sink.update(p.done, p.total, p.eta(), label)
+}
+
+// eta estimates the time remaining, or zero when unknown or complete
+func (p *batchProgress) eta() time.Duration {
+	if p.done == 0 || p.done >= p.total {
+		return 0
+	}
+	perTicket := p.now().Sub(p.started) / time.Duration(p.done)
+	return perTicket * time.Duration(p.total-p.done)
+}
+
+// newProgressSink returns a bar for terminals, or plain [n/total] lines otherwise
+func newProgressSink(out io.Writer, tty bool) progressSink {
+	if tty {
+		return &barSink{out: out}
+	}
+	return &lineSink{out: out}
+}
+
+// barSink draws a progress bar with the completed count and ETA
+type barSink struct {
+	out io.Writer
+}
+
+func (s *barSink) update(done, total int, eta time.Duration, label string) {
+	filled := 0
+	if total > 0 {
+		filled = done * progressBarWidth / total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	fmt.Fprintf(s.out, "\n%s %d/%d%s\n", bar, done, total, formatETA(eta))
+}
+
+// lineSink prints one [n/total] line per completed ticket, for logs and pipes
+type lineSink struct {
+	out io.Writer
+}
+
+func (s *lineSink) update(done, total int, eta time.Duration, label string) {
+	fmt.Fprintf(s.out, "[%d/%d] %s done%s\n", done, total, label, formatETA(eta))
+}
+
+// formatETA formats a remaining-time estimate, or nothing when it is unknown
+func formatETA(eta time.Duration) string {
+	if eta <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (ETA %s)", eta.Round(time.Second))
+}
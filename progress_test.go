@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// progressUpdate is one update received by a recordingSink
+type progressUpdate struct {
+	done, total int
+	eta         time.Duration
+	label       string
+}
+
+// recordingSink records every progress update
+type recordingSink struct {
+	updates []progressUpdate
+}
+
+func (s *recordingSink) update(done, total int, eta time.Duration, label string) {
+	s.updates = append(s.updates, progressUpdate{done, total, eta, label})
+}
+
+func TestBatchProgress(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)}
+	sink := &recordingSink{}
+	progress := newBatchProgress(sink, 4, clock.Now)
+
+	for i, took := range []time.Duration{10 * time.Second, 30 * time.Second, 20 * time.Second, 5 * time.Second} {
+		clock.advance(took)
+		progress.advance(fmt.Sprintf("RHEL-%d", i+1))
+	}
+
+	want := []progressUpdate{
+		{1, 4, 30 * time.Second, "RHEL-1"},
+		{2, 4, 40 * time.Second, "RHEL-2"},
+		{3, 4, 20 * time.Second, "RHEL-3"},
+		{4, 4, 0, "RHEL-4"},
+	}
+	if len(sink.updates) != len(want) {
+		t.Fatalf("updates = %+v, want %+v", sink.updates, want)
+	}
+	for i := range want {
+		if sink.updates[i] != want[i] {
+			t.Errorf("update %d = %+v, want %+v", i+1, sink.updates[i], want[i])
+		}
+	}
+}
+
+func TestProgressSinks(t *testing.T) {
+	tests := []struct {
+		name string
+		tty  bool
+		want string
+	}{
+		{name: "terminal bar", tty: true, want: "\n" + strings.Repeat("█", 15) + strings.Repeat("░", 15) + " 2/4 (ETA 40s)\n"},
+		{name: "plain lines", want: "[2/4] RHEL-2 done (ETA 40s)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			newProgressSink(&out, tt.tty).update(2, 4, 40*time.Second, "RHEL-2")
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
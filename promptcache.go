@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Supported --prompt-cache modes
+const (
+	PromptCacheAuto = "auto"
+	PromptCacheOn   = "on"
+	PromptCacheOff  = "off"
+)
+
+// usePromptCache is set once the run is known to be a batch, from --prompt-cache
+var usePromptCache bool
+
+// validatePromptCache checks a --prompt-cache mode, treating an empty mode as auto
+func validatePromptCache(mode string) (string, error) {
+	switch mode {
+	case "", PromptCacheAuto:
+		return PromptCacheAuto, nil
+	case PromptCacheOn, PromptCacheOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("%q must be one of %s, %s or %s", mode, PromptCacheAuto, PromptCacheOn, PromptCacheOff)
+}
+
+// promptCacheEnabled reports whether prompts are cached: always with on, never
+// with off, and for batch runs with auto, where tickets share the template prefix
+func promptCacheEnabled(mode string, batch bool) bool {
+	return mode == PromptCacheOn || (mode == PromptCacheAuto && batch)
+}
+
+// planPromptMessage builds the user message for a rendered prompt. A non-empty
+// static prefix, shared by every ticket rendered with the same template, goes in
+// its own text block marked for prompt caching, followed by the rest of the prompt.
+func planPromptMessage(promptText, static string) anthropic.MessageParam {
+	if static == "" || len(static) >= len(promptText) {
+		return anthropic.NewUserMessage(anthropic.NewTextBlock(promptText))
+	}
+
+	cached := anthropic.NewTextBlock(static)
+	cached.OfText.CacheControl = anthropic.NewCacheControlEphemeralParam()
+	return anthropic.NewUserMessage(cached, anthropic.NewTextBlock(promptText[len(static):]))
+}
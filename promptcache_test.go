@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestPlanPromptMessage(t *testing.T) {
+	prompt := "You are a senior engineer.\nWrite a plan.\n\nTicket: RHEL-1\n"
+
+	tests := []struct {
+		name       string
+		static     string
+		wantBlocks []string
+	}{
+		{name: "static prefix cached", static: "You are a senior engineer.\nWrite a plan.\n", wantBlocks: []string{"You are a senior engineer.\nWrite a plan.\n", "\nTicket: RHEL-1\n"}},
+		{name: "no static prefix", wantBlocks: []string{prompt}},
+		{name: "whole prompt static", static: prompt, wantBlocks: []string{prompt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := planPromptMessage(prompt, tt.static)
+			if len(message.Content) != len(tt.wantBlocks) {
+				t.Fatalf("message has %d blocks, want %d", len(message.Content), len(tt.wantBlocks))
+			}
+			for i, block := range message.Content {
+				if block.OfText == nil {
+					t.Fatalf("block %d is not text", i)
+				}
+				if block.OfText.Text != tt.wantBlocks[i] {
+					t.Errorf("block %d = %q, want %q", i, block.OfText.Text, tt.wantBlocks[i])
+				}
+
+				// Only the static block of a split prompt carries the cache marker
+				wantCached := len(tt.wantBlocks) > 1 && i == 0
+				if cached := block.OfText.CacheControl.Type == "ephemeral"; cached != wantCached {
+					t.Errorf("block %d cached = %v, want %v", i, cached, wantCached)
+				}
+			}
+		})
+	}
+}
+
+func TestPromptCacheEnabled(t *testing.T) {
+	tests := []struct {
+		mode  string
+		batch bool
+		want  bool
+	}{
+		{PromptCacheAuto, true, true},
+		{PromptCacheAuto, false, false},
+		{PromptCacheOn, false, true},
+		{PromptCacheOff, true, false},
+	}
+	for _, tt := range tests {
+		if got := promptCacheEnabled(tt.mode, tt.batch); got != tt.want {
+			t.Errorf("promptCacheEnabled(%q, %v) = %v, want %v", tt.mode, tt.batch, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePromptCache(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{"", PromptCacheAuto, false},
+		{PromptCacheAuto, PromptCacheAuto, false},
+		{PromptCacheOn, PromptCacheOn, false},
+		{PromptCacheOff, PromptCacheOff, false},
+		{"always", "", true},
+	}
+	for _, tt := range tests {
+		got, err := validatePromptCache(tt.mode)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("validatePromptCache(%q) = %q, %v, want %q, wantErr %v", tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Package prompts embeds the default prompt templates shipped with jig
+package prompts
+
+import _ "embed"
+
+// ImplementationPlanPOML is the default POML implementation plan template
+//
+//go:embed implementation-plan.poml
+var ImplementationPlanPOML string
+
+// StructuredPlanMarkdown is the template used with --structured, asking for the
+// plan as JSON tasks instead of prose
+//
+//go:embed structured-plan.md
+var StructuredPlanMarkdown string
+
+// ExplainMarkdown is the lightweight template used by the explain subcommand to
+// summarize a ticket without planning
+//
+//go:embed explain.md
+var ExplainMarkdown string
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// reviewPromptTemplate asks the review model to critique a plan against its ticket.
+// The ticket text and plan are substituted in order.
+const reviewPromptTemplate = `You are reviewing an implementation plan written for a Jira ticket.
+
+<ticket>
+%s
+</ticket>
+
+<plan>
+%s
+</plan>
+
+What's missing or wrong? Check the plan against the ticket's requirements and acceptance criteria,
+and list any items the plan doesn't address, steps that are incorrect, and significant risks it
+overlooks. Be concise and use a markdown bullet list. If the plan fully covers the ticket, say so
+in one sentence.`
+
+// planReviewer critiques a plan for a ticket, returning the review notes
+type planReviewer interface {
+	Review(ctx context.Context, ticket *jira.Ticket, plan string) (string, error)
+}
+
+// claudeReviewer reviews plans with a single Claude request
+type claudeReviewer struct {
+	client anthropic.Client
+	model  string
+}
+
+// Review sends the ticket and plan to the review model and returns its critique
+func (r *claudeReviewer) Review(ctx context.Context, ticket *jira.Ticket, plan string) (string, error) {
+	message, err := r.client.Messages.New(ctx, anthropic.MessageNewParams{
+		MaxTokens: 1024,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(fmt.Sprintf(reviewPromptTemplate, ticket.MarshalForPrompt(), plan))),
+		},
+		Model: anthropic.Model(r.model),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var critique strings.Builder
+	for i := range message.Content {
+		critique.WriteString(message.Content[i].Text)
+	}
+	return strings.TrimSpace(critique.String()), nil
+}
+
+// appendReviewNotes adds a review critique to the end of a plan
+func appendReviewNotes(plan, critique string) string {
+	if critique == "" {
+		return plan
+	}
+	return strings.TrimRight(plan, "\n") + "\n\n## Review Notes\n\n" + critique + "\n"
+}
+
+// selfReviewPlan runs the review pass for a plan and returns the plan with its review
+// notes appended. A failed review is reported as a warning and the plan is returned as is.
+func selfReviewPlan(ctx context.Context, reviewer planReviewer, ticket *jira.Ticket, plan string, timer *phaseTimer) string {
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Suffix = " 🔍 Reviewing the plan..."
+	if showSpinners {
+		s.Start()
+	}
+	done := timer.track(ticket.Key, "review")
+	critique, err := reviewer.Review(ctx, ticket, plan)
+	done()
+	s.Stop()
+	if err != nil {
+		color.Yellow("⚠️  Warning: Self-review failed, saving the plan without review notes: %v", err)
+		return plan
+	}
+
+	color.HiMagenta("🔍 REVIEW NOTES")
+	printSeparator()
+	printPlan(critique, renderMarkdown && isTerminal(os.Stdout))
+	printSeparator()
+
+	return appendReviewNotes(plan, critique)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// fakeReviewer returns a canned critique, recording the plan it reviewed
+type fakeReviewer struct {
+	critique string
+	err      error
+	reviewed string
+}
+
+func (r *fakeReviewer) Review(ctx context.Context, ticket *jira.Ticket, plan string) (string, error) {
+	r.reviewed = plan
+	return r.critique, r.err
+}
+
+func TestSelfReviewPlan(t *testing.T) {
+	plan := "## Steps\n\n1. Add retries\n\n"
+
+	tests := []struct {
+		name     string
+		reviewer *fakeReviewer
+		want     string
+	}{
+		{
+			name:     "critique appended",
+			reviewer: &fakeReviewer{critique: "- Missing: logging of retries"},
+			want:     "## Steps\n\n1. Add retries\n\n## Review Notes\n\n- Missing: logging of retries\n",
+		},
+		{name: "empty critique", reviewer: &fakeReviewer{}, want: plan},
+		{name: "review error keeps the plan", reviewer: &fakeReviewer{err: errors.New("model overloaded")}, want: plan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := &jira.Ticket{Key: "RHEL-1", Summary: "Retry uploads"}
+			timer := newPhaseTimer(time.Now)
+
+			got := selfReviewPlan(context.Background(), tt.reviewer, ticket, plan, timer)
+			if got != tt.want {
+				t.Errorf("selfReviewPlan() = %q, want %q", got, tt.want)
+			}
+			if tt.reviewer.reviewed != plan {
+				t.Errorf("reviewed plan = %q, want %q", tt.reviewer.reviewed, plan)
+			}
+			if phases := formatPhases(timer.totals()); !strings.HasPrefix(phases, "review ") {
+				t.Errorf("timed phases = %q, want the review phase", phases)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// shortPlanNudge follows a suspiciously short plan when retrying with --min-plan-chars or --min-plan-lines
+const shortPlanNudge = `That response is too short to be an implementation plan (%s). Please write the complete
+implementation plan for the ticket, covering every section the original request asked for.`
+
+// shortPlanTemperatureStep is how much the temperature is raised when retrying a
+// short plan, so the retry is less likely to repeat it. Temperatures are capped at 1.
+const shortPlanTemperatureStep = 0.2
+
+// shortPlanReason describes why a plan is shorter than the configured minimum
+// number of characters or non-blank lines, or returns an empty string if it is
+// long enough. A minimum of zero or less disables that check.
+func shortPlanReason(plan string, minChars, minLines int) string {
+	if chars := len([]rune(strings.TrimSpace(plan))); minChars > 0 && chars < minChars {
+		return fmt.Sprintf("%d characters, below the minimum of %d", chars, minChars)
+	}
+
+	lines := 0
+	for _, line := range strings.Split(plan, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines++
+		}
+	}
+	if minLines > 0 && lines < minLines {
+		return fmt.Sprintf("%d lines, below the minimum of %d", lines, minLines)
+	}
+
+	return ""
+}
+
+// shortPlanRetryParams continues the conversation after a short plan, asking for
+// the complete plan at a slightly higher temperature than the original request,
+// whose temperature defaults to the API's 1.0
+func shortPlanRetryParams(params anthropic.MessageNewParams, message *anthropic.Message, reason string) anthropic.MessageNewParams {
+	retry := params
+	retry.Messages = append(append([]anthropic.MessageParam{}, params.Messages...),
+		message.ToParam(),
+		anthropic.NewUserMessage(anthropic.NewTextBlock(fmt.Sprintf(shortPlanNudge, reason))),
+	)
+
+	temperature := DefaultTemperature
+	if params.Temperature.Valid() {
+		temperature = min(params.Temperature.Value+shortPlanTemperatureStep, 1)
+	}
+	retry.Temperature = anthropic.Float(temperature)
+	return retry
+}
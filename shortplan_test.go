@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestShortPlanReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     string
+		minChars int
+		minLines int
+		want     string
+	}{
+		{"long enough", "## Plan\n\nStep one\nStep two", 10, 3, ""},
+		{"too few characters", "  Short  ", 10, 0, "5 characters, below the minimum of 10"},
+		{"too few lines", "One\n\n\nTwo\n", 0, 3, "2 lines, below the minimum of 3"},
+		{"checks disabled", "", 0, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := shortPlanReason(tt.plan, tt.minChars, tt.minLines); got != tt.want {
+			t.Errorf("%s: shortPlanReason() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShortPlanRetryParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		temperature *float64
+		want        float64
+	}{
+		{name: "unset defaults to 1.0", want: 1},
+		{name: "raised", temperature: ptr(0.5), want: 0.7},
+		{name: "capped at 1", temperature: ptr(0.9), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := anthropic.MessageNewParams{
+				Messages: []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Plan RHEL-1"))},
+			}
+			if tt.temperature != nil {
+				params.Temperature = anthropic.Float(*tt.temperature)
+			}
+			message := &anthropic.Message{Role: "assistant"}
+
+			retry := shortPlanRetryParams(params, message, "3 lines, below the minimum of 10")
+
+			if got := retry.Temperature.Value; !retry.Temperature.Valid() || got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("retry temperature = %v, want %v", got, tt.want)
+			}
+			if len(retry.Messages) != 3 {
+				t.Fatalf("retry has %d messages, want the prompt, the short plan and the nudge", len(retry.Messages))
+			}
+			if nudge := retry.Messages[2].Content[0].OfText.Text; !strings.Contains(nudge, "3 lines, below the minimum of 10") {
+				t.Errorf("nudge = %q, want it to give the reason", nudge)
+			}
+			if len(params.Messages) != 1 {
+				t.Errorf("original params were modified: %d messages", len(params.Messages))
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
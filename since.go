@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// orderByPattern matches the ORDER BY clause of a JQL query
+	orderByPattern = regexp.MustCompile(`(?i)\s+order\s+by\s+`)
+
+	// sinceDaysPattern matches a --since duration in days, e.g. "7d"
+	sinceDaysPattern = regexp.MustCompile(`^(\d+)d$`)
+)
+
+// sinceDateLayouts are the absolute --since formats, as accepted by JQL
+var sinceDateLayouts = []string{"2006-01-02 15:04", "2006-01-02"}
+
+// sinceClause converts a --since value into a JQL "updated >=" clause. The value is
+// either a duration such as "24h", "90m" or "7d", or a date such as "2025-01-31" or
+// "2025-01-31 09:00" in the Jira user's time zone.
+func sinceClause(since string) (string, error) {
+	since = strings.TrimSpace(since)
+
+	for _, layout := range sinceDateLayouts {
+		if _, err := time.Parse(layout, since); err == nil {
+			return fmt.Sprintf(`updated >= "%s"`, since), nil
+		}
+	}
+
+	var duration time.Duration
+	if m := sinceDaysPattern.FindStringSubmatch(since); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil {
+			return "", fmt.Errorf("invalid number of days %q", m[1])
+		}
+		duration = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if duration, err = time.ParseDuration(since); err != nil {
+			return "", fmt.Errorf("%q is not a duration (e.g. 24h, 7d) or date (e.g. 2025-01-31)", since)
+		}
+	}
+	if duration <= 0 {
+		return "", fmt.Errorf("duration %q must be positive", since)
+	}
+
+	// JQL relative dates have minute precision; round up so nothing is missed
+	minutes := int64((duration + time.Minute - 1) / time.Minute)
+	return fmt.Sprintf(`updated >= "-%dm"`, minutes), nil
+}
+
+// assigneeMeClause restricts a JQL query to tickets assigned to the authenticated user
+const assigneeMeClause = "assignee = currentUser()"
+
+// addJQLClause combines a clause with a JQL query using AND, keeping any
+// ORDER BY clause at the end
+func addJQLClause(query, clause string) string {
+	query = strings.TrimSpace(query)
+	order := ""
+	if start := orderByIndex(query); start >= 0 {
+		query, order = strings.TrimSpace(query[:start]), strings.TrimSpace(query[start:])
+	}
+
+	if query == "" {
+		query = clause
+	} else {
+		query = fmt.Sprintf("(%s) AND %s", query, clause)
+	}
+
+	if order != "" {
+		return query + " " + order
+	}
+	return query
+}
+
+// orderByIndex returns the offset of the query's ORDER BY clause, or -1 if it has
+// none. Text in quoted strings, such as summary ~ "sort order by date", is skipped.
+func orderByIndex(query string) int {
+	// The pattern needs leading whitespace, so match against the query with a space prepended
+	padded := " " + query
+	for _, loc := range orderByPattern.FindAllStringIndex(padded, -1) {
+		if !inJQLString(padded[:loc[0]]) {
+			return max(loc[0]-1, 0)
+		}
+	}
+	return -1
+}
+
+// inJQLString reports whether the end of prefix is inside a quoted JQL string,
+// honoring backslash escapes
+func inJQLString(prefix string) bool {
+	var quote rune
+	escaped := false
+	for _, r := range prefix {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		}
+	}
+	return quote != 0
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestAddJQLClause(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "project", query: "project = RHEL", want: "(project = RHEL) AND assignee = currentUser()"},
+		{name: "or kept together", query: "project = A OR project = B", want: "(project = A OR project = B) AND assignee = currentUser()"},
+		{name: "order by kept last", query: "project = RHEL ORDER BY priority DESC", want: "(project = RHEL) AND assignee = currentUser() ORDER BY priority DESC"},
+		{name: "lowercase order by", query: "project = RHEL order by created", want: "(project = RHEL) AND assignee = currentUser() order by created"},
+		{name: "order by in a string", query: `summary ~ "sort order by date"`, want: `(summary ~ "sort order by date") AND assignee = currentUser()`},
+		{name: "only order by", query: "ORDER BY updated", want: "assignee = currentUser() ORDER BY updated"},
+		{name: "empty", want: "assignee = currentUser()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addJQLClause(tt.query, assigneeMeClause); got != tt.want {
+				t.Errorf("addJQLClause(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSinceClause(t *testing.T) {
+	tests := []struct {
+		name    string
+		since   string
+		want    string
+		wantErr bool
+	}{
+		{name: "hours", since: "24h", want: `updated >= "-1440m"`},
+		{name: "minutes", since: "90m", want: `updated >= "-90m"`},
+		{name: "days", since: "7d", want: `updated >= "-10080m"`},
+		{name: "seconds round up", since: "30s", want: `updated >= "-1m"`},
+		{name: "date", since: "2025-01-31", want: `updated >= "2025-01-31"`},
+		{name: "date and time", since: " 2025-01-31 09:00 ", want: `updated >= "2025-01-31 09:00"`},
+		{name: "zero", since: "0h", wantErr: true},
+		{name: "negative", since: "-24h", wantErr: true},
+		{name: "invalid date", since: "2025-02-30", wantErr: true},
+		{name: "unknown unit", since: "2w", wantErr: true},
+		{name: "empty", since: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sinceClause(tt.since)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sinceClause(%q) error = %v, wantErr %v", tt.since, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sinceClause(%q) = %q, want %q", tt.since, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSinceJQL(t *testing.T) {
+	clause, err := sinceClause("24h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `(project = RHEL AND status = New) AND updated >= "-1440m" ORDER BY updated DESC`
+	if got := addJQLClause("project = RHEL AND status = New ORDER BY updated DESC", clause); got != want {
+		t.Errorf("JQL = %q, want %q", got, want)
+	}
+}
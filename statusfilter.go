@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// skippedTicket is a fetched ticket left out of a run by --skip-closed or --status
+type skippedTicket struct {
+	Ticket *jira.Ticket
+	Reason string
+}
+
+// filterTicketsByStatus drops closed tickets when skipClosed is set and, when
+// statuses is non-empty, tickets whose status name isn't in it (ignoring case).
+// It returns the tickets to plan and the skipped ones with the reason for each.
+func filterTicketsByStatus(tickets []*jira.Ticket, skipClosed bool, statuses []string) ([]*jira.Ticket, []skippedTicket) {
+	allowed := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		if status = strings.TrimSpace(status); status != "" {
+			allowed[strings.ToLower(status)] = true
+		}
+	}
+
+	var kept []*jira.Ticket
+	var skipped []skippedTicket
+	for _, ticket := range tickets {
+		switch {
+		case skipClosed && ticket.IsClosed():
+			skipped = append(skipped, skippedTicket{Ticket: ticket, Reason: fmt.Sprintf("closed (%s)", ticket.Status.Name)})
+		case len(allowed) > 0 && !allowed[strings.ToLower(ticket.Status.Name)]:
+			skipped = append(skipped, skippedTicket{Ticket: ticket, Reason: fmt.Sprintf("status %s is not in --status", ticket.Status.Name)})
+		default:
+			kept = append(kept, ticket)
+		}
+	}
+	return kept, skipped
+}
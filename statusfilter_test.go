@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestFilterTicketsByStatus(t *testing.T) {
+	tickets := []*jira.Ticket{
+		{Key: "A-1", Status: jira.Status{Name: "To Do", Category: jira.StatusCategoryNew}},
+		{Key: "A-2", Status: jira.Status{Name: "In Progress", Category: jira.StatusCategoryInProgress}},
+		{Key: "A-3", Status: jira.Status{Name: "Verified", Category: jira.StatusCategoryDone}},
+		{Key: "A-4", Status: jira.Status{Name: "Code Review", Category: jira.StatusCategoryInProgress}},
+	}
+
+	tests := []struct {
+		name        string
+		skipClosed  bool
+		statuses    []string
+		wantKept    []string
+		wantSkipped []string
+	}{
+		{name: "no filter", wantKept: []string{"A-1", "A-2", "A-3", "A-4"}},
+		{name: "skip closed", skipClosed: true, wantKept: []string{"A-1", "A-2", "A-4"}, wantSkipped: []string{"A-3"}},
+		{name: "status allowlist ignoring case", statuses: []string{"to do", " In Progress "}, wantKept: []string{"A-1", "A-2"}, wantSkipped: []string{"A-3", "A-4"}},
+		{name: "closed status in the allowlist", skipClosed: true, statuses: []string{"Verified", "To Do"}, wantKept: []string{"A-1"}, wantSkipped: []string{"A-2", "A-3", "A-4"}},
+		{name: "empty statuses ignored", statuses: []string{"", " "}, wantKept: []string{"A-1", "A-2", "A-3", "A-4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, skipped := filterTicketsByStatus(tickets, tt.skipClosed, tt.statuses)
+
+			var keptKeys, skippedKeys []string
+			for _, ticket := range kept {
+				keptKeys = append(keptKeys, ticket.Key)
+			}
+			for _, skip := range skipped {
+				skippedKeys = append(skippedKeys, skip.Ticket.Key)
+				if skip.Reason == "" {
+					t.Errorf("%s skipped without a reason", skip.Ticket.Key)
+				}
+			}
+			if !slices.Equal(keptKeys, tt.wantKept) {
+				t.Errorf("kept = %v, want %v", keptKeys, tt.wantKept)
+			}
+			if !slices.Equal(skippedKeys, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", skippedKeys, tt.wantSkipped)
+			}
+		})
+	}
+}
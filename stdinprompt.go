@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+// stdinTemplatePath is the --template value that reads the prompt from stdin
+const stdinTemplatePath = "-"
+
+// maxStdinPromptBytes caps the prompt read from stdin with --template -
+const maxStdinPromptBytes = 10 << 20
+
+// readStdinPrompt reads the whole prompt piped to --template -, rejecting an empty one
+func readStdinPrompt(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxStdinPromptBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt from stdin: %w", err)
+	}
+	if len(data) > maxStdinPromptBytes {
+		return "", fmt.Errorf("prompt on stdin is larger than %d bytes", maxStdinPromptBytes)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("no prompt on stdin")
+	}
+	return string(data), nil
+}
+
+// renderStdinPrompt returns the stdin prompt for a ticket: verbatim, or executed as
+// a Go template with the ticket's template data when asTemplate is set
+func renderStdinPrompt(text string, ticket *jira.Ticket, asTemplate bool, opts ...prompt.RenderOption) (string, error) {
+	if !asTemplate {
+		return text, nil
+	}
+	return prompt.RenderString("stdin", text, ticket, opts...)
+}
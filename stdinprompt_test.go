@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/prompt"
+)
+
+func TestReadStdinPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{name: "verbatim", stdin: "Plan {{.Summary}}\n\n  keep spacing  \n", want: "Plan {{.Summary}}\n\n  keep spacing  \n"},
+		{name: "empty", wantErr: true},
+		{name: "too large", stdin: strings.Repeat("a", maxStdinPromptBytes+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readStdinPrompt(strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readStdinPrompt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readStdinPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStdinPrompt(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		asTemplate bool
+		want       string
+		wantErr    bool
+	}{
+		{name: "verbatim", text: "Plan {{.Summary}}", want: "Plan {{.Summary}}"},
+		{name: "as template", text: "Plan {{.Ticket.Key}}: {{.Summary}}", asTemplate: true, want: "Plan DEMO-123: Add retry support to the export job"},
+		{name: "invalid template", text: "Plan {{.Summary", asTemplate: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderStdinPrompt(tt.text, prompt.SampleTicket(), tt.asTemplate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderStdinPrompt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderStdinPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
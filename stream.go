@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// exitInterrupted is the conventional exit code for a run stopped by SIGINT
+const exitInterrupted = 130
+
+// interruptContext returns a context cancelled on the first Ctrl-C so an in-flight
+// generation can stop and save its partial output. A second Ctrl-C exits immediately.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// streamPlan generates a plan with a streaming request, printing text as it arrives
+// and copying it to sink when set. The returned message accumulates everything
+// received, so it holds the partial plan when the stream is interrupted or fails
+// part way through.
+func streamPlan(ctx context.Context, client anthropic.Client, params anthropic.MessageNewParams, sink io.Writer) (*anthropic.Message, error) {
+	stream := client.Messages.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	message := &anthropic.Message{}
+	for stream.Next() {
+		event := stream.Current()
+		if err := message.Accumulate(event); err != nil {
+			return message, fmt.Errorf("failed to read streamed response: %w", err)
+		}
+
+		if delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
+			if text, ok := delta.Delta.AsAny().(anthropic.TextDelta); ok {
+				fmt.Print(text.Text)
+				if sink != nil {
+					io.WriteString(sink, text.Text)
+				}
+			}
+		}
+	}
+
+	return message, stream.Err()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+func TestInterruptContext(t *testing.T) {
+	ctx, stop := interruptContext()
+	defer stop()
+
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := self.Signal(os.Interrupt); err != nil {
+		t.Skipf("can't send an interrupt on this platform: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by Ctrl-C")
+	}
+}
+
+func TestSaveInterruptedPlan(t *testing.T) {
+	oldNoHeader, oldFormat := noHeader, outputFormat
+	t.Cleanup(func() { noHeader, outputFormat = oldNoHeader, oldFormat })
+	noHeader, outputFormat = false, FormatMarkdown
+
+	ticket := &jira.Ticket{Key: "RHEL-9", Summary: "Retry uploads"}
+	partial := "## Analysis\n\nThe export job gives up after"
+	dir := filepath.Join(t.TempDir(), "plans")
+
+	path, err := saveImplementationPlan(ticket.Key, ticket, &generationInfo{Interrupted: true}, partial, dir, defaultHeaderFields)
+	if err != nil {
+		t.Fatalf("saveImplementationPlan() error = %v", err)
+	}
+	content := fileContent(t, path)
+	if !strings.Contains(content, "# Implementation Plan: Retry uploads [interrupted]") {
+		t.Errorf("saved plan = %q, want the title marked interrupted", content)
+	}
+	if !strings.Contains(content, partial) {
+		t.Errorf("saved plan = %q, want the partial plan %q", content, partial)
+	}
+}
+
+func TestPlanFileStreamInterrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "RHEL-9.md")
+	setGlobals(t, path, "")
+	ticket := &jira.Ticket{Key: "RHEL-9", Summary: "Retry uploads"}
+
+	// The stream is cut off after the first chunk
+	stream := newPlanFileStream(ticket)
+	if err := stream.begin(); err != nil {
+		t.Fatalf("begin() error = %v", err)
+	}
+	stream.Write([]byte("## Analysis\n\nThe export job gives up after"))
+	stream.end(false)
+
+	content := fileContent(t, path)
+	if !strings.Contains(content, "The export job gives up after") || !strings.HasSuffix(content, incompleteFooter) {
+		t.Errorf("streamed file = %q, want the partial plan marked incomplete", content)
+	}
+	if stream.holds("The export job gives up after", "The export job gives up after") {
+		t.Error("holds() = true for an interrupted stream, want the partial plan saved again")
+	}
+}
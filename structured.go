@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/fatih/color"
+	"github.com/joshbranham/jira-implementation-generator/pkg/jira"
+)
+
+// structuredNudge asks the model to correct a response that wasn't valid plan JSON.
+// The parse error is substituted.
+const structuredNudge = `Your previous response could not be used: %v.
+Return valid JSON only, matching the schema from my first message, with no markdown code fences and no other text.`
+
+// structuredPlan is the plan requested with --structured. The model provides the
+// summary, tasks and risks; the ticket, model and generation time are added when saving.
+type structuredPlan struct {
+	Ticket    string           `json:"ticket,omitempty"`
+	Model     string           `json:"model,omitempty"`
+	Generated time.Time        `json:"generated,omitzero"`
+	Summary   string           `json:"summary"`
+	Tasks     []structuredTask `json:"tasks"`
+	Risks     []string         `json:"risks,omitempty"`
+}
+
+// structuredTask is a single task of a structured plan
+type structuredTask struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Estimate    string `json:"estimate"`
+}
+
+// messageSender sends a request to the model, e.g. client.Messages.New
+type messageSender func(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error)
+
+// parseStructuredPlan decodes and validates a structured plan from a response,
+// tolerating a surrounding markdown code fence or text around the JSON object
+func parseStructuredPlan(text string) (*structuredPlan, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("the response contains no JSON object")
+	}
+
+	var plan structuredPlan
+	if err := json.Unmarshal([]byte(text[start:end+1]), &plan); err != nil {
+		return nil, fmt.Errorf("the response is not valid JSON: %w", err)
+	}
+
+	if len(plan.Tasks) == 0 {
+		return nil, errors.New(`the plan has no "tasks"`)
+	}
+	for i, task := range plan.Tasks {
+		if strings.TrimSpace(task.Title) == "" {
+			return nil, fmt.Errorf(`task %d has no "title"`, i+1)
+		}
+	}
+	return &plan, nil
+}
+
+// requestStructuredPlan parses the structured plan from a response to params. If
+// it can't be parsed, the model is asked once more for valid JSON, continuing the
+// conversation with the parse error. It returns the plan and the response it came from.
+func requestStructuredPlan(ctx context.Context, send messageSender, params anthropic.MessageNewParams, message *anthropic.Message) (*structuredPlan, *anthropic.Message, error) {
+	plan, err := parseStructuredPlan(messageText(message))
+	if err == nil {
+		return plan, message, nil
+	}
+
+	color.Yellow("⚠️  Warning: %v; asking for valid JSON", err)
+	retry := params
+	retry.Messages = append(append([]anthropic.MessageParam{}, params.Messages...),
+		message.ToParam(),
+		anthropic.NewUserMessage(anthropic.NewTextBlock(fmt.Sprintf(structuredNudge, err))),
+	)
+
+	message, sendErr := send(ctx, retry)
+	if sendErr != nil {
+		return nil, nil, sendErr
+	}
+	plan, err = parseStructuredPlan(messageText(message))
+	if err != nil {
+		return nil, nil, fmt.Errorf("no valid structured plan after retrying: %w", err)
+	}
+	return plan, message, nil
+}
+
+// formatStructuredPlan encodes a structured plan as indented JSON, stamped with
+// the ticket and generation details
+func formatStructuredPlan(plan *structuredPlan, ticket *jira.Ticket, gen *generationInfo) (string, error) {
+	stamped := *plan
+	stamped.Ticket = ticket.Key
+	stamped.Generated = time.Now()
+	if gen != nil {
+		stamped.Model = gen.Model
+	}
+
+	data, err := json.MarshalIndent(stamped, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode structured plan: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// saveStructuredPlan writes a structured plan's JSON to path, or to a timestamped
+// .json file in dir when path is empty, and returns the file's path
+func saveStructuredPlan(ticketID, content, dir, path string) (string, error) {
+	var file *os.File
+	var err error
+	if path != "" {
+		if parent := filepath.Dir(path); parent != "." {
+			if err := os.MkdirAll(parent, 0755); err != nil {
+				return "", fmt.Errorf("failed to create directory %s: %w", parent, err)
+			}
+		}
+		if file, err = os.Create(path); err != nil {
+			return "", fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+	} else {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		timestamp := time.Now().Format("20060102_150405")
+		if file, path, err = createUniqueFile(dir, fmt.Sprintf("%s_%s", ticketID, timestamp), ".json"); err != nil {
+			return "", err
+		}
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return "", fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+
+	color.Green("\n💾 Structured plan saved to: %s", path)
+	return path, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const validStructuredJSON = `{"summary":"Retry uploads","tasks":[{"title":"Add backoff","description":"Wrap the upload","estimate":"2h"}],"risks":["Duplicate uploads"]}`
+
+// textMessage returns an assistant response with one text block per text
+func textMessage(texts ...string) *anthropic.Message {
+	message := &anthropic.Message{Role: "assistant"}
+	for _, text := range texts {
+		message.Content = append(message.Content, anthropic.ContentBlockUnion{Type: "text", Text: text})
+	}
+	return message
+}
+
+func TestRequestStructuredPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		first       string
+		replies     []string
+		sendErr     error
+		wantSummary string
+		wantSent    int
+		wantErr     string
+	}{
+		{name: "valid JSON", first: validStructuredJSON, wantSummary: "Retry uploads"},
+		{name: "fenced JSON", first: "```json\n" + validStructuredJSON + "\n```", wantSummary: "Retry uploads"},
+		{name: "valid after one retry", first: "Here is the plan: tasks TBD", replies: []string{validStructuredJSON}, wantSummary: "Retry uploads", wantSent: 1},
+		{name: "invalid twice", first: `{"summary":"x"}`, replies: []string{`{"tasks":[{"title":""}]}`}, wantSent: 1, wantErr: "no valid structured plan after retrying"},
+		{name: "retry fails", first: "not JSON", sendErr: errors.New("overloaded"), wantSent: 1, wantErr: "overloaded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := anthropic.MessageNewParams{
+				Messages: []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Plan RHEL-1 as JSON"))},
+			}
+			var sent []anthropic.MessageNewParams
+			send := func(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+				sent = append(sent, params)
+				if tt.sendErr != nil {
+					return nil, tt.sendErr
+				}
+				return textMessage(tt.replies[len(sent)-1]), nil
+			}
+
+			first := textMessage(tt.first)
+			plan, message, err := requestStructuredPlan(context.Background(), send, params, first)
+
+			if len(sent) != tt.wantSent {
+				t.Fatalf("sent %d requests, want %d", len(sent), tt.wantSent)
+			}
+			if tt.wantSent > 0 {
+				// The retry continues the conversation with the parse error
+				retry := sent[0].Messages
+				if len(retry) != 3 || !strings.Contains(retry[2].Content[0].OfText.Text, "Return valid JSON only") {
+					t.Errorf("retry messages = %+v, want the prompt, the reply and the nudge", retry)
+				}
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("requestStructuredPlan() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requestStructuredPlan() error = %v", err)
+			}
+			if plan.Summary != tt.wantSummary || len(plan.Tasks) != 1 || plan.Tasks[0].Title != "Add backoff" {
+				t.Errorf("plan = %+v, want the parsed plan", plan)
+			}
+			if (message == first) != (tt.wantSent == 0) {
+				t.Errorf("returned the first response = %v, want %v", message == first, tt.wantSent == 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readTicketsFile reads ticket IDs from a file with one ID per line, skipping
+// blank lines and lines starting with #. Trailing # comments are also removed,
+// e.g. "RHEL-12345  # flaky test".
+func readTicketsFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tickets file: %w", err)
+	}
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if id := strings.TrimSpace(line); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tickets file %s: %w", path, err)
+	}
+	return ids, nil
+}
+
+// mergeTicketIDs combines ticket ID lists in order, keeping the first occurrence of each ID
+func mergeTicketIDs(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, list := range lists {
+		for _, id := range list {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
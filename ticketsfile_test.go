@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadTicketsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tickets.txt")
+	content := "# Sprint 42 batch\n" +
+		"RHEL-1\n" +
+		"\n" +
+		"  RHEL-2  \n" +
+		"\t# RHEL-3 is blocked\n" +
+		"RHEL-4 # flaky test\n" +
+		"   \n" +
+		"RHEL-5\r\n" +
+		"RHEL-6"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := readTicketsFile(path)
+	if err != nil {
+		t.Fatalf("readTicketsFile() error = %v", err)
+	}
+	if want := []string{"RHEL-1", "RHEL-2", "RHEL-4", "RHEL-5", "RHEL-6"}; !slices.Equal(ids, want) {
+		t.Errorf("readTicketsFile() = %q, want %q", ids, want)
+	}
+}
+
+func TestReadTicketsFileMissing(t *testing.T) {
+	if _, err := readTicketsFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("readTicketsFile() error = nil, want an error for a missing file")
+	}
+}
+
+func TestMergeTicketIDs(t *testing.T) {
+	got := mergeTicketIDs([]string{"RHEL-1", "RHEL-2"}, nil, []string{"RHEL-2", "RHEL-3", "RHEL-1"})
+	if want := []string{"RHEL-1", "RHEL-2", "RHEL-3"}; !slices.Equal(got, want) {
+		t.Errorf("mergeTicketIDs() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// phaseEntry is the duration of one phase of a run, for a ticket or (with an
+// empty Ticket) for the run as a whole, e.g. a JQL search
+type phaseEntry struct {
+	Ticket   string
+	Phase    string
+	Duration time.Duration
+}
+
+// phaseTimer collects how long the fetch, render and generate phases of a run take.
+// The clock is injectable so durations are deterministic in tests.
+type phaseTimer struct {
+	now     func() time.Time
+	started time.Time
+	entries []phaseEntry
+}
+
+// newPhaseTimer creates a timer whose total starts now
+func newPhaseTimer(now func() time.Time) *phaseTimer {
+	return &phaseTimer{now: now, started: now()}
+}
+
+// track starts timing a phase and returns a function that records it when called
+func (t *phaseTimer) track(ticket, phase string) func() {
+	start := t.now()
+	return func() {
+		t.entries = append(t.entries, phaseEntry{Ticket: ticket, Phase: phase, Duration: t.now().Sub(start)})
+	}
+}
+
+// total returns the time elapsed since the timer was created
+func (t *phaseTimer) total() time.Duration {
+	return t.now().Sub(t.started)
+}
+
+// totals sums the recorded durations by phase, in the order phases were first recorded
+func (t *phaseTimer) totals() []phaseEntry {
+	return sumPhases(t.entries)
+}
+
+// ticketTotals sums the recorded durations by phase for each ticket, in the order
+// tickets were first recorded. Run-wide entries are excluded.
+func (t *phaseTimer) ticketTotals() map[string][]phaseEntry {
+	byTicket := make(map[string][]phaseEntry)
+	for _, entry := range t.entries {
+		if entry.Ticket != "" {
+			byTicket[entry.Ticket] = append(byTicket[entry.Ticket], entry)
+		}
+	}
+	for ticket, entries := range byTicket {
+		byTicket[ticket] = sumPhases(entries)
+	}
+	return byTicket
+}
+
+// tickets returns the tickets with recorded phases, in the order they were first recorded
+func (t *phaseTimer) tickets() []string {
+	seen := make(map[string]bool)
+	var tickets []string
+	for _, entry := range t.entries {
+		if entry.Ticket != "" && !seen[entry.Ticket] {
+			seen[entry.Ticket] = true
+			tickets = append(tickets, entry.Ticket)
+		}
+	}
+	return tickets
+}
+
+// sumPhases sums durations by phase, keeping the order phases first appear in
+func sumPhases(entries []phaseEntry) []phaseEntry {
+	index := make(map[string]int)
+	var sums []phaseEntry
+	for _, entry := range entries {
+		i, ok := index[entry.Phase]
+		if !ok {
+			i = len(sums)
+			index[entry.Phase] = i
+			sums = append(sums, phaseEntry{Phase: entry.Phase})
+		}
+		sums[i].Duration += entry.Duration
+	}
+	return sums
+}
+
+// formatPhases formats phase durations as "fetch 1.2s, render 3ms, ..."
+func formatPhases(phases []phaseEntry) string {
+	parts := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		parts = append(parts, fmt.Sprintf("%s %s", phase.Phase, phase.Duration.Round(time.Millisecond)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printTimingSummary prints how long each phase took, with per-ticket lines in batch mode
+func printTimingSummary(timer *phaseTimer, batch bool) {
+	color.HiWhite("\n⏱️  Timing:")
+	if batch {
+		byTicket := timer.ticketTotals()
+		for _, ticket := range timer.tickets() {
+			color.White("   %s: %s", ticket, formatPhases(byTicket[ticket]))
+		}
+	}
+
+	summary := formatPhases(timer.totals())
+	if summary != "" {
+		summary += ", "
+	}
+	color.Cyan("   %stotal %s", summary, timer.total().Round(time.Millisecond))
+}
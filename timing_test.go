@@ -0,0 +1,65 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestPhaseTimer(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)}
+	timer := newPhaseTimer(clock.Now)
+
+	steps := []struct {
+		ticket string
+		phase  string
+		took   time.Duration
+	}{
+		{"", "search", 400 * time.Millisecond},
+		{"RHEL-1", "fetch", 1200 * time.Millisecond},
+		{"RHEL-1", "render", 3 * time.Millisecond},
+		{"RHEL-1", "generate", 20 * time.Second},
+		{"RHEL-2", "fetch", 800 * time.Millisecond},
+		{"RHEL-2", "render", 2 * time.Millisecond},
+		{"RHEL-2", "generate", 15 * time.Second},
+	}
+	for _, step := range steps {
+		done := timer.track(step.ticket, step.phase)
+		clock.advance(step.took)
+		done()
+	}
+	clock.advance(100 * time.Millisecond)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"run totals", formatPhases(timer.totals()), "search 400ms, fetch 2s, render 5ms, generate 35s"},
+		{"RHEL-1", formatPhases(timer.ticketTotals()["RHEL-1"]), "fetch 1.2s, render 3ms, generate 20s"},
+		{"RHEL-2", formatPhases(timer.ticketTotals()["RHEL-2"]), "fetch 800ms, render 2ms, generate 15s"},
+		{"total", timer.total().String(), "37.505s"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if got, want := timer.tickets(), []string{"RHEL-1", "RHEL-2"}; !slices.Equal(got, want) {
+		t.Errorf("tickets() = %v, want %v", got, want)
+	}
+}